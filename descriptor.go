@@ -0,0 +1,43 @@
+package goa
+
+type (
+	// FieldDescriptor describes a field of a struct generated by goagen so that runtime code
+	// may introspect the struct without using reflection.
+	// goagen generates a "<Type>Schema" variable listing the descriptors of the fields of each
+	// struct.
+	FieldDescriptor struct {
+		// GoName is the name of the struct field.
+		GoName string
+		// JSONName is the name of the field in JSON documents.
+		JSONName string
+		// Kind is the name of the JSON type of the field: "boolean", "integer", "number",
+		// "string", "any", "array", "object" or "hash".
+		Kind string
+		// Required is true if the field is required.
+		Required bool
+		// Validations lists the validations that apply to the field values.
+		Validations []FieldValidation
+	}
+
+	// FieldValidation describes a validation that applies to the values of a field.
+	FieldValidation struct {
+		// Name is the name of the validation: "enum", "format", "pattern", "minimum",
+		// "maximum", "minLength", "maxLength" or "uniqueItems".
+		Name string
+		// Value is the validation argument: the []interface{} of accepted values for "enum",
+		// a string for "format" and "pattern", a float64 for "minimum" and "maximum", an int
+		// for "minLength" and "maxLength" and true for "uniqueItems".
+		Value interface{}
+	}
+)
+
+// Validation returns the argument of the validation with the given name. The second value is
+// false if no such validation applies to the field.
+func (d FieldDescriptor) Validation(name string) (interface{}, bool) {
+	for _, v := range d.Validations {
+		if v.Name == name {
+			return v.Value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,30 @@
+package goa_test
+
+import (
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FieldDescriptor", func() {
+	desc := goa.FieldDescriptor{
+		GoName:   "Age",
+		JSONName: "age",
+		Kind:     "integer",
+		Validations: []goa.FieldValidation{
+			{Name: "minimum", Value: float64(0)},
+			{Name: "maximum", Value: float64(120)},
+		},
+	}
+
+	It("returns the argument of a validation", func() {
+		max, ok := desc.Validation("maximum")
+		Ω(ok).Should(BeTrue())
+		Ω(max).Should(Equal(float64(120)))
+	})
+
+	It("reports missing validations", func() {
+		_, ok := desc.Validation("pattern")
+		Ω(ok).Should(BeFalse())
+	})
+})
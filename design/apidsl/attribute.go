@@ -17,7 +17,7 @@ import (
 // attributes may include other attributes. At the basic level an attribute has a name,
 // a type and optionally a default value and validation rules. The type of an attribute can be one of:
 //
-// * The primitive types Boolean, Integer, Number, DateTime, UUID or String.
+// * The primitive types Boolean, Integer, Number, DateTime, UUID, Language or String.
 //
 // * A type defined via the Type function.
 //
@@ -334,6 +334,7 @@ func Enum(val ...interface{}) {
 // Format DSL.
 var SupportedValidationFormats = []string{
 	"cidr",
+	"color",
 	"date-time",
 	"email",
 	"hostname",
@@ -364,6 +365,8 @@ var SupportedValidationFormats = []string{
 // "cidr": RFC4632 or RFC4291 CIDR notation IP address
 //
 // "regexp": RE2 regular expression
+//
+// "color": hexadecimal #RGB, #RGBA, #RRGGBB or #RRGGBBAA color
 func Format(f string) {
 	if a, ok := attributeDefinition(); ok {
 		if a.Type != nil && a.Type.Kind() != design.StringKind {
@@ -499,6 +502,34 @@ func MaxLength(val int) {
 	}
 }
 
+// UniqueItems adds a "uniqueItems" validation to the attribute. The attribute must be an array
+// whose elements are primitive values other than Any. The generated Validate method returns an
+// error if two elements are equal, the UnmarshalJSON method produced by
+// codegen.GoUniqueUnmarshaler removes the duplicate elements when decoding.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor49.
+func UniqueItems() {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && !isComparableArray(a.Type) {
+			incompatibleAttributeType("unique items", a.Type.Name(), "an array of primitive values")
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.UniqueItems = true
+		}
+	}
+}
+
+// isComparableArray returns true if t is an array whose elements can be compared for equality.
+func isComparableArray(t design.DataType) bool {
+	arr := t.ToArray()
+	if arr == nil || arr.ElemType == nil || arr.ElemType.Type == nil {
+		return false
+	}
+	elem := arr.ElemType.Type
+	return elem.IsPrimitive() && elem.Kind() != design.AnyKind
+}
+
 // Required adds a "required" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor61.
 func Required(names ...string) {
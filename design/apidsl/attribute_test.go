@@ -253,6 +253,33 @@ var _ = Describe("Attribute", func() {
 		})
 	})
 
+	Context("with a name, type array of strings and a DSL defining a unique items validation", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = ArrayOf(String)
+			dsl = func() { UniqueItems() }
+		})
+
+		It("produces an attribute with a unique items validation", func() {
+			o := parent.Type.(Object)
+			Ω(o).Should(HaveKey(name))
+			Ω(o[name].Validation).ShouldNot(BeNil())
+			Ω(o[name].Validation.UniqueItems).Should(BeTrue())
+		})
+	})
+
+	Context("with a name, type string and a DSL defining a unique items validation", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dataType = String
+			dsl = func() { UniqueItems() }
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
 	Context("with a name, type integer, a description and a DSL defining an enum validation", func() {
 		BeforeEach(func() {
 			name = "foo"
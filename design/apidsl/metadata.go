@@ -26,6 +26,138 @@ import (
 //        Metadata("struct:tag:json", "myName,omitempty")
 //        Metadata("struct:tag:xml", "myName,attr")
 //
+// `struct:field:readonly`, `struct:field:writeonly`: flag media type attributes that only appear
+// in responses, respectively requests, when generating separate request and response types.
+// Applicable to attributes only.
+//
+//        Metadata("struct:field:readonly")
+//
+// `struct:field:build`: restricts the Go struct field to the builds satisfying the given build
+// constraint. The struct is generated in two files, one with the field and one without.
+// Applicable to attributes only.
+//
+//        Metadata("struct:field:build", "debug")
+//
+// `stream:sse`: flags actions that stream their responses as server-sent events. goagen generates
+// the event types and the functions that write them as "data" frames.
+// Applicable to actions only.
+//
+//        Metadata("stream:sse")
+//
+// `struct:field:transform`: lists the transforms applied to the value of a string attribute after
+// it is decoded. The built-in transforms are "trim", "lower" and "upper", other values are names
+// of custom transforms registered with goa.RegisterTransform.
+// Applicable to attributes only.
+//
+//        Metadata("struct:field:transform", "trim", "lower")
+//
+// `struct:discriminator`: describes a family of types made of a base type and derived types. Set
+// on the base type the value is the name of the string attribute holding the discriminator, set on
+// a derived type the value is the discriminator of the type. The derived Go structs embed the base
+// struct and implement a Type method returning the discriminator.
+// Applicable to user types only.
+//
+//        Metadata("struct:discriminator", "kind") // on the base type
+//        Metadata("struct:discriminator", "dog")  // on a derived type
+//
+// `struct:field:channel`: renders the Go struct field as a channel of the attribute type. The
+// field is tagged with "-" so that the form, JSON and XML encoders skip it.
+// `struct:field:channel:dir` restricts the channel direction to "recv" or
+// "send". Meant for internal pipeline types only.
+// Applicable to attributes only.
+//
+//        Metadata("struct:field:channel")
+//        Metadata("struct:field:channel:dir", "recv")
+//
+// `param:split`: accepts the elements of an array query string parameter as a single delimited
+// value, e.g. "tags=a,b,c", instead of repeated keys. The delimiter defaults to ",".
+// Applicable to action params only.
+//
+//        Metadata("param:split")
+//        Metadata("param:split", ";")
+//
+// `struct:field:sqlnull`: renders the public Go struct field of an optional string, integer,
+// number or boolean attribute as goa.NullString, goa.NullInt64, goa.NullFloat64 or goa.NullBool
+// instead of a pointer. The field can be scanned from database rows and is encoded as null in JSON
+// when not valid.
+// Applicable to optional attributes with no default value only.
+//
+//        Metadata("struct:field:sqlnull")
+//
+// `struct:field:pointer`: sets the number of pointer indirections of the generated Go struct field,
+// e.g. "2" renders a string attribute as **string. Overrides the default pointer rules of public
+// structs, the fields of private structs are always pointers. Valid values are "0", "1" and "2".
+// Applicable to primitive attributes only.
+//
+//        Metadata("struct:field:pointer", "2")
+//
+// `struct:field:intern`: interns the values of the Go struct field generated for a string or array
+// of strings attribute with goa.Intern when decoding JSON so that equal values share their backing
+// memory. The intern table is bounded and evicts the least recently used values, see
+// goa.SetInternCapacity.
+// Applicable to attributes only.
+//
+//        Metadata("struct:field:intern")
+//
+// `multipart:file`: flags a string attribute of a multipart/form-data request type as holding the
+// path to a file. The generated WriteMultipart method writes the content of the file as a file part
+// instead of writing the path as a form field.
+// Applicable to attributes only.
+//
+//        Metadata("multipart:file")
+//
+// `struct:field:duration`: renders the Go struct field as a duration. The value selects the JSON
+// encoding: "string" uses goa.DurationString (e.g. "1m30s") and "seconds" uses goa.DurationSeconds
+// (e.g. 90). Defaults to "string". Use with string attributes for the former and integer or number
+// attributes for the latter. The attribute cannot define validations or a default value.
+// Applicable to attributes only.
+//
+//        Metadata("struct:field:duration", "seconds")
+//
+// `validation:exclusive:xxx`: defines the group xxx of mutually exclusive attributes of an object,
+// the generated Validate method fails if more than one of the attributes is set.
+// `validation:atleastone:xxx` defines a group of attributes at least one of which must be set.
+// Defining both on the same attributes requires exactly one of them to be set.
+// Applicable to types, media types and attributes only.
+//
+//        Metadata("validation:exclusive:contact", "email", "phone")
+//        Metadata("validation:atleastone:contact", "email", "phone")
+//
+// `collection:paginated`: flags a collection media type as returned by cursor paginated
+// endpoints, goagen generates a page type that wraps the collection with the next page cursor.
+// Applicable to collection media types only.
+//
+//        CollectionOf(BottleMedia, func() {
+//                Metadata("collection:paginated")
+//        })
+//
+// `struct:method:xxx`: maps the method xxx of an interface the generated struct must implement
+// to the attribute it returns, goagen generates the method returning the value of the field.
+// Applicable to types and media types only.
+//
+//        Metadata("struct:method:GetID", "id")
+//
+// `struct:field:id`: renders the struct field generated for a string or integer attribute with
+// the given distinct identifier type so that identifiers of different entities cannot be mixed up.
+// The type is encoded in JSON as the underlying scalar.
+// Applicable to string and integer attributes only.
+//
+//        Metadata("struct:field:id", "UserID")
+//
+// `struct:field:atomic`: renders the struct field generated for an integer or boolean attribute
+// with the atomic.Int64 or atomic.Bool type so that it may be accessed concurrently (requires Go
+// 1.19 or later). goagen generates the accessors and the JSON encoding of the field.
+// Applicable to integer and boolean attributes only.
+//
+//        Metadata("struct:field:atomic")
+//
+// `struct:field:lazy`: renders the struct field generated for an attribute whose value is
+// expensive to compute as a cached value computed on demand by a provider function set on the
+// struct. goagen generates the accessor and the JSON encoding of the field.
+// Applicable to attributes only.
+//
+//        Metadata("struct:field:lazy")
+//
 // `swagger:generate`: specifies whether Swagger specification should be generated. Defaults to
 // true.
 // Applicable to resources, actions and file servers.
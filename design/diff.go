@@ -0,0 +1,203 @@
+package design
+
+import "sort"
+
+const (
+	// FieldAdded is the kind of changes that add an attribute to an object.
+	FieldAdded ChangeKind = iota + 1
+	// FieldRemoved is the kind of changes that remove an attribute from an object.
+	FieldRemoved
+	// FieldMadeRequired is the kind of changes that make an optional attribute required.
+	FieldMadeRequired
+	// FieldMadeOptional is the kind of changes that make a required attribute optional.
+	FieldMadeOptional
+	// TypeWidened is the kind of changes that replace the type of an attribute with a type that
+	// accepts all the values of the previous type, e.g. integer to number.
+	TypeWidened
+	// TypeNarrowed is the kind of changes that replace the type of an attribute with a type that
+	// accepts only some of the values of the previous type, e.g. number to integer.
+	TypeNarrowed
+	// TypeChanged is the kind of changes that replace the type of an attribute with an
+	// incompatible type, e.g. string to integer.
+	TypeChanged
+)
+
+type (
+	// ChangeKind enumerates the kinds of changes computed by Diff.
+	ChangeKind int
+
+	// Change describes a single difference between two versions of an attribute.
+	Change struct {
+		// Path is the path to the changed attribute, e.g. "address.street" or "tags[]".
+		Path string
+		// Kind is the kind of change.
+		Kind ChangeKind
+		// Required is true if the added or removed attribute is required.
+		Required bool
+		// From is the type of the attribute before the change, nil if the attribute was added.
+		From DataType
+		// To is the type of the attribute after the change, nil if the attribute was removed.
+		To DataType
+	}
+
+	// differ implements recursive and cycle safe comparison of attributes.
+	differ struct {
+		seen    map[[2]*AttributeDefinition]bool
+		changes []Change
+	}
+)
+
+// Diff returns the changes that turn the attribute from into the attribute to. Object attributes
+// are compared recursively, array elements and hash values are compared using the "[]" path
+// suffix and hash keys using the "[key]" path suffix. The changes are sorted by path.
+func Diff(from, to *AttributeDefinition) []Change {
+	d := &differ{seen: make(map[[2]*AttributeDefinition]bool)}
+	d.diff("", from, to)
+	sort.Stable(byPath(d.changes))
+	return d.changes
+}
+
+// IsBreaking returns true if any of the given changes is breaking.
+func IsBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.IsBreaking() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBreaking returns true if the change may break existing clients: removing or adding a required
+// attribute, making an optional attribute required and narrowing or changing an attribute type
+// are breaking. Adding or removing an optional attribute, making a required attribute optional
+// and widening an attribute type are not.
+func (c Change) IsBreaking() bool {
+	switch c.Kind {
+	case FieldAdded, FieldRemoved:
+		return c.Required
+	case FieldMadeRequired, TypeNarrowed, TypeChanged:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns a human readable description of the change kind.
+func (k ChangeKind) String() string {
+	switch k {
+	case FieldAdded:
+		return "field added"
+	case FieldRemoved:
+		return "field removed"
+	case FieldMadeRequired:
+		return "field made required"
+	case FieldMadeOptional:
+		return "field made optional"
+	case TypeWidened:
+		return "type widened"
+	case TypeNarrowed:
+		return "type narrowed"
+	case TypeChanged:
+		return "type changed"
+	default:
+		return "unknown change"
+	}
+}
+
+// diff records the changes between the attributes from and to rooted at path.
+func (d *differ) diff(path string, from, to *AttributeDefinition) {
+	from, to = userTypeAttribute(from), userTypeAttribute(to)
+	key := [2]*AttributeDefinition{from, to}
+	if d.seen[key] {
+		return
+	}
+	d.seen[key] = true
+
+	if kind, ok := typeChange(from.Type, to.Type); ok {
+		d.changes = append(d.changes, Change{Path: path, Kind: kind, From: from.Type, To: to.Type})
+		return
+	}
+	switch {
+	case from.Type.IsObject():
+		d.diffObject(path, from, to)
+	case from.Type.IsArray():
+		d.diff(path+"[]", from.Type.ToArray().ElemType, to.Type.ToArray().ElemType)
+	case from.Type.IsHash():
+		fh, th := from.Type.ToHash(), to.Type.ToHash()
+		d.diff(path+"[key]", fh.KeyType, th.KeyType)
+		d.diff(path+"[]", fh.ElemType, th.ElemType)
+	}
+}
+
+// diffObject records the changes between the object attributes from and to rooted at path.
+func (d *differ) diffObject(path string, from, to *AttributeDefinition) {
+	fobj, tobj := from.Type.ToObject(), to.Type.ToObject()
+	prefix := path
+	if prefix != "" {
+		prefix += "."
+	}
+	fobj.IterateAttributes(func(n string, fatt *AttributeDefinition) error {
+		p := prefix + n
+		tatt, ok := tobj[n]
+		if !ok {
+			d.changes = append(d.changes, Change{Path: p, Kind: FieldRemoved, Required: from.IsRequired(n), From: fatt.Type})
+			return nil
+		}
+		freq, treq := from.IsRequired(n), to.IsRequired(n)
+		if !freq && treq {
+			d.changes = append(d.changes, Change{Path: p, Kind: FieldMadeRequired, From: fatt.Type, To: tatt.Type})
+		} else if freq && !treq {
+			d.changes = append(d.changes, Change{Path: p, Kind: FieldMadeOptional, From: fatt.Type, To: tatt.Type})
+		}
+		d.diff(p, fatt, tatt)
+		return nil
+	})
+	tobj.IterateAttributes(func(n string, tatt *AttributeDefinition) error {
+		if _, ok := fobj[n]; !ok {
+			d.changes = append(d.changes, Change{Path: prefix + n, Kind: FieldAdded, Required: to.IsRequired(n), To: tatt.Type})
+		}
+		return nil
+	})
+}
+
+// userTypeAttribute returns the attribute that defines the user or media type of att if any, att
+// otherwise.
+func userTypeAttribute(att *AttributeDefinition) *AttributeDefinition {
+	for {
+		switch actual := att.Type.(type) {
+		case *UserTypeDefinition:
+			att = actual.AttributeDefinition
+		case *MediaTypeDefinition:
+			att = actual.AttributeDefinition
+		default:
+			return att
+		}
+	}
+}
+
+// typeChange returns the kind of change between the types from and to. The second value is false
+// if both types have the same kind and should be compared structurally.
+func typeChange(from, to DataType) (ChangeKind, bool) {
+	fk, tk := from.Kind(), to.Kind()
+	switch {
+	case fk == tk:
+		return 0, false
+	case tk == AnyKind:
+		return TypeWidened, true
+	case fk == AnyKind:
+		return TypeNarrowed, true
+	case fk == IntegerKind && tk == NumberKind:
+		return TypeWidened, true
+	case fk == NumberKind && tk == IntegerKind:
+		return TypeNarrowed, true
+	default:
+		return TypeChanged, true
+	}
+}
+
+// byPath implements sort.Interface to sort changes by path.
+type byPath []Change
+
+func (b byPath) Len() int           { return len(b) }
+func (b byPath) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byPath) Less(i, j int) bool { return b[i].Path < b[j].Path }
@@ -0,0 +1,197 @@
+package design_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff", func() {
+	var from, to *AttributeDefinition
+	var changes []Change
+
+	BeforeEach(func() {
+		from = &AttributeDefinition{
+			Type: Object{
+				"id":    &AttributeDefinition{Type: Integer},
+				"name":  &AttributeDefinition{Type: String},
+				"score": &AttributeDefinition{Type: Integer},
+				"tags":  &AttributeDefinition{Type: &Array{ElemType: &AttributeDefinition{Type: String}}},
+			},
+			Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
+		}
+		to = DupAtt(from)
+		to.Type = Dup(from.Type)
+	})
+
+	JustBeforeEach(func() {
+		changes = Diff(from, to)
+	})
+
+	Context("with identical attributes", func() {
+		It("returns no change", func() {
+			Ω(changes).Should(BeEmpty())
+			Ω(IsBreaking(changes)).Should(BeFalse())
+		})
+	})
+
+	Context("with a removed required field", func() {
+		BeforeEach(func() {
+			delete(to.Type.ToObject(), "id")
+			to.Validation.Required = nil
+		})
+
+		It("classifies the change as breaking", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Path).Should(Equal("id"))
+			Ω(changes[0].Kind).Should(Equal(FieldRemoved))
+			Ω(changes[0].IsBreaking()).Should(BeTrue())
+			Ω(IsBreaking(changes)).Should(BeTrue())
+		})
+	})
+
+	Context("with a removed optional field", func() {
+		BeforeEach(func() {
+			delete(to.Type.ToObject(), "name")
+		})
+
+		It("classifies the change as non-breaking", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Kind).Should(Equal(FieldRemoved))
+			Ω(changes[0].IsBreaking()).Should(BeFalse())
+		})
+	})
+
+	Context("with an added optional field", func() {
+		BeforeEach(func() {
+			to.Type.ToObject()["email"] = &AttributeDefinition{Type: String}
+		})
+
+		It("classifies the change as non-breaking", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Path).Should(Equal("email"))
+			Ω(changes[0].Kind).Should(Equal(FieldAdded))
+			Ω(IsBreaking(changes)).Should(BeFalse())
+		})
+	})
+
+	Context("with an added required field", func() {
+		BeforeEach(func() {
+			to.Type.ToObject()["email"] = &AttributeDefinition{Type: String}
+			to.Validation.Required = append(to.Validation.Required, "email")
+		})
+
+		It("classifies the change as breaking", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Kind).Should(Equal(FieldAdded))
+			Ω(changes[0].Required).Should(BeTrue())
+			Ω(IsBreaking(changes)).Should(BeTrue())
+		})
+	})
+
+	Context("with an optional field made required", func() {
+		BeforeEach(func() {
+			to.Validation.Required = append(to.Validation.Required, "name")
+		})
+
+		It("classifies the change as breaking", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Kind).Should(Equal(FieldMadeRequired))
+			Ω(IsBreaking(changes)).Should(BeTrue())
+		})
+	})
+
+	Context("with a required field made optional", func() {
+		BeforeEach(func() {
+			to.Validation.Required = nil
+		})
+
+		It("classifies the change as non-breaking", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Kind).Should(Equal(FieldMadeOptional))
+			Ω(IsBreaking(changes)).Should(BeFalse())
+		})
+	})
+
+	Context("with a widened type", func() {
+		BeforeEach(func() {
+			to.Type.ToObject()["score"] = &AttributeDefinition{Type: Number}
+		})
+
+		It("classifies the change as non-breaking", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Kind).Should(Equal(TypeWidened))
+			Ω(changes[0].From).Should(Equal(Integer))
+			Ω(changes[0].To).Should(Equal(Number))
+			Ω(IsBreaking(changes)).Should(BeFalse())
+		})
+	})
+
+	Context("with a narrowed type", func() {
+		BeforeEach(func() {
+			from.Type.ToObject()["score"] = &AttributeDefinition{Type: Number}
+		})
+
+		It("classifies the change as breaking", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Kind).Should(Equal(TypeNarrowed))
+			Ω(IsBreaking(changes)).Should(BeTrue())
+		})
+	})
+
+	Context("with an incompatible array element type", func() {
+		BeforeEach(func() {
+			to.Type.ToObject()["tags"] = &AttributeDefinition{Type: &Array{ElemType: &AttributeDefinition{Type: Integer}}}
+		})
+
+		It("classifies the change as breaking", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Path).Should(Equal("tags[]"))
+			Ω(changes[0].Kind).Should(Equal(TypeChanged))
+			Ω(IsBreaking(changes)).Should(BeTrue())
+		})
+	})
+
+	Context("with an incompatible hash key type", func() {
+		BeforeEach(func() {
+			hash := func(key DataType) *AttributeDefinition {
+				return &AttributeDefinition{Type: &Hash{
+					KeyType:  &AttributeDefinition{Type: key},
+					ElemType: &AttributeDefinition{Type: String},
+				}}
+			}
+			from.Type.ToObject()["labels"] = hash(String)
+			to.Type.ToObject()["labels"] = hash(Integer)
+		})
+
+		It("classifies the change as breaking", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Path).Should(Equal("labels[key]"))
+			Ω(changes[0].Kind).Should(Equal(TypeChanged))
+			Ω(IsBreaking(changes)).Should(BeTrue())
+		})
+	})
+
+	Context("with changes in nested user types", func() {
+		BeforeEach(func() {
+			address := func(required ...string) *UserTypeDefinition {
+				return &UserTypeDefinition{
+					TypeName: "address",
+					AttributeDefinition: &AttributeDefinition{
+						Type:       Object{"street": &AttributeDefinition{Type: String}},
+						Validation: &dslengine.ValidationDefinition{Required: required},
+					},
+				}
+			}
+			from.Type.ToObject()["address"] = &AttributeDefinition{Type: address()}
+			to.Type.ToObject()["address"] = &AttributeDefinition{Type: address("street")}
+		})
+
+		It("reports the nested changes", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Path).Should(Equal("address.street"))
+			Ω(changes[0].Kind).Should(Equal(FieldMadeRequired))
+		})
+	})
+})
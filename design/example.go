@@ -166,6 +166,7 @@ func (eg *exampleGenerator) generateFormatExample() interface{} {
 		}(),
 		"cidr":   "192.168.100.14/24",
 		"regexp": eg.r.faker.Characters(3) + ".*",
+		"color":  fmt.Sprintf("#%06x", eg.r.Int()%0x1000000),
 	}[format]; ok {
 		return res
 	}
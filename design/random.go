@@ -63,6 +63,14 @@ func (r *RandomGenerator) UUID() uuid.UUID {
 	return uuid.NewV4()
 }
 
+// languageTags lists the language tags used to produce random Language values.
+var languageTags = []string{"en", "en-US", "en-GB", "fr-FR", "de-DE", "es-419", "ja-JP", "zh-Hant-TW"}
+
+// Language produces a random BCP 47 language tag.
+func (r *RandomGenerator) Language() string {
+	return languageTags[r.rand.Intn(len(languageTags))]
+}
+
 // Bool produces a random boolean.
 func (r *RandomGenerator) Bool() bool {
 	return r.rand.Int()%2 == 0
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"mime"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -163,6 +164,8 @@ const (
 	UserTypeKind
 	// MediaTypeKind represents a media type.
 	MediaTypeKind
+	// LanguageKind represents a JSON string that is parsed as a Go language.Tag
+	LanguageKind
 )
 
 const (
@@ -188,6 +191,10 @@ const (
 
 	// Any is the type for an arbitrary JSON value (interface{} in Go).
 	Any = Primitive(AnyKind)
+
+	// Language is the type for a JSON string parsed as a Go language.Tag
+	// Language expects a BCP 47 formatted language tag, e.g. "en-US".
+	Language = Primitive(LanguageKind)
 )
 
 // DataType implementation
@@ -204,7 +211,7 @@ func (p Primitive) Name() string {
 		return "integer"
 	case Number:
 		return "number"
-	case String, DateTime, UUID:
+	case String, DateTime, UUID, Language:
 		return "string"
 	case Any:
 		return "any"
@@ -248,7 +255,7 @@ func (p Primitive) CanHaveDefault() (ok bool) {
 
 // IsCompatible returns true if val is compatible with p.
 func (p Primitive) IsCompatible(val interface{}) bool {
-	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID && p != Any {
+	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID && p != Language && p != Any {
 		panic("unknown primitive type") // bug
 	}
 	if p == Any {
@@ -273,10 +280,17 @@ func (p Primitive) IsCompatible(val interface{}) bool {
 			_, err := uuid.FromString(val.(string))
 			return err == nil
 		}
+		if p == Language {
+			return languageTagRegex.MatchString(val.(string))
+		}
 	}
 	return false
 }
 
+// languageTagRegex matches well-formed BCP 47 language tags: a primary language subtag followed
+// by optional script, region, variant, extension and private use subtags.
+var languageTagRegex = regexp.MustCompile(`^(?i:[a-z]{2,8}(-[a-z]{4})?(-([a-z]{2}|[0-9]{3}))?(-([a-z0-9]{5,8}|[0-9][a-z0-9]{3}))*(-[0-9a-wyz](-[a-z0-9]{2,8})+)*(-x(-[a-z0-9]{1,8})+)?|x(-[a-z0-9]{1,8})+)$`)
+
 var anyPrimitive = []Primitive{Boolean, Integer, Number, DateTime, UUID}
 
 // GenerateExample returns an instance of the given data type.
@@ -294,6 +308,8 @@ func (p Primitive) GenerateExample(r *RandomGenerator, seen []string) interface{
 		return r.DateTime()
 	case UUID:
 		return r.UUID()
+	case Language:
+		return r.Language()
 	case Any:
 		// to not make it too complicated, pick one of the primitive types
 		return anyPrimitive[r.Int()%len(anyPrimitive)].GenerateExample(r, seen)
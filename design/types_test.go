@@ -3,6 +3,7 @@ package design_test
 import (
 	"errors"
 	"mime"
+	"regexp"
 	"sync"
 
 	. "github.com/goadesign/goa/design"
@@ -74,6 +75,74 @@ var _ = Describe("IsObject", func() {
 	})
 })
 
+var _ = Describe("IsCompatible", func() {
+	var dt DataType
+	var val interface{}
+	var compatible bool
+
+	JustBeforeEach(func() {
+		compatible = dt.IsCompatible(val)
+	})
+
+	Context("with a language", func() {
+		BeforeEach(func() {
+			dt = Language
+		})
+
+		Context("and a valid language tag", func() {
+			BeforeEach(func() {
+				val = "zh-Hant-TW"
+			})
+
+			It("returns true", func() {
+				Ω(compatible).Should(BeTrue())
+			})
+		})
+
+		Context("and a malformed language tag", func() {
+			BeforeEach(func() {
+				val = "en_US"
+			})
+
+			It("returns false", func() {
+				Ω(compatible).Should(BeFalse())
+			})
+		})
+
+		Context("and a generated example", func() {
+			BeforeEach(func() {
+				val = Language.GenerateExample(NewRandomGenerator("language"), nil)
+			})
+
+			It("returns true", func() {
+				Ω(compatible).Should(BeTrue())
+			})
+		})
+	})
+})
+
+var _ = Describe("GenerateExample", func() {
+	Context("given a string attribute with the color format", func() {
+		var att *AttributeDefinition
+
+		BeforeEach(func() {
+			att = &AttributeDefinition{
+				Type:       String,
+				Validation: &dslengine.ValidationDefinition{Format: "color"},
+			}
+		})
+
+		It("generates valid hex colors", func() {
+			color := regexp.MustCompile(`^#[0-9a-f]{6}$`)
+			for _, seed := range []string{"a", "b", "c"} {
+				example := att.GenerateExample(NewRandomGenerator(seed), nil)
+				Ω(example).Should(BeAssignableToTypeOf(""))
+				Ω(color.MatchString(example.(string))).Should(BeTrue())
+			}
+		})
+	})
+})
+
 var _ = Describe("Project", func() {
 	var mt *MediaTypeDefinition
 	var view string
@@ -460,8 +460,72 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 			verr.Add(parent, "%sdefault value %#v is not one of the accepted values: %#v", ctx, a.DefaultValue, a.Validation.Values)
 		}
 	}
+	if depth, ok := a.Metadata["struct:field:pointer"]; ok {
+		if len(depth) == 0 || (depth[0] != "0" && depth[0] != "1" && depth[0] != "2") {
+			verr.Add(parent, `%sinvalid "struct:field:pointer" metadata %v, must be 0, 1 or 2`, ctx, depth)
+		} else if !a.Type.IsPrimitive() {
+			verr.Add(parent, `%s"struct:field:pointer" metadata applies to primitive attributes only`, ctx)
+		}
+	}
+	if _, ok := a.Metadata["struct:field:atomic"]; ok {
+		if k := a.Type.Kind(); k != IntegerKind && k != BooleanKind {
+			verr.Add(parent, `%s"struct:field:atomic" metadata applies to integer and boolean attributes only`, ctx)
+		}
+	}
+	if id, ok := a.Metadata["struct:field:id"]; ok {
+		if len(id) == 0 || id[0] == "" {
+			verr.Add(parent, `%s"struct:field:id" metadata must define the name of the identifier type`, ctx)
+		} else if k := a.Type.Kind(); k != StringKind && k != IntegerKind {
+			verr.Add(parent, `%s"struct:field:id" metadata applies to string and integer attributes only`, ctx)
+		}
+	}
+	if mode, ok := a.Metadata["struct:field:duration"]; ok {
+		encoding := "string"
+		if len(mode) > 0 && mode[0] != "" {
+			encoding = mode[0]
+		}
+		k := a.Type.Kind()
+		switch {
+		case encoding != "string" && encoding != "seconds":
+			verr.Add(parent, `%sinvalid "struct:field:duration" metadata %v, must be "string" or "seconds"`, ctx, mode)
+		case encoding == "string" && k != StringKind:
+			verr.Add(parent, `%s"struct:field:duration" metadata with the "string" encoding applies to string attributes only`, ctx)
+		case encoding == "seconds" && k != IntegerKind && k != NumberKind:
+			verr.Add(parent, `%s"struct:field:duration" metadata with the "seconds" encoding applies to integer and number attributes only`, ctx)
+		}
+		// The generated fields hold goa durations, the validations and default values of the
+		// attribute apply to strings or numbers.
+		if v := a.Validation; v != nil && (!v.HasRequiredOnly() || v.MinLength != nil) {
+			verr.Add(parent, `%s"struct:field:duration" metadata cannot be combined with validations`, ctx)
+		}
+		if a.DefaultValue != nil {
+			verr.Add(parent, `%s"struct:field:duration" metadata cannot be combined with a default value`, ctx)
+		}
+	}
+	if _, ok := a.Metadata["struct:field:sqlnull"]; ok {
+		switch a.Type.Kind() {
+		case StringKind, IntegerKind, NumberKind, BooleanKind:
+		default:
+			verr.Add(parent, `%s"struct:field:sqlnull" metadata applies to string, integer, number and boolean attributes only`, ctx)
+		}
+	}
 	o := a.Type.ToObject()
 	if o != nil {
+		for k, names := range a.Metadata {
+			if !strings.HasPrefix(k, "validation:exclusive:") && !strings.HasPrefix(k, "validation:atleastone:") {
+				continue
+			}
+			for _, n := range names {
+				if _, ok := o[n]; !ok {
+					verr.Add(parent, `%s%q metadata lists unknown attribute %q`, ctx, k, n)
+				}
+			}
+		}
+		for n, att := range o {
+			if _, ok := att.Metadata["struct:field:sqlnull"]; ok && (a.IsRequired(n) || a.HasDefaultValue(n) || a.IsNonZero(n)) {
+				verr.Add(parent, `%sfield %s: "struct:field:sqlnull" metadata applies to optional attributes with no default value only`, ctx, n)
+			}
+		}
 		for _, n := range a.AllRequired() {
 			found := false
 			for an := range o {
@@ -478,11 +542,16 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 			ctx = fmt.Sprintf("field %s", n)
 			verr.Merge(att.Validate(ctx, parent))
 		}
-	} else {
-		if a.Type.IsArray() {
-			elemType := a.Type.ToArray().ElemType
-			verr.Merge(elemType.Validate(ctx, a))
+	} else if a.Type.IsArray() {
+		elemType := a.Type.ToArray().ElemType
+		verr.Merge(elemType.Validate(ctx, a))
+	} else if h := a.Type.ToHash(); h != nil {
+		// Go map keys must be comparable which excludes slices and maps.
+		if h.KeyType.Type.IsArray() || h.KeyType.Type.IsHash() {
+			verr.Add(parent, "%shash key type %s is not comparable", ctx, h.KeyType.Type.Name())
 		}
+		verr.Merge(h.KeyType.Validate(ctx, a))
+		verr.Merge(h.ElemType.Validate(ctx, a))
 	}
 
 	return verr.AsError()
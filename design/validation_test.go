@@ -62,6 +62,209 @@ var _ = Describe("Validation", func() {
 			})
 		})
 
+		Context("with a nested hash attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, HashOf(String, HashOf(String, Integer)))
+				}
+			})
+
+			It("does not produce an error", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with a nested hash attribute using array keys", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, HashOf(String, HashOf(ArrayOf(String), Integer)))
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("hash key type array is not comparable"))
+			})
+		})
+
+		Context("with an invalid pointer depth", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Metadata("struct:field:pointer", "3")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid "struct:field:pointer" metadata`))
+			})
+		})
+
+		Context("with a pointer depth on a non primitive attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, ArrayOf(String), func() {
+						Metadata("struct:field:pointer", "0")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`"struct:field:pointer" metadata applies to primitive attributes only`))
+			})
+		})
+
+		Context("with an attribute group listing an unknown attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String)
+					Metadata("validation:exclusive:contact", attName, "phone")
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`"validation:exclusive:contact" metadata lists unknown attribute "phone"`))
+			})
+		})
+
+		Context("with a required sql null attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Metadata("struct:field:sqlnull")
+					})
+					Required(attName)
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`"struct:field:sqlnull" metadata applies to optional attributes with no default value only`))
+			})
+		})
+
+		Context("with an unknown duration encoding", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Metadata("struct:field:duration", "minutes")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid "struct:field:duration" metadata [minutes]`))
+			})
+		})
+
+		Context("with a duration in seconds on a string attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Metadata("struct:field:duration", "seconds")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`"seconds" encoding applies to integer and number attributes only`))
+			})
+		})
+
+		Context("with a validated duration", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Metadata("struct:field:duration")
+						Pattern("^[0-9]+s$")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`"struct:field:duration" metadata cannot be combined with validations`))
+			})
+		})
+
+		Context("with a duration in seconds", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, Number, func() {
+						Metadata("struct:field:duration", "seconds")
+					})
+				}
+			})
+
+			It("does not produce an error", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with a valid pointer depth", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Metadata("struct:field:pointer", "2")
+					})
+				}
+			})
+
+			It("does not produce an error", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with an identifier type on a non scalar attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, ArrayOf(String), func() {
+						Metadata("struct:field:id", "UserID")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`"struct:field:id" metadata applies to string and integer attributes only`))
+			})
+		})
+
+		Context("with an atomic string attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Metadata("struct:field:atomic")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`"struct:field:atomic" metadata applies to integer and boolean attributes only`))
+			})
+		})
+
+		Context("with a valid identifier type", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Metadata("struct:field:id", "UserID")
+					})
+				}
+			})
+
+			It("does not produce an error", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
 		Context("with a default value that doesn't exist in enum", func() {
 			BeforeEach(func() {
 				dsl = func() {
@@ -0,0 +1,60 @@
+package goa
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// The Duration types are time.Duration values with a JSON encoding suited to clients that do not
+// agree on how durations are represented. encoding/json always encodes a time.Duration as an
+// integer number of nanoseconds, the types carry the encoding so that it applies wherever the
+// value is used (struct fields, slices, maps) without generating MarshalJSON methods for the
+// enclosing structs. Use the Duration methods or a conversion to get the time.Duration value.
+// goagen uses these types for attributes that define the "struct:field:duration" metadata.
+type (
+	// DurationString is a time.Duration encoded as a Go duration string, e.g. "1m30s".
+	DurationString time.Duration
+
+	// DurationSeconds is a time.Duration encoded as a JSON number of seconds, e.g. 90.5.
+	DurationSeconds time.Duration
+)
+
+// Duration returns the time.Duration value of d.
+func (d DurationString) Duration() time.Duration { return time.Duration(d) }
+
+// MarshalJSON encodes the duration as a Go duration string.
+func (d DurationString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON decodes a JSON string holding a Go duration.
+func (d *DurationString) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = DurationString(v)
+	return nil
+}
+
+// Duration returns the time.Duration value of d.
+func (d DurationSeconds) Duration() time.Duration { return time.Duration(d) }
+
+// MarshalJSON encodes the duration as a number of seconds.
+func (d DurationSeconds) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).Seconds())
+}
+
+// UnmarshalJSON decodes a JSON number of seconds.
+func (d *DurationSeconds) UnmarshalJSON(data []byte) error {
+	var s float64
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*d = DurationSeconds(s * float64(time.Second))
+	return nil
+}
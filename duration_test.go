@@ -0,0 +1,50 @@
+package goa_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Duration types", func() {
+	type record struct {
+		Str goa.DurationString  `json:"str"`
+		Sec goa.DurationSeconds `json:"sec"`
+	}
+	rec := record{
+		Str: goa.DurationString(90 * time.Second),
+		Sec: goa.DurationSeconds(1500 * time.Millisecond),
+	}
+	const encoded = `{"str":"1m30s","sec":1.5}`
+
+	It("encodes durations as strings and numbers of seconds", func() {
+		b, err := json.Marshal(rec)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(encoded))
+	})
+
+	It("round trips both modes", func() {
+		var decoded record
+		err := json.Unmarshal([]byte(encoded), &decoded)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(decoded).Should(Equal(rec))
+		Ω(decoded.Str.Duration()).Should(Equal(90 * time.Second))
+		Ω(decoded.Sec.Duration()).Should(Equal(1500 * time.Millisecond))
+	})
+
+	It("decodes integer numbers of seconds", func() {
+		var d goa.DurationSeconds
+		err := json.Unmarshal([]byte("30"), &d)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(d.Duration()).Should(Equal(30 * time.Second))
+	})
+
+	It("rejects invalid duration strings", func() {
+		var d goa.DurationString
+		err := json.Unmarshal([]byte(`"soon"`), &d)
+		Ω(err).Should(HaveOccurred())
+	})
+})
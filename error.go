@@ -224,6 +224,36 @@ func InvalidLengthError(ctx string, target interface{}, ln, value int, min bool)
 	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "len", ln, "comp", comp, "expected", value)
 }
 
+// InvalidUniqueItemsError is the error produced when the value of a parameter or payload field
+// contains duplicate elements while the design defines a unique items validation.
+func InvalidUniqueItemsError(ctx string, dup interface{}) error {
+	msg := fmt.Sprintf("elements of %s must be unique but got value %#v more than once", ctx, dup)
+	return ErrInvalidRequest(msg, "attribute", ctx, "value", dup)
+}
+
+// ExclusiveAttributesError is the error produced when more than one of a group of mutually
+// exclusive payload fields is set.
+func ExclusiveAttributesError(ctx string, names []string) error {
+	msg := fmt.Sprintf("at most one of the attributes %s of %s may be set", quoteNames(names), ctx)
+	return ErrInvalidRequest(msg, "attributes", names, "parent", ctx)
+}
+
+// MissingAttributeGroupError is the error produced when none of a group of payload fields at least
+// one of which is required is set.
+func MissingAttributeGroupError(ctx string, names []string) error {
+	msg := fmt.Sprintf("at least one of the attributes %s of %s must be set", quoteNames(names), ctx)
+	return ErrInvalidRequest(msg, "attributes", names, "parent", ctx)
+}
+
+// quoteNames returns the comma separated list of the quoted given names.
+func quoteNames(names []string) string {
+	elems := make([]string, len(names))
+	for i, n := range names {
+		elems[i] = fmt.Sprintf("%#v", n)
+	}
+	return strings.Join(elems, ", ")
+}
+
 // NoAuthMiddleware is the error produced when goa is unable to lookup a auth middleware for a
 // security scheme defined in the design.
 func NoAuthMiddleware(schemeName string) error {
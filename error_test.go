@@ -228,6 +228,48 @@ var _ = Describe("InvalidRangeError", func() {
 	})
 })
 
+var _ = Describe("InvalidUniqueItemsError", func() {
+	const ctx = "ctx"
+	const dup = "dup"
+
+	It("creates a http error", func() {
+		valErr := InvalidUniqueItemsError(ctx, dup)
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring(fmt.Sprintf("%#v", dup)))
+	})
+})
+
+var _ = Describe("ExclusiveAttributesError", func() {
+	const ctx = "ctx"
+	var names = []string{"email", "phone"}
+
+	It("creates a http error", func() {
+		valErr := ExclusiveAttributesError(ctx, names)
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring(`"email", "phone"`))
+	})
+})
+
+var _ = Describe("MissingAttributeGroupError", func() {
+	const ctx = "ctx"
+	var names = []string{"email", "phone"}
+
+	It("creates a http error", func() {
+		valErr := MissingAttributeGroupError(ctx, names)
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring(`"email", "phone"`))
+	})
+})
+
 var _ = Describe("InvalidLengthError", func() {
 	const ctx = "ctx"
 	const value = 42
@@ -0,0 +1,75 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+
+	"github.com/goadesign/goa/design"
+)
+
+// GoTypeExpr returns the go/ast expression of the Go type used to refer to instances of t, that is
+// the expression whose printed form is the code returned by GoTypeRef(t, nil, 0, false). It lets
+// code generators assemble code with go/ast and go/printer rather than string concatenation.
+// Qualified type names such as time.Time are returned as selector expressions. Inline objects are
+// returned as struct types whose fields follow the GoTypeDef rules, including the field metadata,
+// struct tags are not generated.
+func GoTypeExpr(t design.DataType) ast.Expr {
+	if mt, ok := t.(*design.MediaTypeDefinition); ok && mt.IsError() {
+		return ast.NewIdent("error")
+	}
+	expr := goTypeNameExpr(t)
+	if t.IsObject() {
+		return &ast.StarExpr{X: expr}
+	}
+	return expr
+}
+
+// goTypeNameExpr returns the go/ast expression of the Go type name of t.
+func goTypeNameExpr(t design.DataType) ast.Expr {
+	switch actual := t.(type) {
+	case design.Primitive:
+		switch actual.Kind() {
+		case design.DateTimeKind:
+			return selector("time", "Time")
+		case design.UUIDKind:
+			return selector("uuid", "UUID")
+		case design.LanguageKind:
+			return selector("language", "Tag")
+		case design.AnyKind:
+			// Valid brace positions make go/printer render "interface{}" on a single line.
+			return &ast.InterfaceType{Methods: &ast.FieldList{Opening: 1, Closing: 1}}
+		default:
+			return ast.NewIdent(GoNativeType(actual))
+		}
+	case *design.Array:
+		return &ast.ArrayType{Elt: GoTypeExpr(actual.ElemType.Type)}
+	case *design.Hash:
+		return &ast.MapType{Key: GoTypeExpr(actual.KeyType.Type), Value: GoTypeExpr(actual.ElemType.Type)}
+	case design.Object:
+		return goStructExpr(&design.AttributeDefinition{Type: actual})
+	case *design.UserTypeDefinition:
+		return ast.NewIdent(Goify(actual.TypeName, true))
+	case *design.MediaTypeDefinition:
+		return ast.NewIdent(Goify(actual.TypeName, true))
+	default:
+		panic(fmt.Sprintf("goa bug: unknown type %#v", actual))
+	}
+}
+
+// goStructExpr returns the go/ast struct type of the object attribute def. The struct type is
+// parsed from the GoTypeDef code so that the fields follow the same rules, struct tags and
+// comments excepted.
+func goStructExpr(def *design.AttributeDefinition) *ast.StructType {
+	code := GoTypeDef(def, 0, false, false)
+	expr, err := parser.ParseExpr(code)
+	if err != nil {
+		panic(fmt.Sprintf("goa bug: invalid struct type %s: %s", code, err))
+	}
+	return expr.(*ast.StructType)
+}
+
+// selector returns the go/ast expression of the qualified identifier pkg.name.
+func selector(pkg, name string) *ast.SelectorExpr {
+	return &ast.SelectorExpr{X: ast.NewIdent(pkg), Sel: ast.NewIdent(name)}
+}
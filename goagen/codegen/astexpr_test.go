@@ -0,0 +1,98 @@
+package codegen_test
+
+import (
+	"bytes"
+	"go/printer"
+	"go/token"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoTypeExpr", func() {
+	var t design.DataType
+	var printed string
+
+	JustBeforeEach(func() {
+		var buf bytes.Buffer
+		err := printer.Fprint(&buf, token.NewFileSet(), codegen.GoTypeExpr(t))
+		Ω(err).ShouldNot(HaveOccurred())
+		printed = buf.String()
+	})
+
+	user := &design.UserTypeDefinition{
+		TypeName:            "bottle",
+		AttributeDefinition: &design.AttributeDefinition{Type: design.Object{"name": {Type: design.String}}},
+	}
+	media := &design.MediaTypeDefinition{
+		UserTypeDefinition: &design.UserTypeDefinition{
+			TypeName:            "Account",
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{"id": {Type: design.Integer}}},
+		},
+		Identifier: "application/vnd.account",
+	}
+	cases := map[string]design.DataType{
+		"a boolean":                      design.Boolean,
+		"an integer":                     design.Integer,
+		"a number":                       design.Number,
+		"a string":                       design.String,
+		"a date time":                    design.DateTime,
+		"a UUID":                         design.UUID,
+		"a language":                     design.Language,
+		"any":                            design.Any,
+		"an array of strings":            &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}},
+		"an array of user types":         &design.Array{ElemType: &design.AttributeDefinition{Type: user}},
+		"a hash of date times":           &design.Hash{KeyType: &design.AttributeDefinition{Type: design.String}, ElemType: &design.AttributeDefinition{Type: design.DateTime}},
+		"a hash of arrays of media type": &design.Hash{KeyType: &design.AttributeDefinition{Type: design.Integer}, ElemType: &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: media}}}},
+		"a user type":                    user,
+		"a media type":                   media,
+		"the error media type":           design.ErrorMedia,
+	}
+	for desc, dt := range cases {
+		desc, dt := desc, dt
+		Context("with "+desc, func() {
+			BeforeEach(func() {
+				t = dt
+			})
+
+			It("prints as the GoTypeRef code", func() {
+				Ω(printed).Should(Equal(codegen.GoTypeRef(t, nil, 0, false)))
+			})
+		})
+	}
+
+	Context("with an inline object", func() {
+		BeforeEach(func() {
+			t = design.Object{
+				"count": {Type: design.Integer},
+				"owner": {Type: user},
+				"tags":  {Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+			}
+		})
+
+		It("returns a pointer to a struct following the pointer rules", func() {
+			Ω(printed).Should(Equal("*struct {\n\tCount\t*int\n\tOwner\t*Bottle\n\tTags\t[]string\n}"))
+		})
+	})
+
+	Context("with an inline object with fields using codegen metadata", func() {
+		BeforeEach(func() {
+			t = design.Object{
+				"count":   {Type: design.Integer, Metadata: dslengine.MetadataDefinition{codegen.SQLNullKey: nil}},
+				"hits":    {Type: design.Integer, Metadata: dslengine.MetadataDefinition{codegen.AtomicKey: nil}},
+				"timeout": {Type: design.String, Metadata: dslengine.MetadataDefinition{codegen.DurationKey: nil}},
+				"owner":   {Type: design.String, Metadata: dslengine.MetadataDefinition{"struct:field:id": {"UserID"}}},
+				"events":  {Type: design.String, Metadata: dslengine.MetadataDefinition{codegen.ChannelKey: nil}},
+				"depth":   {Type: design.String, Metadata: dslengine.MetadataDefinition{codegen.PointerDepthKey: {"2"}}},
+			}
+		})
+
+		It("prints as the GoTypeRef code without the struct tags", func() {
+			Ω(printed).Should(Equal("*struct {\n\tCount\tgoa.NullInt64\n\tDepth\t**string\n\tEvents\tchan string\n" +
+				"\tHits\tatomic.Int64\n\tOwner\t*UserID\n\tTimeout\t*goa.DurationString\n}"))
+		})
+	})
+})
@@ -0,0 +1,142 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// AtomicKey is the name of the metadata used to flag integer and boolean attributes whose struct
+// fields are rendered with the atomic.Int64 and atomic.Bool types of the sync/atomic package so
+// that they may be read and written concurrently. The atomic types require Go 1.19 or later.
+// Atomic fields are only used in public structs and are skipped by the default JSON encoding,
+// GoAtomicAccessors produces the methods that access them and encode their values. The atomic
+// types must not be copied so the generated MarshalJSON method has a pointer receiver: the structs
+// must be marshaled through a pointer, marshaling a struct value omits the atomic fields.
+const AtomicKey = "struct:field:atomic"
+
+var atomicT *template.Template
+
+func init() {
+	var err error
+	if atomicT, err = template.New("atomic").Parse(atomicTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoAtomicAccessors produces the Go code of the methods of the struct generated for the given user
+// type that access the atomic fields of the attributes flagged with AtomicKey, e.g. "GetHits",
+// "SetHits" and "AddHits" for an integer "hits" attribute. It also produces the MarshalJSON and
+// UnmarshalJSON methods that encode the values loaded from the atomic fields together with the
+// other fields. The function returns an error if ut is not an object, if a flagged attribute is not
+// an integer or a boolean, if no attribute is flagged or if another generator produces the JSON
+// methods of ut, see jsonMethodsConflict.
+func GoAtomicAccessors(ut *design.UserTypeDefinition) (string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	if err := jsonMethodsConflict(ut, AtomicKey); err != nil {
+		return "", err
+	}
+	var fields []map[string]interface{}
+	err := obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		if _, ok := att.Metadata[AtomicKey]; !ok {
+			return nil
+		}
+		if atomicType(att) == "" {
+			return fmt.Errorf("%s.%s: atomic fields of type %s are not supported", ut.TypeName, n, att.Type.Name())
+		}
+		typ := "bool"
+		if att.Type.Kind() == design.IntegerKind {
+			typ = "int64"
+		}
+		fields = append(fields, map[string]interface{}{
+			"Field": GoifyAtt(att, n, true),
+			"Name":  jsonName(att, n),
+			"Type":  typ,
+		})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(fields) == 0 {
+		return "", fmt.Errorf("type %s does not define attributes with the %s metadata", ut.TypeName, AtomicKey)
+	}
+	data := map[string]interface{}{
+		"Name":   Goify(ut.TypeName, true),
+		"Fields": fields,
+	}
+	return RunTemplate(atomicT, data), nil
+}
+
+// atomicType returns the sync/atomic type used by the field generated for the given attribute if
+// it is an integer or a boolean and defines the AtomicKey metadata, the empty string otherwise.
+func atomicType(att *design.AttributeDefinition) string {
+	if _, ok := att.Metadata[AtomicKey]; !ok {
+		return ""
+	}
+	switch att.Type.Kind() {
+	case design.IntegerKind:
+		return "atomic.Int64"
+	case design.BooleanKind:
+		return "atomic.Bool"
+	default:
+		return ""
+	}
+}
+
+// isAtomic returns true if the field generated for the child attribute of parent with the given
+// name uses a sync/atomic type. Only the fields of public structs may be atomic, the publicizer
+// stores the values of the private struct fields.
+func isAtomic(parent *design.AttributeDefinition, name string, private bool) bool {
+	return !private && atomicType(parent.Type.ToObject()[name]) != ""
+}
+
+const atomicTmpl = `{{ $name := .Name }}{{ range .Fields }}// Get{{ .Field }} atomically loads the {{ .Field }} field of {{ $name }}.
+func (ut *{{ $name }}) Get{{ .Field }}() {{ .Type }} {
+	return ut.{{ .Field }}.Load()
+}
+
+// Set{{ .Field }} atomically stores v in the {{ .Field }} field of {{ $name }}.
+func (ut *{{ $name }}) Set{{ .Field }}(v {{ .Type }}) {
+	ut.{{ .Field }}.Store(v)
+}
+{{ if eq .Type "int64" }}
+// Add{{ .Field }} atomically adds delta to the {{ .Field }} field of {{ $name }} and returns the new value.
+func (ut *{{ $name }}) Add{{ .Field }}(delta int64) int64 {
+	return ut.{{ .Field }}.Add(delta)
+}
+{{ end }}
+{{ end }}// MarshalJSON encodes the {{ .Name }} using the values loaded from its atomic fields. The atomic
+// fields must not be copied: {{ .Name }} values must be marshaled through a pointer, marshaling a
+// {{ .Name }} value omits the atomic fields.
+func (ut *{{ .Name }}) MarshalJSON() ([]byte, error) {
+	type alias {{ .Name }}
+	return json.Marshal(&struct {
+		*alias
+{{ range .Fields }}		{{ .Field }} {{ .Type }} ` + "`" + `json:"{{ .Name }}"` + "`" + `
+{{ end }}	}{
+		alias: (*alias)(ut),
+{{ range .Fields }}		{{ .Field }}: ut.{{ .Field }}.Load(),
+{{ end }}	})
+}
+
+// UnmarshalJSON decodes the {{ .Name }} and stores the values of its atomic fields.
+func (ut *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	type alias {{ .Name }}
+	aux := &struct {
+		*alias
+{{ range .Fields }}		{{ .Field }} *{{ .Type }} ` + "`" + `json:"{{ .Name }}"` + "`" + `
+{{ end }}	}{alias: (*alias)(ut)}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+{{ range .Fields }}	if aux.{{ .Field }} != nil {
+		ut.{{ .Field }}.Store(*aux.{{ .Field }})
+	}
+{{ end }}	return nil
+}
+`
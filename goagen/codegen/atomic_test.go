@@ -0,0 +1,184 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoAtomicAccessors", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "Stats",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"hits": &design.AttributeDefinition{
+						Type:     design.Integer,
+						Metadata: dslengine.MetadataDefinition{"struct:field:atomic": nil},
+					},
+					"name": &design.AttributeDefinition{Type: design.String},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoAtomicAccessors(ut)
+	})
+
+	It("renders the flagged field with the atomic type and skips it in the default encoding", func() {
+		Ω(codegen.GoTypeDef(ut, 0, true, false)).Should(Equal(atomicStructCode))
+	})
+
+	It("generates the accessors and the JSON methods that load the atomic values", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(atomicCode))
+	})
+
+	It("imports the sync/atomic package", func() {
+		imports := codegen.RequiredImports([]design.DataStructure{ut})
+		Ω(imports).Should(Equal([]*codegen.ImportSpec{codegen.SimpleImport("sync/atomic")}))
+	})
+
+	Context("with validations and a boolean attribute", func() {
+		BeforeEach(func() {
+			max := 10.0
+			obj := ut.Type.ToObject()
+			obj["hits"].Validation = &dslengine.ValidationDefinition{Maximum: &max}
+			obj["active"] = &design.AttributeDefinition{
+				Type:     design.Boolean,
+				Metadata: dslengine.MetadataDefinition{"struct:field:atomic": nil},
+			}
+			ut.Validation.Required = append(ut.Validation.Required, "active")
+		})
+
+		It("generates code that compiles and validates, publicizes, copies and marshals the atomic fields", func() {
+			shallowCopy, err := codegen.GoShallowCopy(ut)
+			Ω(err).ShouldNot(HaveOccurred())
+			src := "package stats\n\nimport (\n\t\"encoding/json\"\n\t\"sync/atomic\"\n\n\t\"github.com/goadesign/goa\"\n)\n\n" +
+				"type Stats " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" +
+				"type stats " + codegen.GoTypeDef(ut, 0, true, true) + "\n\n" +
+				"func (ut *Stats) Validate() (err error) {\n" +
+				codegen.NewValidator().Code(ut.AttributeDefinition, false, false, false, "ut", "response", 1, false) +
+				"\n\treturn\n}\n\n" +
+				"func (ut *stats) Publicize() *Stats {\n\tvar pub Stats\n" +
+				codegen.RecursivePublicizer(ut.AttributeDefinition, "ut", "pub", 1) +
+				"\n\treturn &pub\n}\n\n" + code + "\n" + shallowCopy
+			out, err := goTest(map[string]string{"stats.go": src, "stats_test.go": atomicUsageTest})
+			Ω(err).ShouldNot(HaveOccurred(), out)
+		})
+	})
+
+	Context("with an attribute using other metadata that produces JSON methods", func() {
+		BeforeEach(func() {
+			ut.Type.ToObject()["name"].Metadata = dslengine.MetadataDefinition{"struct:field:intern": nil}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("struct:field:intern"))
+		})
+	})
+
+	Context("with a flagged attribute that is not an integer or a boolean", func() {
+		BeforeEach(func() {
+			ut.Type.ToObject()["name"].Metadata = dslengine.MetadataDefinition{"struct:field:atomic": nil}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const atomicStructCode = "struct {\n" +
+	"\tHits atomic.Int64 `form:\"-\" json:\"-\" xml:\"-\"`\n" +
+	"\tName string `form:\"name\" json:\"name\" xml:\"name\"`\n" +
+	"}"
+
+const atomicCode = `// GetHits atomically loads the Hits field of Stats.
+func (ut *Stats) GetHits() int64 {
+	return ut.Hits.Load()
+}
+
+// SetHits atomically stores v in the Hits field of Stats.
+func (ut *Stats) SetHits(v int64) {
+	ut.Hits.Store(v)
+}
+
+// AddHits atomically adds delta to the Hits field of Stats and returns the new value.
+func (ut *Stats) AddHits(delta int64) int64 {
+	return ut.Hits.Add(delta)
+}
+
+// MarshalJSON encodes the Stats using the values loaded from its atomic fields. The atomic
+// fields must not be copied: Stats values must be marshaled through a pointer, marshaling a
+// Stats value omits the atomic fields.
+func (ut *Stats) MarshalJSON() ([]byte, error) {
+	type alias Stats
+	return json.Marshal(&struct {
+		*alias
+		Hits int64 ` + "`" + `json:"hits"` + "`" + `
+	}{
+		alias: (*alias)(ut),
+		Hits: ut.Hits.Load(),
+	})
+}
+
+// UnmarshalJSON decodes the Stats and stores the values of its atomic fields.
+func (ut *Stats) UnmarshalJSON(data []byte) error {
+	type alias Stats
+	aux := &struct {
+		*alias
+		Hits *int64 ` + "`" + `json:"hits"` + "`" + `
+	}{alias: (*alias)(ut)}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	if aux.Hits != nil {
+		ut.Hits.Store(*aux.Hits)
+	}
+	return nil
+}
+`
+
+const atomicUsageTest = `package stats
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAtomicFields(t *testing.T) {
+	name, hits, active := "s", 3, true
+	s := (&stats{Name: &name, Hits: &hits, Active: &active}).Publicize()
+	if s.GetHits() != 3 || !s.GetActive() {
+		t.Errorf("unexpected publicized values %d, %v", s.GetHits(), s.GetActive())
+	}
+	if err := s.Validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != ` + "`" + `{"name":"s","active":true,"hits":3}` + "`" + ` {
+		t.Errorf("unexpected encoding %s", b)
+	}
+	c := s.ShallowCopy()
+	s.AddHits(8)
+	if c.GetHits() != 3 || c.Name != "s" {
+		t.Errorf("unexpected copy %d, %s", c.GetHits(), c.Name)
+	}
+	if err := s.Validate(); err == nil {
+		t.Error("expected an error for a value above the maximum")
+	}
+}
+`
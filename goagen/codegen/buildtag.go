@@ -0,0 +1,71 @@
+package codegen
+
+import (
+	"fmt"
+	"go/build/constraint"
+
+	"github.com/goadesign/goa/design"
+)
+
+// BuildTagKey is the name of the metadata used to restrict a user type attribute to the builds
+// that satisfy a build constraint, e.g. "debug". The struct generated for the user type is split
+// across two files by GoBuildTaggedFiles.
+const BuildTagKey = "struct:field:build"
+
+// GoBuildTaggedFiles produces the Go code of the files that define the struct generated for ut
+// when some of its attributes are gated by a build constraint with the BuildTagKey metadata. The
+// returned map is indexed by build constraint: the file for the constraint defines the struct with
+// the gated fields and the file for the negated constraint defines it without. Compound
+// constraints are negated as a whole, e.g. "!(linux && amd64)".
+// pkg is the name of the package the files belong to.
+// The function returns a single file indexed by the empty string if no attribute is gated and an
+// error if the attributes are gated by different constraints or if the constraint is invalid.
+func GoBuildTaggedFiles(ut *design.UserTypeDefinition, pkg string) (map[string]string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return nil, fmt.Errorf("user type %s must be an object", ut.TypeName)
+	}
+	var tag string
+	err := obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		vals, ok := att.Metadata[BuildTagKey]
+		if !ok {
+			return nil
+		}
+		if len(vals) != 1 || vals[0] == "" {
+			return fmt.Errorf("%s.%s: %s metadata must have one value", ut.TypeName, n, BuildTagKey)
+		}
+		if tag != "" && vals[0] != tag {
+			return fmt.Errorf("%s: attributes are gated by different build constraints %q and %q",
+				ut.TypeName, tag, vals[0])
+		}
+		tag = vals[0]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tag == "" {
+		return map[string]string{"": buildTaggedFile("", pkg, ut, ut.AttributeDefinition)}, nil
+	}
+	expr, err := constraint.Parse("//go:build " + tag)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid build constraint %q: %s", ut.TypeName, tag, err)
+	}
+	negated := (&constraint.NotExpr{X: expr}).String()
+	return map[string]string{
+		tag:     buildTaggedFile(tag, pkg, ut, ut.AttributeDefinition),
+		negated: buildTaggedFile(negated, pkg, ut, omitAttributes(ut.AttributeDefinition, BuildTagKey)),
+	}, nil
+}
+
+// buildTaggedFile returns the content of the file that defines the struct generated for ut using
+// the attributes of att and the given build constraint if any.
+func buildTaggedFile(constraint, pkg string, ut *design.UserTypeDefinition, att *design.AttributeDefinition) string {
+	var code string
+	if constraint != "" {
+		code = fmt.Sprintf("//go:build %s\n\n", constraint)
+	}
+	name := Goify(ut.TypeName, true)
+	code += fmt.Sprintf("package %s\n\n// %s\ntype %s %s\n", pkg, GoTypeDesc(ut, true), name, GoTypeDef(att, 0, true, false))
+	return code
+}
@@ -0,0 +1,100 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoBuildTaggedFiles", func() {
+	var obj design.Object
+	var files map[string]string
+	var err error
+
+	BeforeEach(func() {
+		obj = design.Object{
+			"name": &design.AttributeDefinition{Type: design.String},
+			"trace": &design.AttributeDefinition{
+				Type:     design.String,
+				Metadata: dslengine.MetadataDefinition{codegen.BuildTagKey: {"debug"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		ut := &design.UserTypeDefinition{
+			AttributeDefinition: &design.AttributeDefinition{Type: obj},
+			TypeName:            "Diag",
+		}
+		files, err = codegen.GoBuildTaggedFiles(ut, "app")
+	})
+
+	It("splits the type across two files", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(files).Should(HaveLen(2))
+		Ω(files["debug"]).Should(Equal(taggedFile))
+		Ω(files["!debug"]).Should(Equal(untaggedFile))
+	})
+
+	Context("with no gated attribute", func() {
+		BeforeEach(func() {
+			delete(obj, "trace")
+		})
+
+		It("produces a single file with no constraint", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(files).Should(HaveLen(1))
+			Ω(files[""]).Should(HavePrefix("package app\n"))
+		})
+	})
+
+	Context("with a compound constraint", func() {
+		BeforeEach(func() {
+			obj["trace"].Metadata = dslengine.MetadataDefinition{codegen.BuildTagKey: {"linux && amd64"}}
+		})
+
+		It("negates the whole constraint", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(files).Should(HaveLen(2))
+			Ω(files["linux && amd64"]).Should(HavePrefix("//go:build linux && amd64\n"))
+			Ω(files["!(linux && amd64)"]).Should(HavePrefix("//go:build !(linux && amd64)\n"))
+		})
+	})
+
+	Context("with an invalid constraint", func() {
+		BeforeEach(func() {
+			obj["trace"].Metadata = dslengine.MetadataDefinition{codegen.BuildTagKey: {"linux &&"}}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with attributes gated by different constraints", func() {
+		BeforeEach(func() {
+			obj["name"].Metadata = dslengine.MetadataDefinition{codegen.BuildTagKey: {"linux"}}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const taggedFile = "//go:build debug\n\n" +
+	"package app\n\n" +
+	"// Diag user type.\n" +
+	"type Diag struct {\n" +
+	"	Name *string `form:\"name,omitempty\" json:\"name,omitempty\" xml:\"name,omitempty\"`\n" +
+	"	Trace *string `form:\"trace,omitempty\" json:\"trace,omitempty\" xml:\"trace,omitempty\"`\n" +
+	"}\n"
+
+const untaggedFile = "//go:build !debug\n\n" +
+	"package app\n\n" +
+	"// Diag user type.\n" +
+	"type Diag struct {\n" +
+	"	Name *string `form:\"name,omitempty\" json:\"name,omitempty\" xml:\"name,omitempty\"`\n" +
+	"}\n"
@@ -0,0 +1,63 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+var bulkT *template.Template
+
+func init() {
+	var err error
+	if bulkT, err = template.New("bulk").Parse(bulkTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoBulkTypes produces the Go code of the request and response types of bulk endpoints operating
+// on the given item type, e.g. "FooBulkRequest" and "FooBulkResponse" for "Foo". The request holds
+// the items and the response holds one "FooBulkResult" per item. A result carries the index of the
+// item in the request, whether the operation succeeded and the error message if it did not.
+// The function returns an error if ut is not an object.
+func GoBulkTypes(ut *design.UserTypeDefinition) (string, error) {
+	if ut.Type.ToObject() == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	req := &design.AttributeDefinition{
+		Type: design.Object{
+			"items": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: ut}}},
+		},
+		Validation: &dslengine.ValidationDefinition{Required: []string{"items"}},
+	}
+	res := &design.AttributeDefinition{
+		Type: design.Object{
+			"index":   &design.AttributeDefinition{Type: design.Integer},
+			"success": &design.AttributeDefinition{Type: design.Boolean},
+			"error":   &design.AttributeDefinition{Type: design.String},
+		},
+		Validation: &dslengine.ValidationDefinition{Required: []string{"index", "success"}},
+	}
+	data := map[string]interface{}{
+		"Name":       Goify(ut.TypeName, true),
+		"RequestDef": GoTypeDef(req, 0, true, false),
+		"ResultDef":  GoTypeDef(res, 0, true, false),
+	}
+	return RunTemplate(bulkT, data), nil
+}
+
+const bulkTmpl = `// {{ .Name }}BulkRequest is the request of bulk operations on {{ .Name }} items.
+type {{ .Name }}BulkRequest {{ .RequestDef }}
+
+// {{ .Name }}BulkResponse is the response of bulk operations on {{ .Name }} items, it holds one
+// result per item of the request.
+type {{ .Name }}BulkResponse struct {
+	Results []{{ .Name }}BulkResult ` + "`" + `form:"results" json:"results" xml:"results"` + "`" + `
+}
+
+// {{ .Name }}BulkResult is the result of the operation on the item of a {{ .Name }}BulkRequest at
+// Index. Error describes the failure if Success is false.
+type {{ .Name }}BulkResult {{ .ResultDef }}
+`
@@ -0,0 +1,62 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoBulkTypes", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "foo",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{"name": &design.AttributeDefinition{Type: design.String}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoBulkTypes(ut)
+	})
+
+	It("generates the bulk request and response types", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(bulkCode))
+	})
+
+	Context("with a type that is not an object", func() {
+		BeforeEach(func() {
+			ut.Type = design.String
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const bulkCode = `// FooBulkRequest is the request of bulk operations on Foo items.
+type FooBulkRequest struct {
+	Items []*Foo ` + "`" + `form:"items" json:"items" xml:"items"` + "`" + `
+}
+
+// FooBulkResponse is the response of bulk operations on Foo items, it holds one
+// result per item of the request.
+type FooBulkResponse struct {
+	Results []FooBulkResult ` + "`" + `form:"results" json:"results" xml:"results"` + "`" + `
+}
+
+// FooBulkResult is the result of the operation on the item of a FooBulkRequest at
+// Index. Error describes the failure if Success is false.
+type FooBulkResult struct {
+	Error *string ` + "`" + `form:"error,omitempty" json:"error,omitempty" xml:"error,omitempty"` + "`" + `
+	Index int ` + "`" + `form:"index" json:"index" xml:"index"` + "`" + `
+	Success bool ` + "`" + `form:"success" json:"success" xml:"success"` + "`" + `
+}
+`
@@ -1,6 +1,12 @@
 package codegen_test
 
 import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -11,3 +17,31 @@ func TestCodegen(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Codegen Suite")
 }
+
+// goTest writes the given files to a temporary package nested in the package directory so that
+// they may import the goa packages, then vets the package and runs its tests. It returns the
+// combined output of the go tool.
+func goTest(files map[string]string) (string, error) {
+	dir, err := ioutil.TempDir(".", "_gotest")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return "", err
+		}
+	}
+	gobin := filepath.Join(runtime.GOROOT(), "bin", "go")
+	var out []byte
+	for _, cmd := range []string{"vet", "test"} {
+		c := exec.Command(gobin, cmd, ".")
+		c.Dir = dir
+		o, err := c.CombinedOutput()
+		out = append(out, o...)
+		if err != nil {
+			return string(out), err
+		}
+	}
+	return string(out), nil
+}
@@ -0,0 +1,71 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var shallowCopyT *template.Template
+
+func init() {
+	var err error
+	if shallowCopyT, err = template.New("shallowCopy").Parse(shallowCopyTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoShallowCopy produces the Go code of the ShallowCopy method of the struct generated for the
+// given user type. ShallowCopy returns a new struct holding a copy of the field values: scalar
+// fields are copied while slice, map and pointer fields keep referring to the same backing data as
+// the original. Modifying an element of a slice or map of the copy (or a value pointed to by one
+// of its fields) also modifies the original, appending to a slice or replacing a field does not.
+// This is what distinguishes ShallowCopy from a deep copy such as a Clone method, which would
+// duplicate the backing data so that the copy and the original never share memory. goagen does not
+// generate Clone methods: code that needs independent copies must duplicate the slices, maps and
+// pointed to values itself. The values of atomic fields are loaded from the original and stored in the copy.
+// The function returns an error if ut is not an object.
+func GoShallowCopy(ut *design.UserTypeDefinition) (string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	data := map[string]interface{}{"TypeName": Goify(ut.TypeName, true)}
+	var fields, atomics []string
+	obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		switch {
+		case isAtomic(ut.AttributeDefinition, n, false):
+			atomics = append(atomics, GoifyAtt(att, n, true))
+		case isLazy(ut.AttributeDefinition, n, false):
+			cache := GoifyAtt(att, n, false)
+			fields = append(fields, cache, cache+"Computed", GoifyAtt(att, n, true)+"Provider")
+		default:
+			fields = append(fields, GoifyAtt(att, n, true))
+		}
+		return nil
+	})
+	if len(atomics) > 0 {
+		// Atomic values must not be copied, the fields are copied one by one and the atomic
+		// values are loaded and stored.
+		data["Fields"] = fields
+		data["Atomics"] = atomics
+	}
+	return RunTemplate(shallowCopyT, data), nil
+}
+
+const shallowCopyTmpl = `// ShallowCopy returns a copy of {{ .TypeName }} that shares the backing data of its slice, map and
+// pointer fields with the original.
+func (ut *{{ .TypeName }}) ShallowCopy() *{{ .TypeName }} {
+	if ut == nil {
+		return nil
+	}
+{{ if .Atomics }}	res := &{{ .TypeName }}{
+{{ range .Fields }}		{{ . }}: ut.{{ . }},
+{{ end }}	}
+{{ range .Atomics }}	res.{{ . }}.Store(ut.{{ . }}.Load())
+{{ end }}	return res
+{{ else }}	res := *ut
+	return &res
+{{ end }}}
+`
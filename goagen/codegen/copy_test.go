@@ -0,0 +1,97 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoShallowCopy", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "order",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"id":    &design.AttributeDefinition{Type: design.Integer},
+					"items": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoShallowCopy(ut)
+	})
+
+	It("generates a method copying the struct value", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(shallowCopyCode))
+	})
+
+	It("generates code that shares the backing data of slices, maps and pointers", func() {
+		obj := ut.Type.ToObject()
+		obj["tags"] = &design.AttributeDefinition{Type: &design.Hash{
+			KeyType:  &design.AttributeDefinition{Type: design.String},
+			ElemType: &design.AttributeDefinition{Type: design.String},
+		}}
+		obj["note"] = &design.AttributeDefinition{Type: design.String}
+		code, err := codegen.GoShallowCopy(ut)
+		Ω(err).ShouldNot(HaveOccurred())
+		src := "package order\n\ntype Order " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" + code
+		out, err := goTest(map[string]string{"order.go": src, "order_test.go": shallowCopyTest})
+		Ω(err).ShouldNot(HaveOccurred(), out)
+	})
+
+	Context("with a type that is not an object", func() {
+		BeforeEach(func() {
+			ut.Type = design.String
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const shallowCopyCode = `// ShallowCopy returns a copy of Order that shares the backing data of its slice, map and
+// pointer fields with the original.
+func (ut *Order) ShallowCopy() *Order {
+	if ut == nil {
+		return nil
+	}
+	res := *ut
+	return &res
+}
+`
+
+const shallowCopyTest = `package order
+
+import "testing"
+
+func TestShallowCopy(t *testing.T) {
+	id, note := 1, "fragile"
+	o := &Order{ID: &id, Items: []string{"a", "b"}, Tags: map[string]string{"k": "v"}, Note: &note}
+	c := o.ShallowCopy()
+	c.Items[0] = "changed"
+	c.Tags["k"] = "changed"
+	*c.Note = "changed"
+	if o.Items[0] != "changed" || o.Tags["k"] != "changed" || *o.Note != "changed" {
+		t.Errorf("backing data not shared %+v", o)
+	}
+	other := 2
+	c.ID = &other
+	c.Items = append(c.Items, "c")
+	c.Tags = nil
+	if *o.ID != 1 || len(o.Items) != 2 || o.Tags == nil {
+		t.Errorf("fields of the original replaced %+v", o)
+	}
+	if (*Order)(nil).ShallowCopy() != nil {
+		t.Error("copy of nil is not nil")
+	}
+}
+`
@@ -0,0 +1,100 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var defaultsT *template.Template
+
+func init() {
+	var err error
+	if defaultsT, err = template.New("defaults").Parse(defaultsTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoDefaultsType produces the Go code that defines the struct generated for the given user type
+// together with its SetDefaults and UnmarshalJSON methods. Optional attributes that define a
+// default value are rendered as value fields: SetDefaults replaces the zero values of the fields
+// built in code with the design defaults while UnmarshalJSON decodes the values over the defaults
+// so that the zero values present in the JSON document are kept. Optional attributes with no
+// default are rendered as pointers and left untouched. The methods are omitted if no attribute
+// defines a default value, UnmarshalJSON requires the "encoding/json" package.
+// The function returns an error if ut is not an object, if a default value cannot be expressed
+// as a Go literal or if another generator produces the JSON methods of ut, see
+// jsonMethodsConflict.
+func GoDefaultsType(ut *design.UserTypeDefinition) (string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	var fields []map[string]interface{}
+	err := obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		if !ut.HasDefaultValue(n) || ut.IsRequired(n) {
+			return nil
+		}
+		if !att.Type.IsPrimitive() && !att.Type.IsArray() && !att.Type.IsHash() {
+			return fmt.Errorf("%s.%s: default values of type %s are not supported", ut.TypeName, n, att.Type.Name())
+		}
+		field := "ut." + GoifyAtt(att, n, true)
+		zero := ZeroValue(att.Type)
+		if strings.HasSuffix(zero, "{}") {
+			zero = "(" + zero + ")"
+		}
+		fields = append(fields, map[string]interface{}{
+			"Field":    field,
+			"Zero":     zero,
+			"Default":  printVal(att.Type, att.DefaultValue),
+			"DateTime": att.Type.Kind() == design.DateTimeKind,
+		})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(fields) > 0 {
+		if err := jsonMethodsConflict(ut, "defaults"); err != nil {
+			return "", err
+		}
+	}
+	name := Goify(ut.TypeName, true)
+	data := map[string]interface{}{
+		"Desc":   GoTypeDesc(ut, true),
+		"Name":   name,
+		"Def":    GoTypeDef(ut, 0, true, false),
+		"Fields": fields,
+	}
+	return RunTemplate(defaultsT, data), nil
+}
+
+const defaultsTmpl = `// {{ .Desc }}
+type {{ .Name }} {{ .Def }}
+{{ if .Fields }}
+// SetDefaults sets the fields of {{ .Name }} that hold their zero value to the default values
+// defined in the design.
+func (ut *{{ .Name }}) SetDefaults() {
+{{ range .Fields }}{{ if .DateTime }}	if {{ .Field }}.IsZero() {
+		{{ .Field }}, _ = {{ .Default }}
+	}
+{{ else }}	if {{ .Field }} == {{ .Zero }} {
+		{{ .Field }} = {{ .Default }}
+	}
+{{ end }}{{ end }}}
+
+// UnmarshalJSON decodes the {{ .Name }} over the default values defined in the design so that
+// the zero values present in data are not replaced with the defaults.
+func (ut *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	type alias {{ .Name }}
+	var res {{ .Name }}
+	res.SetDefaults()
+	if err := json.Unmarshal(data, (*alias)(&res)); err != nil {
+		return err
+	}
+	*ut = res
+	return nil
+}
+{{ end }}`
@@ -0,0 +1,180 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoDefaultsType", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoDefaultsType(ut)
+	})
+
+	Context("given optional attributes with and without default values", func() {
+		BeforeEach(func() {
+			ut = &design.UserTypeDefinition{
+				TypeName: "Account",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"id":    &design.AttributeDefinition{Type: design.Integer},
+						"name":  &design.AttributeDefinition{Type: design.String, DefaultValue: "anonymous"},
+						"quota": &design.AttributeDefinition{Type: design.Integer, DefaultValue: 10},
+						"nick":  &design.AttributeDefinition{Type: design.String},
+					},
+					Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
+				},
+			}
+		})
+
+		It("renders defaulted fields as values filled by SetDefaults and the others as pointers", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(Equal(defaultsTypeCode))
+		})
+
+		Context("with identifier and language defaults", func() {
+			BeforeEach(func() {
+				obj := ut.Type.ToObject()
+				obj["owner"] = &design.AttributeDefinition{Type: design.UUID, DefaultValue: "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}
+				obj["locale"] = &design.AttributeDefinition{Type: design.Language, DefaultValue: "fr-CA"}
+			})
+
+			It("renders the defaults with the uuid and language parsers", func() {
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(code).Should(ContainSubstring(`ut.Owner = uuid.FromStringOrNil("6ba7b810-9dad-11d1-80b4-00c04fd430c8")`))
+				Ω(code).Should(ContainSubstring(`ut.Locale = language.MustParse("fr-CA")`))
+			})
+		})
+
+		Context("with an interned attribute", func() {
+			BeforeEach(func() {
+				ut.Type.ToObject()["tenant"] = &design.AttributeDefinition{
+					Type:     design.String,
+					Metadata: dslengine.MetadataDefinition{codegen.InternKey: nil},
+				}
+			})
+
+			It("returns an error", func() {
+				Ω(err).Should(HaveOccurred())
+				Ω(err.Error()).Should(ContainSubstring(codegen.InternKey))
+			})
+		})
+
+		It("generates code that compiles and keeps the explicit zero values", func() {
+			ut.TypeName = "Account"
+			ut.Type.ToObject()["owner"] = &design.AttributeDefinition{Type: design.UUID, DefaultValue: "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}
+			code, err := codegen.GoDefaultsType(ut)
+			Ω(err).ShouldNot(HaveOccurred())
+			src := "package account\n\nimport (\n\t\"encoding/json\"\n\n\tuuid \"github.com/satori/go.uuid\"\n)\n\n" + code
+			out, err := goTest(map[string]string{"account.go": src, "account_test.go": defaultsUsageTest})
+			Ω(err).ShouldNot(HaveOccurred(), out)
+		})
+	})
+
+	Context("given optional attributes without default values", func() {
+		BeforeEach(func() {
+			ut = &design.UserTypeDefinition{
+				TypeName: "Account",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"nick": &design.AttributeDefinition{Type: design.String},
+					},
+				},
+			}
+		})
+
+		It("renders pointer fields and omits SetDefaults", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(Equal(noDefaultsTypeCode))
+		})
+	})
+
+	Context("given a non object type", func() {
+		BeforeEach(func() {
+			ut = &design.UserTypeDefinition{
+				TypeName:            "Name",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+			}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const (
+	defaultsTypeCode = `// Account user type.
+type Account struct {
+	ID int ` + "`" + `form:"id" json:"id" xml:"id"` + "`" + `
+	Name string ` + "`" + `form:"name" json:"name" xml:"name"` + "`" + `
+	Nick *string ` + "`" + `form:"nick,omitempty" json:"nick,omitempty" xml:"nick,omitempty"` + "`" + `
+	Quota int ` + "`" + `form:"quota" json:"quota" xml:"quota"` + "`" + `
+}
+
+// SetDefaults sets the fields of Account that hold their zero value to the default values
+// defined in the design.
+func (ut *Account) SetDefaults() {
+	if ut.Name == "" {
+		ut.Name = "anonymous"
+	}
+	if ut.Quota == 0 {
+		ut.Quota = 10
+	}
+}
+
+// UnmarshalJSON decodes the Account over the default values defined in the design so that
+// the zero values present in data are not replaced with the defaults.
+func (ut *Account) UnmarshalJSON(data []byte) error {
+	type alias Account
+	var res Account
+	res.SetDefaults()
+	if err := json.Unmarshal(data, (*alias)(&res)); err != nil {
+		return err
+	}
+	*ut = res
+	return nil
+}
+`
+
+	noDefaultsTypeCode = `// Account user type.
+type Account struct {
+	Nick *string ` + "`" + `form:"nick,omitempty" json:"nick,omitempty" xml:"nick,omitempty"` + "`" + `
+}
+`
+)
+
+const defaultsUsageTest = `package account
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDefaults(t *testing.T) {
+	var absent, zero Account
+	if err := json.Unmarshal([]byte(` + "`" + `{"id":1}` + "`" + `), &absent); err != nil {
+		t.Fatal(err)
+	}
+	if absent.Name != "anonymous" || absent.Quota != 10 || absent.Owner.String() != "6ba7b810-9dad-11d1-80b4-00c04fd430c8" {
+		t.Errorf("unexpected defaults %+v", absent)
+	}
+	if err := json.Unmarshal([]byte(` + "`" + `{"id":1,"name":"","quota":0}` + "`" + `), &zero); err != nil {
+		t.Fatal(err)
+	}
+	if zero.Name != "" || zero.Quota != 0 {
+		t.Errorf("explicit zero values replaced with defaults %+v", zero)
+	}
+	built := Account{ID: 1, Quota: 3}
+	built.SetDefaults()
+	if built.Name != "anonymous" || built.Quota != 3 {
+		t.Errorf("unexpected defaults %+v", built)
+	}
+}
+`
@@ -0,0 +1,105 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// DiscriminatorKey is the name of the metadata used to describe type families made of a base
+// type and derived types. Set on the base type the value is the name of the string attribute that
+// holds the discriminator, set on a derived type the value is the discriminator of the type.
+const DiscriminatorKey = "struct:discriminator"
+
+var derivedT *template.Template
+
+func init() {
+	var err error
+	if derivedT, err = template.New("derived").Parse(derivedTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoDerivedTypes produces the Go code that defines the structs generated for the given base and
+// derived types. The derived structs embed the base struct, their constructor sets the base
+// discriminator field and their Type method returns the discriminator. The values of the
+// DiscriminatorKey metadata set on the types define the discriminator attribute and values.
+// The function returns an error if the base discriminator attribute is not a string attribute of
+// the base type, if a derived type does not define its discriminator, if a derived type
+// redefines an attribute of the base type or if it defines an attribute whose field would clash
+// with the Type method.
+func GoDerivedTypes(base *design.UserTypeDefinition, derived ...*design.UserTypeDefinition) (string, error) {
+	baseObj := base.Type.ToObject()
+	if baseObj == nil {
+		return "", fmt.Errorf("base type %s must be an object", base.TypeName)
+	}
+	disc, err := discriminator(base)
+	if err != nil {
+		return "", err
+	}
+	discAtt, ok := baseObj[disc]
+	if !ok || discAtt.Type.Kind() != design.StringKind {
+		return "", fmt.Errorf("discriminator %q of %s must be a string attribute", disc, base.TypeName)
+	}
+	baseName := Goify(base.TypeName, true)
+	code := fmt.Sprintf("// %s\ntype %s %s\n", GoTypeDesc(base, true), baseName, GoTypeDef(base, 0, true, false))
+	for _, ut := range derived {
+		obj := ut.Type.ToObject()
+		if obj == nil {
+			return "", fmt.Errorf("derived type %s must be an object", ut.TypeName)
+		}
+		for n, att := range obj {
+			if _, ok := baseObj[n]; ok {
+				return "", fmt.Errorf("derived type %s redefines attribute %q of %s", ut.TypeName, n, base.TypeName)
+			}
+			if GoifyAtt(att, n, true) == "Type" {
+				return "", fmt.Errorf("derived type %s: field of attribute %q clashes with the Type method", ut.TypeName, n)
+			}
+		}
+		val, err := discriminator(ut)
+		if err != nil {
+			return "", err
+		}
+		def := strings.Replace(GoTypeDef(ut, 0, true, false), "struct {\n", "struct {\n\t"+baseName+"\n", 1)
+		data := map[string]interface{}{
+			"Desc":          GoTypeDesc(ut, true),
+			"Name":          Goify(ut.TypeName, true),
+			"Def":           def,
+			"Field":         baseName + "." + GoifyAtt(discAtt, disc, true),
+			"Pointer":       base.IsPrimitivePointer(disc),
+			"Discriminator": val,
+		}
+		code += RunTemplate(derivedT, data)
+	}
+	return code, nil
+}
+
+// discriminator returns the value of the DiscriminatorKey metadata of ut.
+func discriminator(ut *design.UserTypeDefinition) (string, error) {
+	vals := ut.Metadata[DiscriminatorKey]
+	if len(vals) != 1 || vals[0] == "" {
+		return "", fmt.Errorf("type %s: %s metadata must have one value", ut.TypeName, DiscriminatorKey)
+	}
+	return vals[0], nil
+}
+
+const derivedTmpl = `
+// {{ .Desc }}
+type {{ .Name }} {{ .Def }}
+
+// New{{ .Name }} instantiates a {{ .Name }} and sets its discriminator.
+func New{{ .Name }}() *{{ .Name }} {
+	ut := &{{ .Name }}{}
+{{ if .Pointer }}	disc := {{ printf "%q" .Discriminator }}
+	ut.{{ .Field }} = &disc
+{{ else }}	ut.{{ .Field }} = {{ printf "%q" .Discriminator }}
+{{ end }}	return ut
+}
+
+// Type returns the {{ .Name }} discriminator.
+func (ut *{{ .Name }}) Type() string {
+	return {{ printf "%q" .Discriminator }}
+}
+`
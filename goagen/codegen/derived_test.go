@@ -0,0 +1,125 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoDerivedTypes", func() {
+	var base, dog, cat *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		base = &design.UserTypeDefinition{
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"kind": &design.AttributeDefinition{Type: design.String},
+					"name": &design.AttributeDefinition{Type: design.String},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"kind"}},
+				Metadata:   dslengine.MetadataDefinition{codegen.DiscriminatorKey: {"kind"}},
+			},
+			TypeName: "Animal",
+		}
+		dog = &design.UserTypeDefinition{
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:     design.Object{"breed": &design.AttributeDefinition{Type: design.String}},
+				Metadata: dslengine.MetadataDefinition{codegen.DiscriminatorKey: {"dog"}},
+			},
+			TypeName: "Dog",
+		}
+		cat = &design.UserTypeDefinition{
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:     design.Object{"lives": &design.AttributeDefinition{Type: design.Integer}},
+				Metadata: dslengine.MetadataDefinition{codegen.DiscriminatorKey: {"cat"}},
+			},
+			TypeName: "Cat",
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoDerivedTypes(base, dog, cat)
+	})
+
+	It("embeds the base type in the derived types", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(derivedTypesCode))
+	})
+
+	Context("with a derived type redefining a base attribute", func() {
+		BeforeEach(func() {
+			cat.Type.ToObject()["name"] = &design.AttributeDefinition{Type: design.String}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a derived type defining a type attribute", func() {
+		BeforeEach(func() {
+			dog.Type.ToObject()["type"] = &design.AttributeDefinition{Type: design.String}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("Type method"))
+		})
+	})
+
+	Context("with a derived type with no discriminator", func() {
+		BeforeEach(func() {
+			dog.Metadata = nil
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const derivedTypesCode = "// Animal user type.\n" +
+	"type Animal struct {\n" +
+	"	Kind string `form:\"kind\" json:\"kind\" xml:\"kind\"`\n" +
+	"	Name *string `form:\"name,omitempty\" json:\"name,omitempty\" xml:\"name,omitempty\"`\n" +
+	"}\n" +
+	"\n" +
+	"// Dog user type.\n" +
+	"type Dog struct {\n" +
+	"	Animal\n" +
+	"	Breed *string `form:\"breed,omitempty\" json:\"breed,omitempty\" xml:\"breed,omitempty\"`\n" +
+	"}\n" + `
+// NewDog instantiates a Dog and sets its discriminator.
+func NewDog() *Dog {
+	ut := &Dog{}
+	ut.Animal.Kind = "dog"
+	return ut
+}
+
+// Type returns the Dog discriminator.
+func (ut *Dog) Type() string {
+	return "dog"
+}
+` +
+	"\n" +
+	"// Cat user type.\n" +
+	"type Cat struct {\n" +
+	"	Animal\n" +
+	"	Lives *int `form:\"lives,omitempty\" json:\"lives,omitempty\" xml:\"lives,omitempty\"`\n" +
+	"}\n" + `
+// NewCat instantiates a Cat and sets its discriminator.
+func NewCat() *Cat {
+	ut := &Cat{}
+	ut.Animal.Kind = "cat"
+	return ut
+}
+
+// Type returns the Cat discriminator.
+func (ut *Cat) Type() string {
+	return "cat"
+}
+`
@@ -0,0 +1,112 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var fieldSchemaT *template.Template
+
+func init() {
+	var err error
+	if fieldSchemaT, err = template.New("fieldSchema").Parse(fieldSchemaTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoFieldSchema produces the Go code of the variable that lists the goa.FieldDescriptor of each
+// field of the struct generated for the given user type, e.g. "UserSchema" for "User". The
+// descriptors are sorted by attribute name and hold the Go and JSON names of the fields, the name
+// of the JSON type of the attributes, whether they are required and their validations.
+// The function returns an error if ut is not an object.
+func GoFieldSchema(ut *design.UserTypeDefinition) (string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	var fields []map[string]interface{}
+	obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		fields = append(fields, map[string]interface{}{
+			"GoName":      GoifyAtt(att, n, true),
+			"JSONName":    jsonName(att, n),
+			"Kind":        kindName(att.Type),
+			"Required":    ut.IsRequired(n),
+			"Validations": fieldValidations(att),
+		})
+		return nil
+	})
+	name := Goify(ut.TypeName, true)
+	data := map[string]interface{}{
+		"TypeName": name,
+		"VarName":  name + "Schema",
+		"Fields":   fields,
+	}
+	return RunTemplate(fieldSchemaT, data), nil
+}
+
+// kindName returns the name of the JSON type of t, the name of the JSON type of the underlying
+// type for user and media types.
+func kindName(t design.DataType) string {
+	switch actual := t.(type) {
+	case *design.UserTypeDefinition:
+		return kindName(actual.Type)
+	case *design.MediaTypeDefinition:
+		return kindName(actual.Type)
+	default:
+		return t.Name()
+	}
+}
+
+// fieldValidations returns the Go literals of the goa.FieldValidation values that describe the
+// validations of att.
+func fieldValidations(att *design.AttributeDefinition) []string {
+	v := att.Validation
+	if v == nil {
+		return nil
+	}
+	var vals []string
+	add := func(name, value string) {
+		vals = append(vals, fmt.Sprintf("{Name: %q, Value: %s}", name, value))
+	}
+	if len(v.Values) > 0 {
+		add("enum", fmt.Sprintf("%#v", v.Values))
+	}
+	if v.Format != "" {
+		add("format", fmt.Sprintf("%q", v.Format))
+	}
+	if v.Pattern != "" {
+		add("pattern", fmt.Sprintf("%q", v.Pattern))
+	}
+	if v.Minimum != nil {
+		add("minimum", fmt.Sprintf("float64(%v)", *v.Minimum))
+	}
+	if v.Maximum != nil {
+		add("maximum", fmt.Sprintf("float64(%v)", *v.Maximum))
+	}
+	if v.MinLength != nil {
+		add("minLength", fmt.Sprintf("%d", *v.MinLength))
+	}
+	if v.MaxLength != nil {
+		add("maxLength", fmt.Sprintf("%d", *v.MaxLength))
+	}
+	if v.UniqueItems {
+		add("uniqueItems", "true")
+	}
+	return vals
+}
+
+const fieldSchemaTmpl = `// {{ .VarName }} describes the fields of {{ .TypeName }}.
+var {{ .VarName }} = []goa.FieldDescriptor{
+{{ range .Fields }}	{
+		GoName: {{ printf "%q" .GoName }},
+		JSONName: {{ printf "%q" .JSONName }},
+		Kind: {{ printf "%q" .Kind }},
+{{ if .Required }}		Required: true,
+{{ end }}{{ if .Validations }}		Validations: []goa.FieldValidation{
+{{ range .Validations }}			{{ . }},
+{{ end }}		},
+{{ end }}	},
+{{ end }}}
+`
@@ -0,0 +1,100 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoFieldSchema", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		min, max, maxLen := 0.0, 150.0, 64
+		ut = &design.UserTypeDefinition{
+			TypeName: "User",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"age": &design.AttributeDefinition{
+						Type:       design.Integer,
+						Validation: &dslengine.ValidationDefinition{Minimum: &min, Maximum: &max},
+					},
+					"email": &design.AttributeDefinition{
+						Type:       design.String,
+						Validation: &dslengine.ValidationDefinition{Format: "email", MaxLength: &maxLen},
+						Metadata:   dslengine.MetadataDefinition{"struct:tag:json": {"mail,omitempty"}},
+					},
+					"roles": &design.AttributeDefinition{
+						Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}},
+					},
+					"status": &design.AttributeDefinition{
+						Type:       design.String,
+						Validation: &dslengine.ValidationDefinition{Values: []interface{}{"active", "disabled"}},
+					},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"email", "status"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoFieldSchema(ut)
+	})
+
+	It("generates the schema variable", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(fieldSchemaCode))
+	})
+
+	Context("with a type that is not an object", func() {
+		BeforeEach(func() {
+			ut.Type = design.String
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const fieldSchemaCode = `// UserSchema describes the fields of User.
+var UserSchema = []goa.FieldDescriptor{
+	{
+		GoName: "Age",
+		JSONName: "age",
+		Kind: "integer",
+		Validations: []goa.FieldValidation{
+			{Name: "minimum", Value: float64(0)},
+			{Name: "maximum", Value: float64(150)},
+		},
+	},
+	{
+		GoName: "Email",
+		JSONName: "mail",
+		Kind: "string",
+		Required: true,
+		Validations: []goa.FieldValidation{
+			{Name: "format", Value: "email"},
+			{Name: "maxLength", Value: 64},
+		},
+	},
+	{
+		GoName: "Roles",
+		JSONName: "roles",
+		Kind: "array",
+	},
+	{
+		GoName: "Status",
+		JSONName: "status",
+		Kind: "string",
+		Required: true,
+		Validations: []goa.FieldValidation{
+			{Name: "enum", Value: []interface {}{"active", "disabled"}},
+		},
+	},
+}
+`
@@ -0,0 +1,61 @@
+package codegen
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/design"
+)
+
+const (
+	// ReadOnlyKey is the name of the metadata used to flag attributes that only appear in
+	// responses. Such attributes are omitted from the request type generated by
+	// GoRequestResponseTypes.
+	ReadOnlyKey = "struct:field:readonly"
+
+	// WriteOnlyKey is the name of the metadata used to flag attributes that only appear in
+	// requests. Such attributes are omitted from the response type generated by
+	// GoRequestResponseTypes.
+	WriteOnlyKey = "struct:field:writeonly"
+)
+
+// GoRequestResponseTypes produces the Go code that defines the FooRequest and FooResponse structs
+// for the media type Foo. The request struct omits the attributes flagged with the ReadOnlyKey
+// metadata and the response struct omits the attributes flagged with the WriteOnlyKey metadata,
+// all other attributes appear in both structs.
+// The function returns an error if the media type is not an object or if an attribute is flagged
+// both read-only and write-only.
+func GoRequestResponseTypes(mt *design.MediaTypeDefinition) (string, error) {
+	obj := mt.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("media type %s must be an object", mt.TypeName)
+	}
+	for n, att := range obj {
+		_, ro := att.Metadata[ReadOnlyKey]
+		_, wo := att.Metadata[WriteOnlyKey]
+		if ro && wo {
+			return "", fmt.Errorf("%s.%s cannot be both read-only and write-only", mt.TypeName, n)
+		}
+	}
+	name := Goify(mt.TypeName, true)
+	req := omitAttributes(mt.AttributeDefinition, ReadOnlyKey)
+	resp := omitAttributes(mt.AttributeDefinition, WriteOnlyKey)
+	code := fmt.Sprintf("// %sRequest is the %s media type used in requests.\ntype %sRequest %s\n\n",
+		name, mt.TypeName, name, GoTypeDef(req, 0, true, false))
+	code += fmt.Sprintf("// %sResponse is the %s media type used in responses.\ntype %sResponse %s\n",
+		name, mt.TypeName, name, GoTypeDef(resp, 0, true, false))
+	return code, nil
+}
+
+// omitAttributes returns a copy of att whose object type omits the attributes that define
+// the metadata with the given key.
+func omitAttributes(att *design.AttributeDefinition, omitKey string) *design.AttributeDefinition {
+	obj := make(design.Object)
+	for n, a := range att.Type.ToObject() {
+		if _, ok := a.Metadata[omitKey]; !ok {
+			obj[n] = a
+		}
+	}
+	dup := design.DupAtt(att)
+	dup.Type = obj
+	return dup
+}
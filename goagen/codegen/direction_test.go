@@ -0,0 +1,72 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoRequestResponseTypes", func() {
+	var obj design.Object
+	var mt *design.MediaTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		obj = design.Object{
+			"id": &design.AttributeDefinition{
+				Type:     design.Integer,
+				Metadata: dslengine.MetadataDefinition{codegen.ReadOnlyKey: nil},
+			},
+			"password": &design.AttributeDefinition{
+				Type:     design.String,
+				Metadata: dslengine.MetadataDefinition{codegen.WriteOnlyKey: nil},
+			},
+			"name": &design.AttributeDefinition{Type: design.String},
+		}
+	})
+
+	JustBeforeEach(func() {
+		mt = &design.MediaTypeDefinition{
+			UserTypeDefinition: &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type:       obj,
+					Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+				},
+				TypeName: "User",
+			},
+		}
+		code, err = codegen.GoRequestResponseTypes(mt)
+	})
+
+	It("splits the fields according to their direction", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(requestResponseCode))
+	})
+
+	Context("with an attribute flagged read-only and write-only", func() {
+		BeforeEach(func() {
+			obj["name"].Metadata = dslengine.MetadataDefinition{
+				codegen.ReadOnlyKey:  nil,
+				codegen.WriteOnlyKey: nil,
+			}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const requestResponseCode = "// UserRequest is the User media type used in requests.\n" +
+	"type UserRequest struct {\n" +
+	"	Name string `form:\"name\" json:\"name\" xml:\"name\"`\n" +
+	"	Password *string `form:\"password,omitempty\" json:\"password,omitempty\" xml:\"password,omitempty\"`\n" +
+	"}\n\n" +
+	"// UserResponse is the User media type used in responses.\n" +
+	"type UserResponse struct {\n" +
+	"	ID *int `form:\"id,omitempty\" json:\"id,omitempty\" xml:\"id,omitempty\"`\n" +
+	"	Name string `form:\"name\" json:\"name\" xml:\"name\"`\n" +
+	"}\n"
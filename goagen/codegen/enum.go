@@ -0,0 +1,100 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+)
+
+// enumConst describes a single constant emitted by EnumConstants.
+type enumConst struct {
+	name, qualified, value string
+}
+
+// EnumConstants produces a Go const block that defines one constant per enum value of the
+// attributes of the given data structures. The enum attributes considered are the direct
+// attributes of the data structures and the elements of their array attributes. Constants are
+// named after the attribute and the value, e.g. "StatusActive". Identical constants defined by
+// different types are emitted once while constants whose name collide but whose value differ are
+// prefixed with the name of their type, e.g. "UserStatusActive". The constants are sorted by name.
+// EnumConstants returns an empty string if none of the data structures define enum values and an
+// error if values that differ produce the same constant name, e.g. "a-b" and "a_b".
+func EnumConstants(types []design.DataStructure) (string, error) {
+	var consts []*enumConst
+	for _, ds := range types {
+		obj := ds.Definition().Type.ToObject()
+		if obj == nil {
+			continue
+		}
+		typeName := dataStructureName(ds)
+		obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+			if arr := att.Type.ToArray(); arr != nil {
+				att = arr.ElemType
+			}
+			if att.Validation == nil {
+				return nil
+			}
+			for _, v := range att.Validation.Values {
+				name := Goify(n, true) + Goify(fmt.Sprintf("%v", v), true)
+				consts = append(consts, &enumConst{
+					name:      name,
+					qualified: typeName + name,
+					value:     fmt.Sprintf("%#v", v),
+				})
+			}
+			return nil
+		})
+	}
+	if len(consts) == 0 {
+		return "", nil
+	}
+
+	values := make(map[string]map[string]bool)
+	for _, c := range consts {
+		if values[c.name] == nil {
+			values[c.name] = make(map[string]bool)
+		}
+		values[c.name][c.value] = true
+	}
+	defs := make(map[string]string)
+	for _, c := range consts {
+		name := c.name
+		if len(values[c.name]) > 1 {
+			name = c.qualified
+		}
+		if v, ok := defs[name]; ok && v != c.value {
+			return "", fmt.Errorf("enum values %s and %s both produce the constant %s", v, c.value, name)
+		}
+		defs[name] = c.value
+	}
+	names := make([]string, len(defs))
+	i := 0
+	for n := range defs {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteString("const (\n")
+	for _, n := range names {
+		buf.WriteString(fmt.Sprintf("\t%s = %s\n", n, defs[n]))
+	}
+	buf.WriteString(")\n")
+	return buf.String(), nil
+}
+
+// dataStructureName returns the Go name of the type of the given data structure if it has one,
+// the empty string otherwise.
+func dataStructureName(ds design.DataStructure) string {
+	switch actual := ds.(type) {
+	case *design.MediaTypeDefinition:
+		return Goify(actual.TypeName, true)
+	case *design.UserTypeDefinition:
+		return Goify(actual.TypeName, true)
+	default:
+		return ""
+	}
+}
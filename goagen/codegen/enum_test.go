@@ -0,0 +1,103 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EnumConstants", func() {
+	var types []design.DataStructure
+	var code string
+	var err error
+
+	JustBeforeEach(func() {
+		code, err = codegen.EnumConstants(types)
+	})
+
+	Context("given two types with enum attributes", func() {
+		BeforeEach(func() {
+			enum := func(t design.DataType, vals ...interface{}) *design.AttributeDefinition {
+				return &design.AttributeDefinition{
+					Type:       t,
+					Validation: &dslengine.ValidationDefinition{Values: vals},
+				}
+			}
+			user := &design.UserTypeDefinition{
+				TypeName: "user",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"name":     &design.AttributeDefinition{Type: design.String},
+						"status":   enum(design.String, "active", "inactive"),
+						"priority": enum(design.Integer, 1, 2),
+					},
+				},
+			}
+			project := &design.UserTypeDefinition{
+				TypeName: "project",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"status":   enum(design.String, "active", "archived"),
+						"priority": enum(design.String, "1"),
+						"tags":     &design.AttributeDefinition{Type: &design.Array{ElemType: enum(design.String, "public", "private")}},
+					},
+				},
+			}
+			types = []design.DataStructure{user, project}
+		})
+
+		It("emits a sorted and deduplicated const block", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(Equal(enumConstantsCode))
+		})
+	})
+
+	Context("given enum values producing the same constant name", func() {
+		BeforeEach(func() {
+			types = []design.DataStructure{&design.UserTypeDefinition{
+				TypeName: "user",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"status": &design.AttributeDefinition{
+						Type:       design.String,
+						Validation: &dslengine.ValidationDefinition{Values: []interface{}{"a-b", "a_b"}},
+					}},
+				},
+			}}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("UserStatusAB"))
+		})
+	})
+
+	Context("given types with no enum attribute", func() {
+		BeforeEach(func() {
+			types = []design.DataStructure{&design.UserTypeDefinition{
+				TypeName: "user",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"name": &design.AttributeDefinition{Type: design.String}},
+				},
+			}}
+		})
+
+		It("returns an empty string", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(BeEmpty())
+		})
+	})
+})
+
+const enumConstantsCode = `const (
+	Priority2 = 2
+	ProjectPriority1 = "1"
+	StatusActive = "active"
+	StatusArchived = "archived"
+	StatusInactive = "inactive"
+	TagsPrivate = "private"
+	TagsPublic = "public"
+	UserPriority1 = 1
+)
+`
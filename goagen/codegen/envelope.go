@@ -0,0 +1,111 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// EnvelopeKey is the name of the metadata used to flag the user types encoded in envelopes by
+// GoEnvelopeMarshaler so that the generators of the other JSON methods reject them.
+const EnvelopeKey = "struct:envelope"
+
+var envelopeT *template.Template
+
+func init() {
+	var err error
+	if envelopeT, err = template.New("envelope").Parse(envelopeTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoEnvelopeMarshaler produces the Go code of the MarshalJSON and UnmarshalJSON methods of the
+// struct generated for the given user type that encode it in a JSON:API style envelope:
+//
+//	{"type":"users","id":"123","attributes":{"name":"joe"}}
+//
+// envType is the constant value of the "type" key. The "id" attribute is hoisted to the top level
+// of the envelope and the other attributes are encoded under the "attributes" key. UnmarshalJSON
+// returns an error if the type of the decoded envelope is not envType.
+// The function returns an error if ut is not an object, does not define an "id" attribute or if
+// another generator produces the JSON methods of ut, see jsonMethodsConflict.
+func GoEnvelopeMarshaler(ut *design.UserTypeDefinition, envType string) (string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	idAtt, ok := obj["id"]
+	if !ok {
+		return "", fmt.Errorf("type %s must define an id attribute", ut.TypeName)
+	}
+	if err := jsonMethodsConflict(ut, "envelope"); err != nil {
+		return "", err
+	}
+	attrs := make(design.Object)
+	var fields []string
+	obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		if n != "id" {
+			attrs[n] = att
+			fields = append(fields, GoifyAtt(att, n, true))
+		}
+		return nil
+	})
+	attrsAtt := design.DupAtt(ut.AttributeDefinition)
+	attrsAtt.Type = attrs
+	idType := GoTypeRef(idAtt.Type, nil, 0, false)
+	idTag := "id"
+	if ut.IsPrimitivePointer("id") {
+		idType = "*" + idType
+		idTag += ",omitempty"
+	}
+	name := Goify(ut.TypeName, true)
+	data := map[string]interface{}{
+		"Name":      name,
+		"AttrsName": Goify(ut.TypeName, false) + "Attributes",
+		"AttrsDef":  GoTypeDef(attrsAtt, 0, true, false),
+		"EnvType":   envType,
+		"IDField":   GoifyAtt(idAtt, "id", true),
+		"IDType":    idType,
+		"IDTag":     idTag,
+		"Fields":    fields,
+	}
+	return RunTemplate(envelopeT, data), nil
+}
+
+const envelopeTmpl = `// {{ .AttrsName }} holds the {{ .Name }} fields encoded under the "attributes" key.
+type {{ .AttrsName }} {{ .AttrsDef }}
+
+// MarshalJSON encodes the {{ .Name }} in a {{ printf "%q" .EnvType }} envelope.
+func (ut *{{ .Name }}) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Type       string ` + "`" + `json:"type"` + "`" + `
+		ID         {{ .IDType }} ` + "`" + `json:"{{ .IDTag }}"` + "`" + `
+		Attributes *{{ .AttrsName }} ` + "`" + `json:"attributes"` + "`" + `
+	}{
+		Type: {{ printf "%q" .EnvType }},
+		ID:   ut.{{ .IDField }},
+		Attributes: &{{ .AttrsName }}{
+{{ range .Fields }}			{{ . }}: ut.{{ . }},
+{{ end }}		},
+	})
+}
+
+// UnmarshalJSON decodes the {{ .Name }} from a {{ printf "%q" .EnvType }} envelope.
+func (ut *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	var env struct {
+		Type       string ` + "`" + `json:"type"` + "`" + `
+		ID         {{ .IDType }} ` + "`" + `json:"{{ .IDTag }}"` + "`" + `
+		Attributes {{ .AttrsName }} ` + "`" + `json:"attributes"` + "`" + `
+	}
+	if err := json.Unmarshal(data, &env); err != nil {
+		return err
+	}
+	if env.Type != {{ printf "%q" .EnvType }} {
+		return fmt.Errorf("invalid envelope type %q, expected %q", env.Type, {{ printf "%q" .EnvType }})
+	}
+	ut.{{ .IDField }} = env.ID
+{{ range .Fields }}	ut.{{ . }} = env.Attributes.{{ . }}
+{{ end }}	return nil
+}
+`
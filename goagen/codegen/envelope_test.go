@@ -0,0 +1,101 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoEnvelopeMarshaler", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "User",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"id":   &design.AttributeDefinition{Type: design.String},
+					"name": &design.AttributeDefinition{Type: design.String},
+					"age":  &design.AttributeDefinition{Type: design.Integer},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"id", "name"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoEnvelopeMarshaler(ut, "users")
+	})
+
+	It("generates the envelope marshaling methods", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(envelopeCode))
+	})
+
+	Context("with a type that has no id attribute", func() {
+		BeforeEach(func() {
+			delete(ut.Type.ToObject(), "id")
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an attribute whose generator also produces JSON methods", func() {
+		BeforeEach(func() {
+			ut.Type.ToObject()["name"].Metadata = dslengine.MetadataDefinition{codegen.InternKey: nil}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring(codegen.InternKey))
+		})
+	})
+})
+
+const envelopeCode = `// userAttributes holds the User fields encoded under the "attributes" key.
+type userAttributes struct {
+	Age *int ` + "`" + `form:"age,omitempty" json:"age,omitempty" xml:"age,omitempty"` + "`" + `
+	Name string ` + "`" + `form:"name" json:"name" xml:"name"` + "`" + `
+}
+
+// MarshalJSON encodes the User in a "users" envelope.
+func (ut *User) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Type       string ` + "`" + `json:"type"` + "`" + `
+		ID         string ` + "`" + `json:"id"` + "`" + `
+		Attributes *userAttributes ` + "`" + `json:"attributes"` + "`" + `
+	}{
+		Type: "users",
+		ID:   ut.ID,
+		Attributes: &userAttributes{
+			Age: ut.Age,
+			Name: ut.Name,
+		},
+	})
+}
+
+// UnmarshalJSON decodes the User from a "users" envelope.
+func (ut *User) UnmarshalJSON(data []byte) error {
+	var env struct {
+		Type       string ` + "`" + `json:"type"` + "`" + `
+		ID         string ` + "`" + `json:"id"` + "`" + `
+		Attributes userAttributes ` + "`" + `json:"attributes"` + "`" + `
+	}
+	if err := json.Unmarshal(data, &env); err != nil {
+		return err
+	}
+	if env.Type != "users" {
+		return fmt.Errorf("invalid envelope type %q, expected %q", env.Type, "users")
+	}
+	ut.ID = env.ID
+	ut.Age = env.Attributes.Age
+	ut.Name = env.Attributes.Name
+	return nil
+}
+`
@@ -0,0 +1,61 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var fieldEnumT *template.Template
+
+func init() {
+	var err error
+	if fieldEnumT, err = template.New("fieldEnum").Parse(fieldEnumTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoFieldEnum produces the Go code that defines the typed enum of the names of the fields of the
+// struct generated for the given user type. The enum type is named after the user type, e.g.
+// "AccountField", and defines one constant per field, e.g. "AccountFieldName", whose value is the
+// Go name of the field. The Values method returns all the constants sorted by attribute name so
+// that query builders and projections may refer to fields in a type safe way.
+// The function returns an error if ut is not an object.
+func GoFieldEnum(ut *design.UserTypeDefinition) (string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	name := Goify(ut.TypeName, true)
+	enum := name + "Field"
+	var fields []map[string]interface{}
+	obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		field := GoifyAtt(att, n, true)
+		fields = append(fields, map[string]interface{}{
+			"Const": enum + field,
+			"Field": field,
+		})
+		return nil
+	})
+	data := map[string]interface{}{
+		"TypeName": name,
+		"Enum":     enum,
+		"Fields":   fields,
+	}
+	return RunTemplate(fieldEnumT, data), nil
+}
+
+const fieldEnumTmpl = `// {{ .Enum }} is the name of a field of {{ .TypeName }}.
+type {{ .Enum }} string
+
+const (
+{{ range .Fields }}	// {{ .Const }} is the name of the {{ .Field }} field of {{ $.TypeName }}.
+	{{ .Const }} {{ $.Enum }} = {{ printf "%q" .Field }}
+{{ end }})
+
+// Values returns the names of all the fields of {{ .TypeName }}.
+func ({{ .Enum }}) Values() []{{ .Enum }} {
+	return []{{ .Enum }}{ {{- range $i, $f := .Fields }}{{ if $i }}, {{ end }}{{ $f.Const }}{{ end -}} }
+}
+`
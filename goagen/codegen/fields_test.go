@@ -0,0 +1,81 @@
+package codegen_test
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoFieldEnum", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "user",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name":       &design.AttributeDefinition{Type: design.String},
+					"age":        &design.AttributeDefinition{Type: design.Integer},
+					"account_id": &design.AttributeDefinition{Type: design.Integer},
+					"team": &design.AttributeDefinition{
+						Type:     design.String,
+						Metadata: dslengine.MetadataDefinition{"struct:field:name": {"Squad"}},
+					},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoFieldEnum(ut)
+	})
+
+	It("generates one constant per field and the Values method", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(fieldEnumCode))
+	})
+
+	It("uses the Goified field names as constant values", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		for n, att := range ut.Type.ToObject() {
+			field := codegen.GoifyAtt(att, n, true)
+			Ω(code).Should(ContainSubstring(fmt.Sprintf("\tUserField%s UserField = %q\n", field, field)))
+		}
+	})
+
+	Context("with a type that is not an object", func() {
+		BeforeEach(func() {
+			ut.Type = design.String
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const fieldEnumCode = `// UserField is the name of a field of User.
+type UserField string
+
+const (
+	// UserFieldAccountID is the name of the AccountID field of User.
+	UserFieldAccountID UserField = "AccountID"
+	// UserFieldAge is the name of the Age field of User.
+	UserFieldAge UserField = "Age"
+	// UserFieldName is the name of the Name field of User.
+	UserFieldName UserField = "Name"
+	// UserFieldSquad is the name of the Squad field of User.
+	UserFieldSquad UserField = "Squad"
+)
+
+// Values returns the names of all the fields of User.
+func (UserField) Values() []UserField {
+	return []UserField{UserFieldAccountID, UserFieldAge, UserFieldName, UserFieldSquad}
+}
+`
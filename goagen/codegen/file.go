@@ -0,0 +1,312 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+type (
+	// Option configures the file produced by GenerateFile.
+	Option func(*fileOptions)
+
+	// fileOptions holds the GenerateFile settings.
+	fileOptions struct {
+		header    string
+		jsonTags  bool
+		goVersion string
+	}
+)
+
+// WithHeader sets the comment written at the top of the file produced by GenerateFile, before
+// the package clause. Each line of header is prefixed with "// ".
+func WithHeader(header string) Option {
+	return func(o *fileOptions) { o.header = header }
+}
+
+// WithoutJSONTags disables the generation of struct field tags.
+func WithoutJSONTags() Option {
+	return func(o *fileOptions) { o.jsonTags = false }
+}
+
+// WithGoVersion sets the version of Go targeted by the file produced by GenerateFile, e.g. "1.24".
+// Targeting Go 1.24 or later renders the optional primitive fields of the generated structs using
+// the generic goa.Optional type instead of pointers. The fields rely on the "omitzero" JSON tag
+// option to omit the absent values which earlier versions of encoding/json ignore.
+func WithGoVersion(version string) Option {
+	return func(o *fileOptions) { o.goVersion = version }
+}
+
+// GenerateFile produces a complete gofmt'd Go source file that declares the given user or media
+// types in package pkg. The import block is computed with RequiredImports and the types are
+// declared in the order computed by SortTypes followed by the identifier types computed by
+// GoIDTypes. The function returns an error if a data structure is not a user or media type, if the
+// identifier types conflict or if the resulting code cannot be formatted.
+func GenerateFile(pkg string, types []design.DataStructure, opts ...Option) ([]byte, error) {
+	o := &fileOptions{jsonTags: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+	for _, ds := range types {
+		if userTypeName(ds) == "" {
+			return nil, fmt.Errorf("cannot generate declaration for data structure of type %T, must be a user or media type", ds)
+		}
+	}
+	ids, err := GoIDTypes(types)
+	if err != nil {
+		return nil, err
+	}
+	// Go allows forward references, cycles are fine.
+	sorted, _ := SortTypes(types)
+	generics := goVersionAtLeast(o.goVersion, 1, 24)
+	var optional bool
+	if generics {
+		for _, ds := range types {
+			if hasOptionalFields(ds.Definition()) {
+				optional = true
+				break
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if o.header != "" {
+		buf.WriteString(Comment(o.header))
+		buf.WriteString("\n\n")
+	}
+	buf.WriteString("package " + pkg + "\n\n")
+	imports := RequiredImports(types)
+	if optional {
+		imports = addImport(imports, SimpleImport("github.com/goadesign/goa"))
+	}
+	if len(imports) > 0 {
+		buf.WriteString("import (\n")
+		for _, imp := range imports {
+			buf.WriteString("\t" + imp.Code() + "\n")
+		}
+		buf.WriteString(")\n\n")
+	}
+	for _, ds := range sorted {
+		buf.WriteString(fmt.Sprintf("// %s\ntype %s %s\n\n",
+			GoTypeDesc(ds.(design.DataType), true), Goify(userTypeName(ds), true), goTypeDef(ds, 0, o.jsonTags, false, generics)))
+	}
+	if ids != "" {
+		buf.WriteString(ids + "\n")
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated file: %s", err)
+	}
+	return src, nil
+}
+
+// RequiredImports returns the imports needed by the declarations of the Go types generated for
+// the given data structures sorted by path. The attributes of the user types referred to by the
+// data structures are not considered as their declarations are generated separately.
+func RequiredImports(types []design.DataStructure) []*ImportSpec {
+	paths := make(map[string]*ImportSpec)
+	for _, ds := range types {
+		collectImports(ds.Definition(), paths)
+	}
+	keys := make([]string, len(paths))
+	i := 0
+	for p := range paths {
+		keys[i] = p
+		i++
+	}
+	sort.Strings(keys)
+	imports := make([]*ImportSpec, len(keys))
+	for i, p := range keys {
+		imports[i] = paths[p]
+	}
+	return imports
+}
+
+// addImport inserts imp in the given imports sorted by path unless it is already present.
+func addImport(imports []*ImportSpec, imp *ImportSpec) []*ImportSpec {
+	i := sort.Search(len(imports), func(i int) bool { return imports[i].Path >= imp.Path })
+	if i < len(imports) && imports[i].Path == imp.Path {
+		return imports
+	}
+	imports = append(imports, nil)
+	copy(imports[i+1:], imports[i:])
+	imports[i] = imp
+	return imports
+}
+
+// collectImports records the imports needed by the Go type generated for att in paths.
+func collectImports(att *design.AttributeDefinition, paths map[string]*ImportSpec) {
+	switch actual := att.Type.(type) {
+	case design.Primitive:
+		switch actual.Kind() {
+		case design.DateTimeKind:
+			paths["time"] = SimpleImport("time")
+		case design.UUIDKind:
+			paths["github.com/satori/go.uuid"] = NewImport("uuid", "github.com/satori/go.uuid")
+		case design.LanguageKind:
+			paths["golang.org/x/text/language"] = SimpleImport("golang.org/x/text/language")
+		}
+	case *design.Array:
+		collectImports(actual.ElemType, paths)
+	case *design.Hash:
+		collectImports(actual.KeyType, paths)
+		collectImports(actual.ElemType, paths)
+	case design.Object:
+		for n, catt := range actual {
+			if sqlNullType(att, n) != "" || durationType(catt) != "" {
+				paths["github.com/goadesign/goa"] = SimpleImport("github.com/goadesign/goa")
+				continue
+			}
+			if atomicType(catt) != "" {
+				paths["sync/atomic"] = SimpleImport("sync/atomic")
+				continue
+			}
+			collectImports(catt, paths)
+		}
+	}
+}
+
+// SortTypes returns the given data structures sorted so that user and media types are listed
+// after the types they refer to. Ties are broken by type name so that the order is stable across
+// runs. If types refer to each other the function still returns a complete order, along with an
+// error describing the cycles.
+func SortTypes(types []design.DataStructure) ([]design.DataStructure, error) {
+	byName := make(map[string]design.DataStructure, len(types))
+	for _, ds := range types {
+		if n := userTypeName(ds); n != "" {
+			byName[n] = ds
+		}
+	}
+	input := make([]design.DataStructure, len(types))
+	copy(input, types)
+	sort.Stable(byTypeName(input))
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	var (
+		sorted []design.DataStructure
+		cycles []string
+		state  = make(map[design.DataStructure]int, len(types))
+		path   []string
+		visit  func(design.DataStructure)
+	)
+	visit = func(ds design.DataStructure) {
+		switch state[ds] {
+		case done:
+			return
+		case visiting:
+			n := userTypeName(ds)
+			for i := len(path) - 1; i >= 0; i-- {
+				if path[i] == n {
+					cycles = append(cycles, strings.Join(append(path[i:len(path):len(path)], n), " -> "))
+					break
+				}
+			}
+			return
+		}
+		state[ds] = visiting
+		path = append(path, userTypeName(ds))
+		deps := referencedTypeNames(ds.Definition(), nil)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if d, ok := byName[dep]; ok {
+				visit(d)
+			}
+		}
+		path = path[:len(path)-1]
+		state[ds] = done
+		sorted = append(sorted, ds)
+	}
+	for _, ds := range input {
+		visit(ds)
+	}
+	if len(cycles) > 0 {
+		return sorted, fmt.Errorf("type cycles detected: %s", strings.Join(cycles, ", "))
+	}
+	return sorted, nil
+}
+
+// ReferencedTypes returns the user and media types transitively referred to by the attributes of
+// ds, excluding ds itself. Each type is listed once and after the types it refers to so that the
+// result can be given to GenerateFile together with ds. Object attributes are traversed in
+// alphabetical order and cycles are broken at the type that closes them.
+func ReferencedTypes(ds design.DataStructure) []design.DataStructure {
+	var (
+		res   []design.DataStructure
+		seen  = map[string]bool{userTypeName(ds): true}
+		visit func(*design.AttributeDefinition)
+	)
+	visit = func(att *design.AttributeDefinition) {
+		switch actual := att.Type.(type) {
+		case *design.UserTypeDefinition, *design.MediaTypeDefinition:
+			ref := actual.(design.DataStructure)
+			name := userTypeName(ref)
+			if seen[name] {
+				return
+			}
+			seen[name] = true
+			visit(ref.Definition())
+			res = append(res, ref)
+		case *design.Array:
+			visit(actual.ElemType)
+		case *design.Hash:
+			visit(actual.KeyType)
+			visit(actual.ElemType)
+		case design.Object:
+			actual.IterateAttributes(func(_ string, catt *design.AttributeDefinition) error {
+				visit(catt)
+				return nil
+			})
+		}
+	}
+	visit(ds.Definition())
+	return res
+}
+
+// byTypeName implements sort.Interface to sort data structures by type name.
+type byTypeName []design.DataStructure
+
+func (b byTypeName) Len() int           { return len(b) }
+func (b byTypeName) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byTypeName) Less(i, j int) bool { return userTypeName(b[i]) < userTypeName(b[j]) }
+
+// userTypeName returns the type name of ds if it is a user or media type, the empty string
+// otherwise.
+func userTypeName(ds design.DataStructure) string {
+	switch actual := ds.(type) {
+	case *design.UserTypeDefinition:
+		return actual.TypeName
+	case *design.MediaTypeDefinition:
+		return actual.TypeName
+	default:
+		return ""
+	}
+}
+
+// referencedTypeNames appends the names of the user types directly referred to by att to names.
+// Object attributes are traversed in alphabetical order.
+func referencedTypeNames(att *design.AttributeDefinition, names []string) []string {
+	switch actual := att.Type.(type) {
+	case *design.UserTypeDefinition:
+		return append(names, actual.TypeName)
+	case *design.MediaTypeDefinition:
+		return append(names, actual.TypeName)
+	case *design.Array:
+		return referencedTypeNames(actual.ElemType, names)
+	case *design.Hash:
+		names = referencedTypeNames(actual.KeyType, names)
+		return referencedTypeNames(actual.ElemType, names)
+	case design.Object:
+		actual.IterateAttributes(func(_ string, catt *design.AttributeDefinition) error {
+			names = referencedTypeNames(catt, names)
+			return nil
+		})
+	}
+	return names
+}
@@ -0,0 +1,309 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GenerateFile", func() {
+	var types []design.DataStructure
+	var opts []codegen.Option
+	var code []byte
+	var err error
+
+	BeforeEach(func() {
+		address := &design.UserTypeDefinition{
+			TypeName: "Address",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"city": &design.AttributeDefinition{Type: design.String},
+				},
+			},
+		}
+		user := &design.UserTypeDefinition{
+			TypeName: "User",
+			AttributeDefinition: &design.AttributeDefinition{
+				Description: "User describes a user.",
+				Type: design.Object{
+					"address":   &design.AttributeDefinition{Type: address},
+					"createdAt": &design.AttributeDefinition{Type: design.DateTime},
+					"name":      &design.AttributeDefinition{Type: design.String},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+			},
+		}
+		types = []design.DataStructure{user, address}
+		opts = []codegen.Option{codegen.WithHeader("Code generated by goagen, DO NOT EDIT.")}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GenerateFile("app", types, opts...)
+	})
+
+	It("generates the file with the import block and the types in dependency order", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(code)).Should(Equal(generatedFileCode))
+	})
+
+	Context("targeting Go 1.24", func() {
+		BeforeEach(func() {
+			counter := &design.UserTypeDefinition{
+				TypeName: "Counter",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"count": &design.AttributeDefinition{Type: design.Integer},
+						"name":  &design.AttributeDefinition{Type: design.String},
+					},
+					Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+				},
+			}
+			types = []design.DataStructure{counter}
+			opts = []codegen.Option{codegen.WithGoVersion("1.24")}
+		})
+
+		It("renders optional fields with the generic Optional type", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(code)).Should(Equal(optionalFileCode))
+		})
+
+		It("generates files of the same package that compile and omit absent values", func() {
+			gauge := &design.UserTypeDefinition{
+				TypeName: "Gauge",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"level": &design.AttributeDefinition{Type: design.Number}},
+				},
+			}
+			other, err := codegen.GenerateFile("app", []design.DataStructure{gauge}, opts...)
+			Ω(err).ShouldNot(HaveOccurred())
+			out, err := goTest(map[string]string{
+				"counter.go":      string(code),
+				"gauge.go":        string(other),
+				"counter_test.go": optionalUsageTest,
+			})
+			Ω(err).ShouldNot(HaveOccurred(), out)
+		})
+
+		for _, v := range []string{"go1.24.1", "1.25", "2.0"} {
+			v := v
+			Context("and the later version "+v, func() {
+				BeforeEach(func() {
+					opts = []codegen.Option{codegen.WithGoVersion(v)}
+				})
+
+				It("renders optional fields with the generic Optional type", func() {
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(string(code)).Should(Equal(optionalFileCode))
+				})
+			})
+		}
+
+		for _, v := range []string{"go1.17", "1.18", "go1.23.4", "", "devel"} {
+			v := v
+			Context("and the earlier or unknown version "+v, func() {
+				BeforeEach(func() {
+					opts = []codegen.Option{codegen.WithGoVersion(v)}
+				})
+
+				It("renders optional fields with pointers", func() {
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(string(code)).Should(ContainSubstring("Count *int"))
+					Ω(string(code)).ShouldNot(ContainSubstring("Optional"))
+				})
+			})
+		}
+	})
+
+	Context("with a data structure that is not a user type", func() {
+		BeforeEach(func() {
+			types = append(types, &design.AttributeDefinition{Type: design.String})
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("RequiredImports", func() {
+	It("returns the sorted imports needed by the type declarations", func() {
+		ut := &design.UserTypeDefinition{
+			TypeName: "Event",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"at":    &design.AttributeDefinition{Type: design.DateTime},
+					"ids":   &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.UUID}}},
+					"other": &design.AttributeDefinition{Type: &design.Hash{KeyType: &design.AttributeDefinition{Type: design.String}, ElemType: &design.AttributeDefinition{Type: design.DateTime}}},
+				},
+			},
+		}
+		imports := codegen.RequiredImports([]design.DataStructure{ut})
+		Ω(imports).Should(Equal([]*codegen.ImportSpec{
+			codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+			codegen.SimpleImport("time"),
+		}))
+	})
+})
+
+const generatedFileCode = `// Code generated by goagen, DO NOT EDIT.
+
+package app
+
+import (
+	"time"
+)
+
+// Address user type.
+type Address struct {
+	City *string ` + "`" + `form:"city,omitempty" json:"city,omitempty" xml:"city,omitempty"` + "`" + `
+}
+
+// User describes a user.
+type User struct {
+	Address   *Address   ` + "`" + `form:"address,omitempty" json:"address,omitempty" xml:"address,omitempty"` + "`" + `
+	CreatedAt *time.Time ` + "`" + `form:"createdAt,omitempty" json:"createdAt,omitempty" xml:"createdAt,omitempty"` + "`" + `
+	Name      string     ` + "`" + `form:"name" json:"name" xml:"name"` + "`" + `
+}
+`
+
+var _ = Describe("SortTypes", func() {
+	var a, b, c *design.UserTypeDefinition
+	var sorted []design.DataStructure
+	var err error
+
+	newType := func(name string) *design.UserTypeDefinition {
+		return &design.UserTypeDefinition{
+			TypeName:            name,
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+		}
+	}
+
+	BeforeEach(func() {
+		a, b, c = newType("A"), newType("B"), newType("C")
+	})
+
+	JustBeforeEach(func() {
+		sorted, err = codegen.SortTypes([]design.DataStructure{c, a, b})
+	})
+
+	Context("with a linear dependency chain", func() {
+		BeforeEach(func() {
+			c.Type.ToObject()["b"] = &design.AttributeDefinition{Type: b}
+			b.Type.ToObject()["a"] = &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: a}}}
+		})
+
+		It("lists the types after their dependencies", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(sorted).Should(Equal([]design.DataStructure{a, b, c}))
+		})
+	})
+
+	Context("with independent types", func() {
+		It("sorts the types by name", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(sorted).Should(Equal([]design.DataStructure{a, b, c}))
+		})
+	})
+
+	Context("with a cycle", func() {
+		BeforeEach(func() {
+			a.Type.ToObject()["c"] = &design.AttributeDefinition{Type: c}
+			c.Type.ToObject()["a"] = &design.AttributeDefinition{Type: a}
+		})
+
+		It("returns a complete order and reports the cycle", func() {
+			Ω(sorted).Should(Equal([]design.DataStructure{c, a, b}))
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(Equal("type cycles detected: A -> C -> A"))
+		})
+	})
+})
+
+var _ = Describe("ReferencedTypes", func() {
+	var root, a, b, c *design.UserTypeDefinition
+	var refs []design.DataStructure
+
+	newType := func(name string) *design.UserTypeDefinition {
+		return &design.UserTypeDefinition{
+			TypeName:            name,
+			AttributeDefinition: &design.AttributeDefinition{Type: design.Object{}},
+		}
+	}
+
+	BeforeEach(func() {
+		root, a, b, c = newType("Root"), newType("A"), newType("B"), newType("C")
+		root.Type.ToObject()["b"] = &design.AttributeDefinition{Type: b}
+		root.Type.ToObject()["as"] = &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: a}}}
+		b.Type.ToObject()["c"] = &design.AttributeDefinition{Type: c}
+	})
+
+	JustBeforeEach(func() {
+		refs = codegen.ReferencedTypes(root)
+	})
+
+	It("lists the referenced types after their dependencies", func() {
+		Ω(refs).Should(Equal([]design.DataStructure{a, c, b}))
+	})
+
+	Context("with types referenced multiple times and cycles", func() {
+		BeforeEach(func() {
+			a.Type.ToObject()["c"] = &design.AttributeDefinition{Type: c}
+			c.Type.ToObject()["root"] = &design.AttributeDefinition{Type: root}
+			c.Type.ToObject()["b"] = &design.AttributeDefinition{Type: b}
+		})
+
+		It("lists each type once and excludes the root", func() {
+			Ω(refs).Should(Equal([]design.DataStructure{b, c, a}))
+		})
+	})
+})
+
+const optionalFileCode = `package app
+
+import (
+	"github.com/goadesign/goa"
+)
+
+// Counter user type.
+type Counter struct {
+	Count goa.Optional[int] ` + "`" + `form:"count,omitempty" json:"count,omitzero" xml:"count,omitempty"` + "`" + `
+	Name  string            ` + "`" + `form:"name" json:"name" xml:"name"` + "`" + `
+}
+`
+
+const optionalUsageTest = `package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/goadesign/goa"
+)
+
+func TestOptionalFields(t *testing.T) {
+	b, err := json.Marshal(&Counter{Name: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != ` + "`" + `{"name":"c"}` + "`" + ` {
+		t.Errorf("unexpected encoding of absent value %s", b)
+	}
+	b, err = json.Marshal(&Counter{Count: goa.Some(0), Name: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != ` + "`" + `{"count":0,"name":"c"}` + "`" + ` {
+		t.Errorf("unexpected encoding of present value %s", b)
+	}
+	var c Counter
+	if err := json.Unmarshal(b, &c); err != nil || !c.Count.Present() {
+		t.Errorf("unexpected decoded value %+v, %v", c, err)
+	}
+	var g Gauge
+	if err := json.Unmarshal([]byte(` + "`" + `{}` + "`" + `), &g); err != nil || g.Level.Present() {
+		t.Errorf("unexpected decoded value %+v, %v", g, err)
+	}
+}
+`
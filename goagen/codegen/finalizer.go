@@ -71,13 +71,17 @@ func (f *Finalizer) recurse(att *design.AttributeDefinition, target string, dept
 	if o := att.Type.ToObject(); o != nil {
 		o.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
 			if att.HasDefaultValue(n) {
+				defaultVal := printVal(catt.Type, catt.DefaultValue)
+				if id := idType(catt); id != "" {
+					defaultVal = fmt.Sprintf("%s(%s)", id, defaultVal)
+				}
 				data := map[string]interface{}{
 					"target":     target,
 					"field":      n,
 					"catt":       catt,
 					"depth":      depth,
 					"isDatetime": catt.Type == design.DateTime,
-					"defaultVal": printVal(catt.Type, catt.DefaultValue),
+					"defaultVal": defaultVal,
 				}
 				if !first {
 					buf.WriteByte('\n')
@@ -121,8 +125,13 @@ func printVal(t design.DataType, val interface{}) string {
 	case t.IsPrimitive():
 		// For primitive types, simply print the value
 		s := fmt.Sprintf("%#v", val)
-		if t == design.DateTime {
+		switch t.Kind() {
+		case design.DateTimeKind:
 			s = fmt.Sprintf("time.Parse(time.RFC3339, %s)", s)
+		case design.UUIDKind:
+			s = fmt.Sprintf("uuid.FromStringOrNil(%s)", s)
+		case design.LanguageKind:
+			s = fmt.Sprintf("language.MustParse(%s)", s)
 		}
 		return s
 	case t.IsHash():
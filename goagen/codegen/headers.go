@@ -0,0 +1,113 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var headersT *template.Template
+
+func init() {
+	var err error
+	if headersT, err = template.New("headers").Parse(headersTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoHeadersStruct produces the Go code of the struct that holds the typed values of the headers of
+// the given action, e.g. "ShowUserHeaders" for the "show" action of the "user" resource, together
+// with the function that parses them from a http.Header, e.g. "ParseShowUserHeaders". The struct
+// has one field per header declared by the action or its resource, fields of optional headers are
+// pointers. The parse function converts the header values with strconv, time.Parse or
+// uuid.FromString according to their design type and returns an error if a required header is
+// missing or if a value cannot be converted.
+// GoHeadersStruct returns an empty string if the action declares no header and an error if a
+// header is not a primitive or is of type any.
+func GoHeadersStruct(a *design.ActionDefinition) (string, error) {
+	var fields []map[string]interface{}
+	err := a.IterateHeaders(func(name string, required bool, h *design.AttributeDefinition) error {
+		field := Goify(name, true)
+		raw := "raw" + field
+		var parse, kind string
+		switch h.Type.Kind() {
+		case design.StringKind:
+		case design.BooleanKind:
+			kind = "boolean"
+			parse = fmt.Sprintf("strconv.ParseBool(%s)", raw)
+		case design.IntegerKind:
+			kind = "integer"
+			parse = fmt.Sprintf("strconv.Atoi(%s)", raw)
+		case design.NumberKind:
+			kind = "number"
+			parse = fmt.Sprintf("strconv.ParseFloat(%s, 64)", raw)
+		case design.DateTimeKind:
+			kind = "datetime"
+			parse = fmt.Sprintf("time.Parse(time.RFC3339, %s)", raw)
+		case design.UUIDKind:
+			kind = "uuid"
+			parse = fmt.Sprintf("uuid.FromString(%s)", raw)
+		default:
+			return fmt.Errorf("%s: header %s of type %s is not supported", a.Context(), name, h.Type.Name())
+		}
+		typ := GoNativeType(h.Type)
+		if !required {
+			typ = "*" + typ
+		}
+		fields = append(fields, map[string]interface{}{
+			"Header":   name,
+			"Field":    field,
+			"Raw":      raw,
+			"Type":     typ,
+			"Kind":     kind,
+			"Required": required,
+			"Parse":    parse,
+		})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(fields) == 0 {
+		return "", nil
+	}
+	name := Goify(a.Name+strings.Title(a.Parent.Name), true) + "Headers"
+	data := map[string]interface{}{
+		"Name":     name,
+		"Action":   a.Name,
+		"Resource": a.Parent.Name,
+		"Fields":   fields,
+	}
+	return RunTemplate(headersT, data), nil
+}
+
+const headersTmpl = `// {{ .Name }} holds the typed values of the headers of the {{ .Action }} action of {{ .Resource }}.
+type {{ .Name }} struct {
+{{ range .Fields }}	// {{ .Field }} is the value of the {{ printf "%q" .Header }} header.
+	{{ .Field }} {{ .Type }}
+{{ end }}}
+
+// Parse{{ .Name }} parses the headers of the {{ .Action }} action of {{ .Resource }} from h.
+func Parse{{ .Name }}(h http.Header) (*{{ .Name }}, error) {
+	var err error
+	res := &{{ .Name }}{}
+{{ range .Fields }}	{{ .Raw }} := h.Get({{ printf "%q" .Header }})
+{{ if .Required }}	if {{ .Raw }} == "" {
+		err = goa.MergeErrors(err, goa.MissingHeaderError({{ printf "%q" .Header }}))
+	} else {
+{{ else }}	if {{ .Raw }} != "" {
+{{ end }}{{ if .Parse }}		if v, err2 := {{ .Parse }}; err2 == nil {
+			res.{{ .Field }} = {{ if not .Required }}&{{ end }}v
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError({{ printf "%q" .Header }}, {{ .Raw }}, {{ printf "%q" .Kind }}))
+		}
+{{ else }}		res.{{ .Field }} = {{ if not .Required }}&{{ end }}{{ .Raw }}
+{{ end }}	}
+{{ end }}	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+`
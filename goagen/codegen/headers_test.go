@@ -0,0 +1,95 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoHeadersStruct", func() {
+	var action *design.ActionDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		res := &design.ResourceDefinition{Name: "user"}
+		action = &design.ActionDefinition{
+			Name:   "show",
+			Parent: res,
+			Headers: &design.AttributeDefinition{
+				Type: design.Object{
+					"X-Request-Id": &design.AttributeDefinition{Type: design.String},
+					"X-Limit":      &design.AttributeDefinition{Type: design.Integer},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"X-Request-Id"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoHeadersStruct(action)
+	})
+
+	It("generates the headers struct and its parse function", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(headersCode))
+	})
+
+	Context("with no header", func() {
+		BeforeEach(func() {
+			action.Headers = nil
+		})
+
+		It("generates nothing", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(BeEmpty())
+		})
+	})
+
+	Context("with a header that is not a primitive", func() {
+		BeforeEach(func() {
+			action.Headers.Type.ToObject()["X-Tags"] = &design.AttributeDefinition{
+				Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}},
+			}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const headersCode = `// ShowUserHeaders holds the typed values of the headers of the show action of user.
+type ShowUserHeaders struct {
+	// XLimit is the value of the "X-Limit" header.
+	XLimit *int
+	// XRequestID is the value of the "X-Request-Id" header.
+	XRequestID string
+}
+
+// ParseShowUserHeaders parses the headers of the show action of user from h.
+func ParseShowUserHeaders(h http.Header) (*ShowUserHeaders, error) {
+	var err error
+	res := &ShowUserHeaders{}
+	rawXLimit := h.Get("X-Limit")
+	if rawXLimit != "" {
+		if v, err2 := strconv.Atoi(rawXLimit); err2 == nil {
+			res.XLimit = &v
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("X-Limit", rawXLimit, "integer"))
+		}
+	}
+	rawXRequestID := h.Get("X-Request-Id")
+	if rawXRequestID == "" {
+		err = goa.MergeErrors(err, goa.MissingHeaderError("X-Request-Id"))
+	} else {
+		res.XRequestID = rawXRequestID
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+`
@@ -164,3 +164,92 @@ func SnakeCase(name string) string {
 	}
 	return b.String()
 }
+
+// jsonMethodsGenerators lists the generators that produce the MarshalJSON or UnmarshalJSON
+// method of the struct generated for a user type. Each generator is named after the metadata that
+// drives it or after the design property it handles and comes with the function that reports
+// whether it applies to a type: applies returns the name of the attribute that triggers the
+// generator, the empty string for types flagged as a whole.
+var jsonMethodsGenerators = []struct {
+	key     string
+	applies func(*design.UserTypeDefinition) (string, bool)
+}{
+	{AtomicKey, attributeMetadata(AtomicKey)},
+	{LazyKey, attributeMetadata(LazyKey)},
+	{InternKey, attributeMetadata(InternKey)},
+	{FieldTransformKey, attributeMetadata(FieldTransformKey)},
+	{"uniqueItems", uniqueItemsAttribute},
+	{"defaults", defaultsAttribute},
+	{"envelope", envelopeType},
+}
+
+// jsonMethodsConflict returns an error if a generator listed in jsonMethodsGenerators other than
+// the one named key applies to ut: the generated types cannot define more than one MarshalJSON or
+// UnmarshalJSON method.
+func jsonMethodsConflict(ut *design.UserTypeDefinition, key string) error {
+	if ut.Type.ToObject() == nil {
+		return nil
+	}
+	for _, gen := range jsonMethodsGenerators {
+		if gen.key == key {
+			continue
+		}
+		n, ok := gen.applies(ut)
+		if !ok {
+			continue
+		}
+		if n == "" {
+			return fmt.Errorf("type %s: %s cannot be combined with %s, both produce JSON methods",
+				ut.TypeName, key, gen.key)
+		}
+		return fmt.Errorf("type %s: %s cannot be combined with %s (attribute %s), both produce JSON methods",
+			ut.TypeName, key, gen.key, n)
+	}
+	return nil
+}
+
+// attributeMetadata returns a function that returns the name of the first attribute of a user
+// type that defines the metadata with the given key.
+func attributeMetadata(key string) func(*design.UserTypeDefinition) (string, bool) {
+	return func(ut *design.UserTypeDefinition) (string, bool) {
+		return firstAttribute(ut, func(n string, att *design.AttributeDefinition) bool {
+			_, ok := att.Metadata[key]
+			return ok
+		})
+	}
+}
+
+// uniqueItemsAttribute returns the name of the first array attribute of ut that defines the
+// uniqueItems validation, see GoUniqueUnmarshaler.
+func uniqueItemsAttribute(ut *design.UserTypeDefinition) (string, bool) {
+	return firstAttribute(ut, func(n string, att *design.AttributeDefinition) bool {
+		return att.Type.IsArray() && att.Validation != nil && att.Validation.UniqueItems
+	})
+}
+
+// defaultsAttribute returns the name of the first optional attribute of ut that defines a default
+// value, see GoDefaultsType.
+func defaultsAttribute(ut *design.UserTypeDefinition) (string, bool) {
+	return firstAttribute(ut, func(n string, att *design.AttributeDefinition) bool {
+		return ut.HasDefaultValue(n) && !ut.IsRequired(n)
+	})
+}
+
+// envelopeType reports whether ut defines the EnvelopeKey metadata, see GoEnvelopeMarshaler.
+func envelopeType(ut *design.UserTypeDefinition) (string, bool) {
+	_, ok := ut.Metadata[EnvelopeKey]
+	return "", ok
+}
+
+// firstAttribute returns the name of the first attribute of ut in alphabetical order for which
+// match returns true.
+func firstAttribute(ut *design.UserTypeDefinition, match func(string, *design.AttributeDefinition) bool) (string, bool) {
+	var found string
+	ut.Type.ToObject().IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		if found == "" && match(n, att) {
+			found = n
+		}
+		return nil
+	})
+	return found, found != ""
+}
@@ -0,0 +1,83 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON methods generators", func() {
+	type generator struct {
+		key      string
+		generate func(*design.UserTypeDefinition) (string, error)
+		apply    func(*design.UserTypeDefinition)
+	}
+
+	flag := func(name string, t design.DataType, key string, val ...string) func(*design.UserTypeDefinition) {
+		return func(ut *design.UserTypeDefinition) {
+			ut.Type.ToObject()[name] = &design.AttributeDefinition{
+				Type:     t,
+				Metadata: dslengine.MetadataDefinition{key: val},
+			}
+		}
+	}
+
+	generators := []generator{
+		{codegen.AtomicKey, codegen.GoAtomicAccessors, flag("hits", design.Integer, codegen.AtomicKey)},
+		{codegen.LazyKey, codegen.GoLazyAccessors, flag("total", design.Integer, codegen.LazyKey)},
+		{codegen.InternKey, codegen.GoInternUnmarshaler, flag("tenant", design.String, codegen.InternKey)},
+		{codegen.FieldTransformKey, codegen.GoTransformUnmarshaler, flag("email", design.String, codegen.FieldTransformKey, "lower")},
+		{"uniqueItems", codegen.GoUniqueUnmarshaler, func(ut *design.UserTypeDefinition) {
+			ut.Type.ToObject()["tags"] = &design.AttributeDefinition{
+				Type:       &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}},
+				Validation: &dslengine.ValidationDefinition{UniqueItems: true},
+			}
+		}},
+		{"defaults", codegen.GoDefaultsType, func(ut *design.UserTypeDefinition) {
+			ut.Type.ToObject()["quota"] = &design.AttributeDefinition{Type: design.Integer, DefaultValue: 10}
+		}},
+		{"envelope", func(ut *design.UserTypeDefinition) (string, error) {
+			return codegen.GoEnvelopeMarshaler(ut, "accounts")
+		}, func(ut *design.UserTypeDefinition) {
+			ut.Metadata = dslengine.MetadataDefinition{codegen.EnvelopeKey: nil}
+		}},
+	}
+
+	newType := func() *design.UserTypeDefinition {
+		return &design.UserTypeDefinition{
+			TypeName: "Account",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:       design.Object{"id": &design.AttributeDefinition{Type: design.String}},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
+			},
+		}
+	}
+
+	It("generate the JSON methods of the types that only they apply to", func() {
+		for _, gen := range generators {
+			ut := newType()
+			gen.apply(ut)
+			code, err := gen.generate(ut)
+			Ω(err).ShouldNot(HaveOccurred(), gen.key)
+			Ω(code).ShouldNot(BeEmpty(), gen.key)
+		}
+	})
+
+	It("reject the types that another generator applies to", func() {
+		for _, gen := range generators {
+			for _, other := range generators {
+				if other.key == gen.key {
+					continue
+				}
+				ut := newType()
+				gen.apply(ut)
+				other.apply(ut)
+				_, err := gen.generate(ut)
+				Ω(err).Should(HaveOccurred(), gen.key+" with "+other.key)
+				Ω(err.Error()).Should(ContainSubstring("cannot be combined with "+other.key), gen.key)
+			}
+		}
+	})
+})
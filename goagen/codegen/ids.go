@@ -0,0 +1,91 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+)
+
+// IDTypeKey is the name of the metadata used to render the struct field generated for a string or
+// integer attribute with a distinct named type, e.g. "UserID", so that the identifiers of different
+// entities cannot be mixed up. The value of the metadata is the name of the type. The type is
+// declared with GoIDTypes and is encoded in JSON as the underlying scalar.
+const IDTypeKey = "struct:field:id"
+
+// GoIDTypes produces the Go code that declares the identifier types used by the struct fields
+// generated for the attributes of the given data structures that define the IDTypeKey metadata,
+// e.g. "type UserID string". The attributes of the user types referred to by the data structures
+// are not considered. The types are sorted by name. GoIDTypes returns an empty string if no
+// attribute defines the metadata and an error if the same type name is used with different
+// underlying types.
+func GoIDTypes(types []design.DataStructure) (string, error) {
+	ids := make(map[string]string)
+	for _, ds := range types {
+		if err := collectIDTypes(ds.Definition(), ids); err != nil {
+			return "", err
+		}
+	}
+	if len(ids) == 0 {
+		return "", nil
+	}
+	names := make([]string, len(ids))
+	i := 0
+	for n := range ids {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	for i, n := range names {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(fmt.Sprintf("// %s is a distinct identifier type.\ntype %s %s\n", n, n, ids[n]))
+	}
+	return buf.String(), nil
+}
+
+// collectIDTypes records the underlying types of the identifier types used by the Go type
+// generated for att in ids.
+func collectIDTypes(att *design.AttributeDefinition, ids map[string]string) error {
+	switch actual := att.Type.(type) {
+	case *design.Array:
+		return collectIDTypes(actual.ElemType, ids)
+	case *design.Hash:
+		if err := collectIDTypes(actual.KeyType, ids); err != nil {
+			return err
+		}
+		return collectIDTypes(actual.ElemType, ids)
+	case design.Object:
+		for _, catt := range actual {
+			if id := idType(catt); id != "" {
+				native := GoNativeType(catt.Type)
+				if prev, ok := ids[id]; ok && prev != native {
+					return fmt.Errorf("identifier type %s is used with both %s and %s values", id, prev, native)
+				}
+				ids[id] = native
+			}
+			if err := collectIDTypes(catt, ids); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// idType returns the name of the identifier type of the field generated for the given attribute
+// if it is a string or an integer and defines the IDTypeKey metadata, the empty string otherwise.
+func idType(att *design.AttributeDefinition) string {
+	vals, ok := att.Metadata[IDTypeKey]
+	if !ok || len(vals) == 0 || vals[0] == "" {
+		return ""
+	}
+	switch att.Type.Kind() {
+	case design.StringKind, design.IntegerKind:
+		return Goify(vals[0], true)
+	default:
+		return ""
+	}
+}
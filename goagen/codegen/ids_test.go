@@ -0,0 +1,103 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoIDTypes", func() {
+	var post *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		post = &design.UserTypeDefinition{
+			TypeName: "Post",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"id": &design.AttributeDefinition{
+						Type:     design.Integer,
+						Metadata: dslengine.MetadataDefinition{"struct:field:id": {"PostID"}},
+					},
+					"authorID": &design.AttributeDefinition{
+						Type:     design.String,
+						Metadata: dslengine.MetadataDefinition{"struct:field:id": {"UserID"}},
+					},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"id", "authorID"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoIDTypes([]design.DataStructure{post})
+	})
+
+	It("declares one distinct type per identifier", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(idTypesCode))
+	})
+
+	It("renders the fields with the identifier types", func() {
+		Ω(codegen.GoTypeDef(post, 0, false, false)).Should(Equal("struct {\n\tAuthorID UserID\n\tID PostID\n}"))
+	})
+
+	It("produces types that validate and marshal as the underlying scalar", func() {
+		pattern, min := "^[a-z]+$", 1.0
+		post.Type.ToObject()["authorID"].Validation = &dslengine.ValidationDefinition{Pattern: pattern}
+		post.Type.ToObject()["id"].Validation = &dslengine.ValidationDefinition{Minimum: &min}
+		src, err := codegen.GenerateFile("post", []design.DataStructure{post})
+		Ω(err).ShouldNot(HaveOccurred())
+		validate := "package post\n\nimport \"github.com/goadesign/goa\"\n\n" +
+			"func (ut *Post) Validate() (err error) {\n" +
+			codegen.NewValidator().Code(post.AttributeDefinition, false, false, false, "ut", "response", 1, false) +
+			"\n\treturn\n}\n"
+		out, err := goTest(map[string]string{"post.go": string(src), "validate.go": validate, "post_test.go": idTypesTest})
+		Ω(err).ShouldNot(HaveOccurred(), out)
+	})
+
+	Context("with an identifier type used with different underlying types", func() {
+		BeforeEach(func() {
+			post.Type.ToObject()["id"].Metadata["struct:field:id"] = []string{"UserID"}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const idTypesCode = `// PostID is a distinct identifier type.
+type PostID int
+
+// UserID is a distinct identifier type.
+type UserID string
+`
+
+const idTypesTest = `package post
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIDTypes(t *testing.T) {
+	b, err := json.Marshal(&Post{AuthorID: "joe", ID: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != ` + "`" + `{"authorID":"joe","id":42}` + "`" + ` {
+		t.Errorf("unexpected encoding %s", b)
+	}
+	var p Post
+	if err := json.Unmarshal(b, &p); err != nil || p.AuthorID != UserID("joe") || p.ID != PostID(42) {
+		t.Errorf("unexpected decoding %#v, %v", p, err)
+	}
+	if err := (&Post{AuthorID: "Joe", ID: 0}).Validate(); err == nil {
+		t.Error("expected validation errors")
+	}
+}
+`
@@ -0,0 +1,85 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// InternKey is the name of the metadata used to flag string attributes whose struct fields are
+// interned with goa.Intern when decoded so that equal values share their backing memory. The
+// metadata also applies to arrays of strings.
+const InternKey = "struct:field:intern"
+
+var internT *template.Template
+
+func init() {
+	var err error
+	if internT, err = template.New("intern").Parse(internTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoInternUnmarshaler produces the Go code of the UnmarshalJSON method of the struct generated for
+// the given user type that interns the fields of the string and array of strings attributes
+// flagged with InternKey after decoding. The function returns an error if ut is not an object, if
+// a flagged attribute is not a string or an array of strings, if no attribute is flagged or if
+// another generator produces the JSON methods of ut, see jsonMethodsConflict.
+func GoInternUnmarshaler(ut *design.UserTypeDefinition) (string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	var fields []map[string]interface{}
+	err := obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		if _, ok := att.Metadata[InternKey]; !ok {
+			return nil
+		}
+		field := map[string]interface{}{"Field": "a." + GoifyAtt(att, n, true)}
+		if arr := att.Type.ToArray(); arr != nil && arr.ElemType.Type.Kind() == design.StringKind {
+			field["Array"] = true
+		} else if att.Type.Kind() == design.StringKind {
+			fa := newFieldAccess(ut.AttributeDefinition, n, "a", false)
+			field["Guard"] = fa.guard
+			field["Value"] = fa.value
+		} else {
+			return fmt.Errorf("%s.%s: cannot intern values of type %s", ut.TypeName, n, att.Type.Name())
+		}
+		fields = append(fields, field)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(fields) == 0 {
+		return "", fmt.Errorf("type %s does not define attributes with the %s metadata", ut.TypeName, InternKey)
+	}
+	if err := jsonMethodsConflict(ut, InternKey); err != nil {
+		return "", err
+	}
+	data := map[string]interface{}{
+		"Name":   Goify(ut.TypeName, true),
+		"Fields": fields,
+	}
+	return RunTemplate(internT, data), nil
+}
+
+const internTmpl = `// UnmarshalJSON decodes the {{ .Name }} and interns its string fields with goa.Intern.
+func (ut *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	type alias {{ .Name }}
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+{{ range .Fields }}{{ if .Array }}	for i, v := range {{ .Field }} {
+		{{ .Field }}[i] = goa.Intern(v)
+	}
+{{ else if .Guard }}	if {{ .Guard }} {
+		{{ .Value }} = goa.Intern({{ .Value }})
+	}
+{{ else }}	{{ .Field }} = goa.Intern({{ .Field }})
+{{ end }}{{ end }}	*ut = {{ .Name }}(a)
+	return nil
+}
+`
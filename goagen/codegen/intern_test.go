@@ -0,0 +1,139 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoInternUnmarshaler", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		intern := dslengine.MetadataDefinition{codegen.InternKey: nil}
+		ut = &design.UserTypeDefinition{
+			TypeName: "event",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"tenant":  &design.AttributeDefinition{Type: design.String, Metadata: intern},
+					"region":  &design.AttributeDefinition{Type: design.String, Metadata: intern},
+					"labels":  &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}, Metadata: intern},
+					"message": &design.AttributeDefinition{Type: design.String},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"tenant"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoInternUnmarshaler(ut)
+	})
+
+	It("interns the flagged fields after decoding", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(internCode))
+	})
+
+	It("generates code that compiles and shares the memory of the decoded values", func() {
+		ut.TypeName = "Event"
+		code, err := codegen.GoInternUnmarshaler(ut)
+		Ω(err).ShouldNot(HaveOccurred())
+		src := "package event\n\nimport (\n\t\"encoding/json\"\n\n\t\"github.com/goadesign/goa\"\n)\n\n" +
+			"type Event " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" + code
+		out, err := goTest(map[string]string{"event.go": src, "event_test.go": internUsageTest})
+		Ω(err).ShouldNot(HaveOccurred(), out)
+	})
+
+	Context("with a flagged attribute that is not a string", func() {
+		BeforeEach(func() {
+			ut.Type.ToObject()["count"] = &design.AttributeDefinition{
+				Type:     design.Integer,
+				Metadata: dslengine.MetadataDefinition{codegen.InternKey: nil},
+			}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with no flagged attribute", func() {
+		BeforeEach(func() {
+			ut.Type = design.Object{"message": &design.AttributeDefinition{Type: design.String}}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const internCode = `// UnmarshalJSON decodes the Event and interns its string fields with goa.Intern.
+func (ut *Event) UnmarshalJSON(data []byte) error {
+	type alias Event
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	for i, v := range a.Labels {
+		a.Labels[i] = goa.Intern(v)
+	}
+	if a.Region != nil {
+		*a.Region = goa.Intern(*a.Region)
+	}
+	a.Tenant = goa.Intern(a.Tenant)
+	*ut = Event(a)
+	return nil
+}
+`
+
+const internUsageTest = `package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"unsafe"
+
+	"github.com/goadesign/goa"
+)
+
+func decode(t *testing.T, doc string) *Event {
+	var e Event
+	if err := json.Unmarshal([]byte(doc), &e); err != nil {
+		t.Fatal(err)
+	}
+	return &e
+}
+
+func TestInternedFields(t *testing.T) {
+	goa.ResetInternTable()
+	doc := ` + "`" + `{"tenant":"acme","region":"eu","labels":["a"],"message":"hello"}` + "`" + `
+	e1, e2 := decode(t, doc), decode(t, doc)
+	if e1.Tenant != "acme" || *e1.Region != "eu" || e1.Labels[0] != "a" {
+		t.Fatalf("unexpected decoded value %+v", e1)
+	}
+	if unsafe.StringData(e1.Tenant) != unsafe.StringData(e2.Tenant) {
+		t.Error("tenant not interned")
+	}
+	if unsafe.StringData(*e1.Region) != unsafe.StringData(*e2.Region) {
+		t.Error("region not interned")
+	}
+	if unsafe.StringData(e1.Labels[0]) != unsafe.StringData(e2.Labels[0]) {
+		t.Error("labels not interned")
+	}
+	if unsafe.StringData(*e1.Message) == unsafe.StringData(*e2.Message) {
+		t.Error("message interned")
+	}
+	for i := 0; i < 2*goa.DefaultInternCapacity; i++ {
+		decode(t, fmt.Sprintf(` + "`" + `{"tenant":"tenant-%d"}` + "`" + `, i))
+	}
+	if e3 := decode(t, doc); unsafe.StringData(e3.Tenant) == unsafe.StringData(e1.Tenant) {
+		t.Error("intern table not bounded")
+	}
+}
+`
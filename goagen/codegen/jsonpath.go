@@ -0,0 +1,89 @@
+package codegen
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// JSONPath returns the dotted path to the target attribute from the root data structure using the
+// JSON names of the attributes, e.g. "orders[].items[].sku". Array elements are denoted with "[]".
+// The JSON name of an attribute is the name set with the "struct:tag:json" metadata if any, the
+// attribute name otherwise. Attributes excluded from the JSON representation with the "-" tag and
+// channel attributes are not traversed. The returned boolean is false if the target cannot be reached from root.
+func JSONPath(root design.DataStructure, target *design.AttributeDefinition) (string, bool) {
+	return jsonPath(root.Definition(), target, "", make(map[string]bool))
+}
+
+// JSONTagMap returns the JSON names of the attributes of o indexed by the names of the
+// corresponding struct fields. The field names take the "struct:field:name" metadata into account
+// and the JSON names the "struct:tag:json" metadata. Attributes excluded from the JSON
+// representation map to "-", so do the atomic fields whose struct tags are "-" since their values
+// are encoded by the methods produced by GoAtomicAccessors and the channel fields which are never
+// encoded.
+func JSONTagMap(o design.Object) map[string]string {
+	tags := make(map[string]string, len(o))
+	for n, att := range o {
+		name := jsonName(att, n)
+		_, isChan := att.Metadata[ChannelKey]
+		if isChan || atomicType(att) != "" {
+			name = "-"
+		}
+		tags[GoifyAtt(att, n, true)] = name
+	}
+	return tags
+}
+
+// jsonPath returns the path to target from att, prefix is the path to att.
+func jsonPath(att, target *design.AttributeDefinition, prefix string, seen map[string]bool) (string, bool) {
+	if att == target {
+		return prefix, true
+	}
+	switch actual := att.Type.(type) {
+	case *design.UserTypeDefinition:
+		if seen[actual.TypeName] {
+			return "", false
+		}
+		seen[actual.TypeName] = true
+		return jsonPath(actual.AttributeDefinition, target, prefix, seen)
+	case *design.MediaTypeDefinition:
+		if seen[actual.TypeName] {
+			return "", false
+		}
+		seen[actual.TypeName] = true
+		return jsonPath(actual.AttributeDefinition, target, prefix, seen)
+	case *design.Array:
+		return jsonPath(actual.ElemType, target, prefix+"[]", seen)
+	case design.Object:
+		var path string
+		found := false
+		done := errors.New("done")
+		actual.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+			name := jsonName(catt, n)
+			if _, isChan := catt.Metadata[ChannelKey]; isChan || name == "-" {
+				return nil
+			}
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+			path, found = jsonPath(catt, target, name, seen)
+			if found {
+				return done
+			}
+			return nil
+		})
+		return path, found
+	}
+	return "", false
+}
+
+// jsonName returns the name of the attribute with the given name in JSON representations.
+func jsonName(att *design.AttributeDefinition, name string) string {
+	if tag, ok := att.Metadata["struct:tag:json"]; ok && len(tag) > 0 {
+		if n := strings.Split(strings.Join(tag, ","), ",")[0]; n != "" {
+			return n
+		}
+	}
+	return name
+}
@@ -0,0 +1,113 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSONPath", func() {
+	var root *design.UserTypeDefinition
+	var target *design.AttributeDefinition
+	var path string
+	var found bool
+
+	BeforeEach(func() {
+		target = &design.AttributeDefinition{Type: design.String}
+		item := &design.UserTypeDefinition{
+			TypeName: "Item",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"sku":   target,
+					"price": &design.AttributeDefinition{Type: design.Number},
+				},
+			},
+		}
+		root = &design.UserTypeDefinition{
+			TypeName: "Customer",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name": &design.AttributeDefinition{Type: design.String},
+					"orders": &design.AttributeDefinition{
+						Type: &design.Array{ElemType: &design.AttributeDefinition{
+							Type: design.Object{
+								"lineItems": &design.AttributeDefinition{
+									Type:     &design.Array{ElemType: &design.AttributeDefinition{Type: item}},
+									Metadata: dslengine.MetadataDefinition{"struct:tag:json": {"line_items", "omitempty"}},
+								},
+							},
+						}},
+					},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		path, found = codegen.JSONPath(root, target)
+	})
+
+	It("returns the path to a deeply nested attribute", func() {
+		Ω(found).Should(BeTrue())
+		Ω(path).Should(Equal("orders[].line_items[].sku"))
+	})
+
+	Context("with an attribute that is not part of the data structure", func() {
+		BeforeEach(func() {
+			target = &design.AttributeDefinition{Type: design.String}
+		})
+
+		It("returns false", func() {
+			Ω(found).Should(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("JSONTagMap", func() {
+	It("maps the struct field names to the JSON names", func() {
+		obj := design.Object{
+			"user_id": &design.AttributeDefinition{Type: design.String},
+			"name": &design.AttributeDefinition{
+				Type:     design.String,
+				Metadata: dslengine.MetadataDefinition{"struct:field:name": {"FullName"}},
+			},
+			"email": &design.AttributeDefinition{
+				Type:     design.String,
+				Metadata: dslengine.MetadataDefinition{"struct:tag:json": {"mail", "omitempty"}},
+			},
+			"secret": &design.AttributeDefinition{
+				Type:     design.String,
+				Metadata: dslengine.MetadataDefinition{"struct:tag:json": {"-"}},
+			},
+		}
+		Ω(codegen.JSONTagMap(obj)).Should(Equal(map[string]string{
+			"UserID":   "user_id",
+			"FullName": "name",
+			"Email":    "mail",
+			"Secret":   "-",
+		}))
+	})
+
+	It("maps the atomic fields to the tag of their struct fields", func() {
+		obj := design.Object{
+			"hits": &design.AttributeDefinition{
+				Type:     design.Integer,
+				Metadata: dslengine.MetadataDefinition{"struct:field:atomic": nil},
+			},
+		}
+		Ω(codegen.JSONTagMap(obj)).Should(Equal(map[string]string{"Hits": "-"}))
+	})
+
+	It("maps the channel fields to the tag of their struct fields", func() {
+		obj := design.Object{
+			"events": &design.AttributeDefinition{
+				Type:     design.String,
+				Metadata: dslengine.MetadataDefinition{codegen.ChannelKey: nil, "struct:tag:json": {"events"}},
+			},
+		}
+		Ω(codegen.GoTypeDef(&design.AttributeDefinition{Type: obj}, 0, true, false)).Should(ContainSubstring(`json:"-"`))
+		Ω(codegen.JSONTagMap(obj)).Should(Equal(map[string]string{"Events": "-"}))
+	})
+})
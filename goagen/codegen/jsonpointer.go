@@ -0,0 +1,38 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var jsonPointerSetterT *template.Template
+
+func init() {
+	var err error
+	if jsonPointerSetterT, err = template.New("jsonPointerSetter").Parse(jsonPointerSetterTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoJSONPointerSetter produces the Go code of the JSONPointerSet method of the struct generated
+// for the given user type. The method sets the field addressed by a RFC 6901 JSON pointer, it
+// supports nested objects, array indices and hash keys and checks that the value is compatible
+// with the addressed field. The pointers of derived types (see GoDerivedTypes) also address the
+// fields of the embedded base struct, the attributes inherited with Reference are copied into ut
+// when the design is evaluated and are addressed like the other fields.
+// The function returns an error if ut is not an object.
+func GoJSONPointerSetter(ut *design.UserTypeDefinition) (string, error) {
+	if !ut.IsObject() {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	data := map[string]interface{}{"TypeName": Goify(ut.TypeName, true)}
+	return RunTemplate(jsonPointerSetterT, data), nil
+}
+
+const jsonPointerSetterTmpl = `// JSONPointerSet sets the field of the {{ .TypeName }} addressed by the JSON pointer ptr to value.
+func (ut *{{ .TypeName }}) JSONPointerSet(ptr string, value interface{}) error {
+	return goa.SetJSONPointer(ut, ptr, value)
+}
+`
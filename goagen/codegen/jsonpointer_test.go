@@ -0,0 +1,97 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoJSONPointerSetter", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "order",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{"note": &design.AttributeDefinition{Type: design.String}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoJSONPointerSetter(ut)
+	})
+
+	It("generates the JSONPointerSet method", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(jsonPointerSetterCode))
+	})
+
+	It("generates code that compiles and sets the fields of the embedded base struct", func() {
+		base := &design.UserTypeDefinition{
+			TypeName: "Animal",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:     design.Object{"kind": &design.AttributeDefinition{Type: design.String}},
+				Metadata: dslengine.MetadataDefinition{codegen.DiscriminatorKey: {"kind"}},
+			},
+		}
+		dog := &design.UserTypeDefinition{
+			TypeName: "Dog",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:     design.Object{"name": &design.AttributeDefinition{Type: design.String}},
+				Metadata: dslengine.MetadataDefinition{codegen.DiscriminatorKey: {"dog"}},
+			},
+		}
+		types, err := codegen.GoDerivedTypes(base, dog)
+		Ω(err).ShouldNot(HaveOccurred())
+		setter, err := codegen.GoJSONPointerSetter(dog)
+		Ω(err).ShouldNot(HaveOccurred())
+		src := "package animal\n\nimport \"github.com/goadesign/goa\"\n\n" + types + "\n" + setter
+		out, err := goTest(map[string]string{"animal.go": src, "animal_test.go": jsonPointerUsageTest})
+		Ω(err).ShouldNot(HaveOccurred(), out)
+	})
+
+	Context("with a non object type", func() {
+		BeforeEach(func() {
+			ut.Type = design.String
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const jsonPointerSetterCode = `// JSONPointerSet sets the field of the Order addressed by the JSON pointer ptr to value.
+func (ut *Order) JSONPointerSet(ptr string, value interface{}) error {
+	return goa.SetJSONPointer(ut, ptr, value)
+}
+`
+
+const jsonPointerUsageTest = `package animal
+
+import "testing"
+
+func TestJSONPointerSet(t *testing.T) {
+	d := NewDog()
+	if err := d.JSONPointerSet("/name", "rex"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.JSONPointerSet("/kind", "puppy"); err != nil {
+		t.Fatal(err)
+	}
+	if d.Name == nil || *d.Name != "rex" {
+		t.Errorf("unexpected name %v", d.Name)
+	}
+	if d.Kind == nil || *d.Kind != "puppy" {
+		t.Errorf("unexpected base kind %v", d.Kind)
+	}
+	if err := d.JSONPointerSet("/unknown", "x"); err == nil {
+		t.Error("unknown field not reported")
+	}
+}
+`
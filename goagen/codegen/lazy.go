@@ -0,0 +1,146 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// LazyKey is the name of the metadata used to flag attributes whose values are expensive to
+// compute and should only be computed on demand. The struct field generated for a flagged
+// attribute is unexported and holds the cached value, an exported "<Field>Provider" function field
+// computes the value. GoLazyAccessors produces the methods that compute and cache the values.
+const LazyKey = "struct:field:lazy"
+
+var lazyT *template.Template
+
+func init() {
+	var err error
+	if lazyT, err = template.New("lazy").Parse(lazyTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoLazyAccessors produces the Go code of the methods of the struct generated for the given user
+// type that return the values of the attributes flagged with LazyKey, e.g. "Bar" for a "bar"
+// attribute. The methods compute the values with the provider functions set on the struct the
+// first time they are called and cache the results, errors are not cached. The function also
+// produces the MarshalJSON method that computes the lazy values and encodes them together with the
+// other fields and the UnmarshalJSON method that caches the decoded lazy values. MarshalJSON has a
+// pointer receiver so that the computed values are cached, the lazy fields are only encoded when
+// the struct is marshaled through a pointer. The generated methods are not safe for concurrent
+// use.
+// The function returns an error if ut is not an object, if no attribute is flagged or if
+// another generator produces the JSON methods of ut, see jsonMethodsConflict.
+func GoLazyAccessors(ut *design.UserTypeDefinition) (string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	if err := jsonMethodsConflict(ut, LazyKey); err != nil {
+		return "", err
+	}
+	var fields []map[string]interface{}
+	obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		if _, ok := att.Metadata[LazyKey]; !ok {
+			return nil
+		}
+		tag := jsonName(att, n)
+		optional := !ut.IsRequired(n)
+		if optional {
+			tag += ",omitempty"
+		}
+		fields = append(fields, map[string]interface{}{
+			"Field":    GoifyAtt(att, n, true),
+			"Cache":    GoifyAtt(att, n, false),
+			"Type":     lazyType(ut.AttributeDefinition, n),
+			"Tag":      tag,
+			"Optional": optional,
+		})
+		return nil
+	})
+	if len(fields) == 0 {
+		return "", fmt.Errorf("type %s does not define attributes with the %s metadata", ut.TypeName, LazyKey)
+	}
+	data := map[string]interface{}{
+		"Name":   Goify(ut.TypeName, true),
+		"Fields": fields,
+	}
+	return RunTemplate(lazyT, data), nil
+}
+
+// isLazy returns true if the field generated for the child attribute of parent with the given name
+// is a lazy field. Only the fields of public structs that are not channels may be lazy.
+func isLazy(parent *design.AttributeDefinition, name string, private bool) bool {
+	att := parent.Type.ToObject()[name]
+	_, lazy := att.Metadata[LazyKey]
+	_, isChan := att.Metadata[ChannelKey]
+	return lazy && !private && !isChan
+}
+
+// lazyType returns the type of the value of the lazy child attribute of parent with the given
+// name. It follows the pointer rules of the other fields.
+func lazyType(parent *design.AttributeDefinition, name string) string {
+	return fieldTypeDef(parent, name, 0, true, false, false)
+}
+
+const lazyTmpl = `{{ $name := .Name }}{{ range .Fields }}// {{ .Field }} returns the {{ .Field }} field of {{ $name }}. The value is computed with {{ .Field }}Provider
+// the first time {{ .Field }} is called and cached.
+func (ut *{{ $name }}) {{ .Field }}() ({{ .Type }}, error) {
+	if !ut.{{ .Cache }}Computed {
+		if ut.{{ .Field }}Provider == nil {
+			return ut.{{ .Cache }}, fmt.Errorf("{{ $name }}.{{ .Field }}: no provider")
+		}
+		v, err := ut.{{ .Field }}Provider()
+		if err != nil {
+			return v, err
+		}
+		ut.{{ .Cache }}, ut.{{ .Cache }}Computed = v, true
+	}
+	return ut.{{ .Cache }}, nil
+}
+
+{{ end }}// MarshalJSON computes the lazy fields of {{ .Name }} and encodes them with the other fields. The
+// optional lazy fields that are neither computed nor have a provider are omitted. MarshalJSON has a
+// pointer receiver so that the computed values are cached: {{ .Name }} values must be marshaled
+// through a pointer, marshaling a {{ .Name }} value omits the lazy fields.
+func (ut *{{ .Name }}) MarshalJSON() ([]byte, error) {
+	type alias {{ .Name }}
+{{ range .Fields }}{{ if .Optional }}	var {{ .Cache }} *{{ .Type }}
+	if ut.{{ .Cache }}Computed || ut.{{ .Field }}Provider != nil {
+		v, err := ut.{{ .Field }}()
+		if err != nil {
+			return nil, err
+		}
+		{{ .Cache }} = &v
+	}
+{{ else }}	{{ .Cache }}, err := ut.{{ .Field }}()
+	if err != nil {
+		return nil, err
+	}
+{{ end }}{{ end }}	return json.Marshal(&struct {
+		*alias
+{{ range .Fields }}		{{ .Field }} {{ if .Optional }}*{{ end }}{{ .Type }} ` + "`" + `json:"{{ .Tag }}"` + "`" + `
+{{ end }}	}{
+		alias: (*alias)(ut),
+{{ range .Fields }}		{{ .Field }}: {{ .Cache }},
+{{ end }}	})
+}
+
+// UnmarshalJSON decodes the {{ .Name }} and caches the decoded values of its lazy fields.
+func (ut *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	type alias {{ .Name }}
+	aux := &struct {
+		*alias
+{{ range .Fields }}		{{ .Field }} *{{ .Type }} ` + "`" + `json:"{{ .Tag }}"` + "`" + `
+{{ end }}	}{alias: (*alias)(ut)}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+{{ range .Fields }}	if aux.{{ .Field }} != nil {
+		ut.{{ .Cache }}, ut.{{ .Cache }}Computed = *aux.{{ .Field }}, true
+	}
+{{ end }}	return nil
+}
+`
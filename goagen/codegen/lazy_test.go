@@ -0,0 +1,244 @@
+package codegen_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoLazyAccessors", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "Report",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"id": &design.AttributeDefinition{Type: design.String},
+					"total": &design.AttributeDefinition{
+						Type:     design.Integer,
+						Metadata: dslengine.MetadataDefinition{"struct:field:lazy": nil},
+					},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"id", "total"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoLazyAccessors(ut)
+	})
+
+	It("renders the flagged field as a cached field and a provider", func() {
+		Ω(codegen.GoTypeDef(ut, 0, true, false)).Should(Equal(lazyStructCode))
+	})
+
+	It("generates the accessor and the JSON methods", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(lazyCode))
+	})
+
+	It("calls the provider once and caches the value", func() {
+		dir, err := ioutil.TempDir("", "lazy")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		src, err := codegen.GenerateFile("report", []design.DataStructure{ut})
+		Ω(err).ShouldNot(HaveOccurred())
+		methods := "package report\n\nimport (\n\t\"encoding/json\"\n\t\"fmt\"\n)\n\n" + code
+		Ω(ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module report\n"), 0644)).Should(Succeed())
+		Ω(ioutil.WriteFile(filepath.Join(dir, "report.go"), src, 0644)).Should(Succeed())
+		Ω(ioutil.WriteFile(filepath.Join(dir, "methods.go"), []byte(methods), 0644)).Should(Succeed())
+		Ω(ioutil.WriteFile(filepath.Join(dir, "report_test.go"), []byte(lazyUsageTest), 0644)).Should(Succeed())
+
+		cmd := exec.Command(filepath.Join(runtime.GOROOT(), "bin", "go"), "test", ".")
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		Ω(err).ShouldNot(HaveOccurred(), string(out))
+	})
+
+	Context("with validations and an optional lazy attribute", func() {
+		BeforeEach(func() {
+			min, maxLength := 1.0, 3
+			obj := ut.Type.ToObject()
+			obj["total"].Validation = &dslengine.ValidationDefinition{Minimum: &min}
+			obj["notes"] = &design.AttributeDefinition{
+				Type:       design.String,
+				Metadata:   dslengine.MetadataDefinition{"struct:field:lazy": nil},
+				Validation: &dslengine.ValidationDefinition{MaxLength: &maxLength},
+			}
+		})
+
+		It("generates code that compiles and validates, publicizes and marshals the lazy fields", func() {
+			src := "package report\n\nimport (\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"unicode/utf8\"\n\n\t\"github.com/goadesign/goa\"\n)\n\n" +
+				"type Report " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" +
+				"type report " + codegen.GoTypeDef(ut, 0, true, true) + "\n\n" +
+				"func (ut *Report) Validate() (err error) {\n" +
+				codegen.NewValidator().Code(ut.AttributeDefinition, false, false, false, "ut", "response", 1, false) +
+				"\n\treturn\n}\n\n" +
+				"func (ut *report) Publicize() *Report {\n\tvar pub Report\n" +
+				codegen.RecursivePublicizer(ut.AttributeDefinition, "ut", "pub", 1) +
+				"\n\treturn &pub\n}\n\n" + code
+			out, err := goTest(map[string]string{"report.go": src, "report_test.go": lazyValidationTest})
+			Ω(err).ShouldNot(HaveOccurred(), out)
+		})
+	})
+
+	Context("with no flagged attribute", func() {
+		BeforeEach(func() {
+			delete(ut.Type.ToObject()["total"].Metadata, "struct:field:lazy")
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const lazyStructCode = "struct {\n" +
+	"\tID string `form:\"id\" json:\"id\" xml:\"id\"`\n" +
+	"\ttotal int\n" +
+	"\ttotalComputed bool\n" +
+	"\t// TotalProvider computes the value returned by Total.\n" +
+	"\tTotalProvider func() (int, error) `form:\"-\" json:\"-\" xml:\"-\"`\n" +
+	"}"
+
+const lazyCode = `// Total returns the Total field of Report. The value is computed with TotalProvider
+// the first time Total is called and cached.
+func (ut *Report) Total() (int, error) {
+	if !ut.totalComputed {
+		if ut.TotalProvider == nil {
+			return ut.total, fmt.Errorf("Report.Total: no provider")
+		}
+		v, err := ut.TotalProvider()
+		if err != nil {
+			return v, err
+		}
+		ut.total, ut.totalComputed = v, true
+	}
+	return ut.total, nil
+}
+
+// MarshalJSON computes the lazy fields of Report and encodes them with the other fields. The
+// optional lazy fields that are neither computed nor have a provider are omitted. MarshalJSON has a
+// pointer receiver so that the computed values are cached: Report values must be marshaled
+// through a pointer, marshaling a Report value omits the lazy fields.
+func (ut *Report) MarshalJSON() ([]byte, error) {
+	type alias Report
+	total, err := ut.Total()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&struct {
+		*alias
+		Total int ` + "`" + `json:"total"` + "`" + `
+	}{
+		alias: (*alias)(ut),
+		Total: total,
+	})
+}
+
+// UnmarshalJSON decodes the Report and caches the decoded values of its lazy fields.
+func (ut *Report) UnmarshalJSON(data []byte) error {
+	type alias Report
+	aux := &struct {
+		*alias
+		Total *int ` + "`" + `json:"total"` + "`" + `
+	}{alias: (*alias)(ut)}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	if aux.Total != nil {
+		ut.total, ut.totalComputed = *aux.Total, true
+	}
+	return nil
+}
+`
+
+const lazyUsageTest = `package report
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLazyTotal(t *testing.T) {
+	calls := 0
+	r := &Report{ID: "r1", TotalProvider: func() (int, error) {
+		calls++
+		return 42, nil
+	}}
+	for i := 0; i < 2; i++ {
+		b, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != ` + "`" + `{"id":"r1","total":42}` + "`" + ` {
+			t.Errorf("unexpected encoding %s", b)
+		}
+	}
+	if v, err := r.Total(); err != nil || v != 42 {
+		t.Errorf("unexpected value %d, %v", v, err)
+	}
+	if calls != 1 {
+		t.Errorf("provider called %d times, expected 1", calls)
+	}
+	var decoded Report
+	if err := json.Unmarshal([]byte(` + "`" + `{"id":"r2","total":7}` + "`" + `), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := decoded.Total(); err != nil || v != 7 {
+		t.Errorf("unexpected decoded value %d, %v", v, err)
+	}
+}
+`
+
+const lazyValidationTest = `package report
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLazyValidation(t *testing.T) {
+	r := &Report{ID: "r1", TotalProvider: func() (int, error) { return 42, nil }}
+	if err := r.Validate(); err != nil {
+		t.Errorf("unexpected error for a provided value: %s", err)
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != ` + "`" + `{"id":"r1","total":42}` + "`" + ` {
+		t.Errorf("unexpected encoding %s", b)
+	}
+	if err := (&Report{ID: "r2"}).Validate(); err == nil {
+		t.Error("expected an error for a missing required lazy value")
+	}
+	var decoded Report
+	if err := json.Unmarshal([]byte(` + "`" + `{"id":"r3","total":0,"notes":"long"}` + "`" + `), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.Validate(); err == nil {
+		t.Error("expected an error for invalid cached values")
+	}
+	id, total := "p", 5
+	pub := (&report{ID: &id, Total: &total}).Publicize()
+	if v, err := pub.Total(); err != nil || v != 5 {
+		t.Errorf("unexpected publicized value %d, %v", v, err)
+	}
+	if err := pub.Validate(); err != nil {
+		t.Errorf("unexpected error for publicized values: %s", err)
+	}
+}
+`
@@ -0,0 +1,70 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var fieldMetadataT *template.Template
+
+func init() {
+	var err error
+	if fieldMetadataT, err = template.New("fieldMetadata").Parse(fieldMetadataTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoFieldMetadata produces the Go code of the FieldMetadata method of the struct generated for the
+// given user type. The method returns the design metadata of the attribute corresponding to the
+// struct field with the given Go name so that runtime code may react to design annotations.
+// Metadata with multiple values are joined with commas. The metadata of fields that do not define
+// any is nil. The function returns an error if ut is not an object.
+func GoFieldMetadata(ut *design.UserTypeDefinition) (string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	var fields []map[string]interface{}
+	obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		if len(att.Metadata) == 0 {
+			return nil
+		}
+		keys := make([]string, len(att.Metadata))
+		i := 0
+		for k := range att.Metadata {
+			keys[i] = k
+			i++
+		}
+		sort.Strings(keys)
+		entries := make([]string, len(keys))
+		for i, k := range keys {
+			entries[i] = fmt.Sprintf("%q: %q", k, strings.Join(att.Metadata[k], ","))
+		}
+		fields = append(fields, map[string]interface{}{
+			"Field":   GoifyAtt(att, n, true),
+			"Entries": strings.Join(entries, ", "),
+		})
+		return nil
+	})
+	data := map[string]interface{}{
+		"TypeName": Goify(ut.TypeName, true),
+		"VarName":  Goify(ut.TypeName, false) + "FieldMetadata",
+		"Fields":   fields,
+	}
+	return RunTemplate(fieldMetadataT, data), nil
+}
+
+const fieldMetadataTmpl = `// {{ .VarName }} holds the design metadata of the {{ .TypeName }} fields indexed by Go field name.
+var {{ .VarName }} = map[string]map[string]string{
+{{ range .Fields }}	{{ printf "%q" .Field }}: { {{- .Entries -}} },
+{{ end }}}
+
+// FieldMetadata returns the design metadata of the {{ .TypeName }} field with the given Go name.
+func (ut *{{ .TypeName }}) FieldMetadata(field string) map[string]string {
+	return {{ .VarName }}[field]
+}
+`
@@ -0,0 +1,69 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoFieldMetadata", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "Account",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name": &design.AttributeDefinition{Type: design.String},
+					"plan": &design.AttributeDefinition{
+						Type: design.String,
+						Metadata: dslengine.MetadataDefinition{
+							"owner":   {"billing"},
+							"feature": {"beta", "paid"},
+						},
+					},
+					"service": &design.AttributeDefinition{
+						Type:     design.String,
+						Metadata: dslengine.MetadataDefinition{"struct:field:name": {"ServiceName"}},
+					},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoFieldMetadata(ut)
+	})
+
+	It("generates the metadata of the fields that define some", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(fieldMetadataCode))
+	})
+
+	Context("with a type with no metadata", func() {
+		BeforeEach(func() {
+			ut.Type = design.Object{"name": &design.AttributeDefinition{Type: design.String}}
+		})
+
+		It("generates an empty index", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(ContainSubstring("var accountFieldMetadata = map[string]map[string]string{\n}\n"))
+		})
+	})
+})
+
+const fieldMetadataCode = `// accountFieldMetadata holds the design metadata of the Account fields indexed by Go field name.
+var accountFieldMetadata = map[string]map[string]string{
+	"Plan": {"feature": "beta,paid", "owner": "billing"},
+	"ServiceName": {"struct:field:name": "ServiceName"},
+}
+
+// FieldMetadata returns the design metadata of the Account field with the given Go name.
+func (ut *Account) FieldMetadata(field string) map[string]string {
+	return accountFieldMetadata[field]
+}
+`
@@ -0,0 +1,79 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// MethodKeyPrefix is the prefix of the names of the metadata that map the methods of an interface
+// the struct generated for a user type must implement to the attributes they return, e.g.
+// "struct:method:GetID" mapped to "id".
+const MethodKeyPrefix = "struct:method:"
+
+var delegateMethodsT *template.Template
+
+func init() {
+	var err error
+	if delegateMethodsT, err = template.New("delegateMethods").Parse(delegateMethodsTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoDelegateMethods produces the Go code of the methods of the struct generated for the given user
+// type that are mapped to its attributes with the MethodKeyPrefix metadata. Each method returns
+// the value of the field generated for the attribute, e.g. "GetID" mapped to "id" produces:
+//
+//	func (t *User) GetID() string { return t.ID }
+//
+// The methods are sorted by name. GoDelegateMethods returns an empty string if ut defines no such
+// metadata and an error if ut is not an object or if a method is mapped to an unknown attribute or
+// is not a valid Go identifier.
+func GoDelegateMethods(ut *design.UserTypeDefinition) (string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	var names []string
+	for key := range ut.Metadata {
+		if strings.HasPrefix(key, MethodKeyPrefix) {
+			names = append(names, key[len(MethodKeyPrefix):])
+		}
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	sort.Strings(names)
+	methods := make([]map[string]interface{}, len(names))
+	for i, name := range names {
+		if Goify(name, true) != name {
+			return "", fmt.Errorf("type %s: invalid method name %q", ut.TypeName, name)
+		}
+		vals := ut.Metadata[MethodKeyPrefix+name]
+		if len(vals) == 0 {
+			return "", fmt.Errorf("type %s: method %s is not mapped to an attribute", ut.TypeName, name)
+		}
+		att, ok := obj[vals[0]]
+		if !ok {
+			return "", fmt.Errorf("type %s: method %s is mapped to unknown attribute %q", ut.TypeName, name, vals[0])
+		}
+		methods[i] = map[string]interface{}{
+			"Name":  name,
+			"Field": GoifyAtt(att, vals[0], true),
+			"Type":  fieldTypeDef(ut.AttributeDefinition, vals[0], 0, true, false, false),
+		}
+	}
+	data := map[string]interface{}{
+		"TypeName": Goify(ut.TypeName, true),
+		"Methods":  methods,
+	}
+	return RunTemplate(delegateMethodsT, data), nil
+}
+
+const delegateMethodsTmpl = `{{ $typeName := .TypeName }}{{ range $i, $m := .Methods }}{{ if $i }}
+{{ end }}// {{ $m.Name }} returns the {{ $m.Field }} field of {{ $typeName }}.
+func (t *{{ $typeName }}) {{ $m.Name }}() {{ $m.Type }} { return t.{{ $m.Field }} }
+{{ end }}`
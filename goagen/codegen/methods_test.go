@@ -0,0 +1,69 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoDelegateMethods", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "Foo",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"id":      &design.AttributeDefinition{Type: design.String},
+					"version": &design.AttributeDefinition{Type: design.Integer},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
+				Metadata: dslengine.MetadataDefinition{
+					"struct:method:GetID":      {"id"},
+					"struct:method:GetVersion": {"version"},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoDelegateMethods(ut)
+	})
+
+	It("generates one method per mapped attribute", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(delegateMethodsCode))
+	})
+
+	Context("with a method mapped to an unknown attribute", func() {
+		BeforeEach(func() {
+			ut.Metadata["struct:method:GetName"] = []string{"name"}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with no mapped method", func() {
+		BeforeEach(func() {
+			ut.Metadata = nil
+		})
+
+		It("generates nothing", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(BeEmpty())
+		})
+	})
+})
+
+const delegateMethodsCode = `// GetID returns the ID field of Foo.
+func (t *Foo) GetID() string { return t.ID }
+
+// GetVersion returns the Version field of Foo.
+func (t *Foo) GetVersion() *int { return t.Version }
+`
@@ -0,0 +1,119 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// MultipartFileKey is the name of the metadata used to flag the string attributes of multipart
+// request types that hold the path to a file to be uploaded as a file part rather than a value to
+// be written as a form field. The design package defines no file primitive type so the metadata
+// flags the attributes instead.
+const MultipartFileKey = "multipart:file"
+
+var multipartT *template.Template
+
+func init() {
+	var err error
+	if multipartT, err = template.New("multipart").Parse(multipartTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoMultipartEncoder produces the Go code of the WriteMultipart method of the struct generated for
+// the given user type. WriteMultipart writes the fields to a multipart/form-data writer: scalar
+// fields are written as form fields formatted like query string values, array fields produce one
+// form field per element and the string fields flagged with MultipartFileKey are written as file
+// parts whose content is read from the path they hold. Optional fields that are not set are
+// omitted.
+// The function returns an error if ut is not an object, if one of its attributes cannot be
+// represented as a form field or if a flagged attribute is not a string.
+func GoMultipartEncoder(ut *design.UserTypeDefinition) (string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	var fields []map[string]interface{}
+	err := obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		field := "r." + GoifyAtt(att, n, true)
+		data := map[string]interface{}{"Name": n, "Field": field}
+		val := field
+		if att.Type.IsPrimitive() {
+			fa := newFieldAccess(ut.AttributeDefinition, n, "r", false)
+			data["Guard"] = fa.guard
+			val = fa.value
+		}
+		if _, ok := att.Metadata[MultipartFileKey]; ok {
+			if att.Type.Kind() != design.StringKind {
+				return fmt.Errorf("%s.%s: file attributes must be strings holding a path", ut.TypeName, n)
+			}
+			data["File"] = true
+			data["Value"] = val
+		} else if arr := att.Type.ToArray(); arr != nil {
+			code, err := queryValueCode(arr.ElemType.Type, "v")
+			if err != nil {
+				return fmt.Errorf("%s.%s: %s", ut.TypeName, n, err)
+			}
+			data["Array"] = true
+			data["Value"] = code
+		} else if att.Type.Kind() == design.IntegerKind {
+			// The values of the sqlnull and atomic fields are int64 values.
+			data["Value"] = fmt.Sprintf("strconv.FormatInt(int64(%s), 10)", val)
+		} else {
+			code, err := queryValueCode(att.Type, val)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %s", ut.TypeName, n, err)
+			}
+			data["Value"] = code
+		}
+		fields = append(fields, data)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	data := map[string]interface{}{
+		"Name":   Goify(ut.TypeName, true),
+		"Fields": fields,
+	}
+	return RunTemplate(multipartT, data), nil
+}
+
+const multipartTmpl = `// WriteMultipart writes the {{ .Name }} fields to w, scalar fields as form fields and file fields
+// as file parts read from the paths they hold.
+func (r *{{ .Name }}) WriteMultipart(w *multipart.Writer) error {
+{{ range .Fields }}{{ if .File }}{{ if .Guard }}	if {{ .Guard }} {
+{{ else }}	{
+{{ end }}		f, err := os.Open({{ .Value }})
+		if err != nil {
+			return err
+		}
+		part, err := w.CreateFormFile({{ printf "%q" .Name }}, filepath.Base({{ .Value }}))
+		if err != nil {
+			f.Close()
+			return err
+		}
+		_, err = io.Copy(part, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+{{ else if .Array }}	for _, v := range {{ .Field }} {
+		if err := w.WriteField({{ printf "%q" .Name }}, {{ .Value }}); err != nil {
+			return err
+		}
+	}
+{{ else if .Guard }}	if {{ .Guard }} {
+		if err := w.WriteField({{ printf "%q" .Name }}, {{ .Value }}); err != nil {
+			return err
+		}
+	}
+{{ else }}	if err := w.WriteField({{ printf "%q" .Name }}, {{ .Value }}); err != nil {
+		return err
+	}
+{{ end }}{{ end }}	return nil
+}
+`
@@ -0,0 +1,210 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoMultipartEncoder", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			TypeName: "UploadRequest",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"title": &design.AttributeDefinition{Type: design.String},
+					"document": &design.AttributeDefinition{
+						Type:     design.String,
+						Metadata: dslengine.MetadataDefinition{codegen.MultipartFileKey: nil},
+					},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"title", "document"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoMultipartEncoder(ut)
+	})
+
+	It("writes the text field as a form field and the file field as a file part", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(multipartCode))
+	})
+
+	It("generates code that compiles and writes a body that parses back", func() {
+		src := "package upload\n\nimport (\n\t\"io\"\n\t\"mime/multipart\"\n\t\"os\"\n\t\"path/filepath\"\n)\n\n" +
+			"type UploadRequest " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" + code
+		out, err := goTest(map[string]string{"upload.go": src, "upload_test.go": multipartUsageTest})
+		Ω(err).ShouldNot(HaveOccurred(), out)
+	})
+
+	Context("with optional and array fields", func() {
+		BeforeEach(func() {
+			obj := ut.Type.ToObject()
+			obj["count"] = &design.AttributeDefinition{Type: design.Integer}
+			obj["tags"] = &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}}
+		})
+
+		It("omits unset fields and writes one form field per element", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(ContainSubstring("\tif r.Count != nil {\n\t\tif err := w.WriteField(\"count\", strconv.FormatInt(int64(*r.Count), 10)); err != nil {\n"))
+			Ω(code).Should(ContainSubstring("\tfor _, v := range r.Tags {\n\t\tif err := w.WriteField(\"tags\", v); err != nil {\n"))
+		})
+	})
+
+	Context("with optional date time and UUID fields, sqlnull and atomic fields", func() {
+		BeforeEach(func() {
+			obj := ut.Type.ToObject()
+			obj["takenAt"] = &design.AttributeDefinition{Type: design.DateTime}
+			obj["owner"] = &design.AttributeDefinition{Type: design.UUID}
+			obj["views"] = &design.AttributeDefinition{
+				Type:     design.Integer,
+				Metadata: dslengine.MetadataDefinition{codegen.SQLNullKey: nil},
+			}
+			obj["downloads"] = &design.AttributeDefinition{
+				Type:     design.Integer,
+				Metadata: dslengine.MetadataDefinition{codegen.AtomicKey: nil},
+			}
+		})
+
+		It("formats the field values", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(ContainSubstring(`w.WriteField("takenAt", (*r.TakenAt).Format(time.RFC3339))`))
+			Ω(code).Should(ContainSubstring(`w.WriteField("owner", (*r.Owner).String())`))
+			Ω(code).Should(ContainSubstring(`w.WriteField("views", strconv.FormatInt(int64(r.Views.Int64), 10))`))
+			Ω(code).Should(ContainSubstring(`w.WriteField("downloads", strconv.FormatInt(int64(r.Downloads.Load()), 10))`))
+		})
+
+		It("generates code that compiles", func() {
+			src := "package upload\n\nimport (\n\t\"io\"\n\t\"mime/multipart\"\n\t\"os\"\n\t\"path/filepath\"\n\t\"strconv\"\n\t\"sync/atomic\"\n\t\"time\"\n\n" +
+				"\t\"github.com/goadesign/goa\"\n\t\"github.com/goadesign/goa/uuid\"\n)\n\n" +
+				"type UploadRequest " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" + code
+			out, err := goTest(map[string]string{"upload.go": src})
+			Ω(err).ShouldNot(HaveOccurred(), out)
+		})
+	})
+
+	Context("with a file attribute that is not a string", func() {
+		BeforeEach(func() {
+			ut.Type.ToObject()["document"].Type = design.Integer
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const multipartCode = `// WriteMultipart writes the UploadRequest fields to w, scalar fields as form fields and file fields
+// as file parts read from the paths they hold.
+func (r *UploadRequest) WriteMultipart(w *multipart.Writer) error {
+	{
+		f, err := os.Open(r.Document)
+		if err != nil {
+			return err
+		}
+		part, err := w.CreateFormFile("document", filepath.Base(r.Document))
+		if err != nil {
+			f.Close()
+			return err
+		}
+		_, err = io.Copy(part, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if err := w.WriteField("title", r.Title); err != nil {
+		return err
+	}
+	return nil
+}
+`
+
+const multipartUsageTest = `package upload
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteMultipart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.txt")
+	if err := os.WriteFile(path, []byte("file content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.SetBoundary("upload-boundary"); err != nil {
+		t.Fatal(err)
+	}
+	r := &UploadRequest{Document: path, Title: "quarterly report"}
+	if err := r.WriteMultipart(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	raw := body.String()
+	if !strings.HasPrefix(raw, "--upload-boundary\r\n") {
+		t.Errorf("body does not start with the boundary: %q", raw)
+	}
+	if !strings.HasSuffix(raw, "\r\n--upload-boundary--\r\n") {
+		t.Errorf("body does not end with the closing boundary: %q", raw)
+	}
+	if n := strings.Count(raw, "--upload-boundary\r\n"); n != 2 {
+		t.Errorf("got %d parts, expected 2", n)
+	}
+
+	mr := multipart.NewReader(&body, "upload-boundary")
+	file, err := mr.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cd := file.Header.Get("Content-Disposition"); cd != ` + "`" + `form-data; name="document"; filename="report.txt"` + "`" + ` {
+		t.Errorf("unexpected file part disposition %q", cd)
+	}
+	if ct := file.Header.Get("Content-Type"); ct != "application/octet-stream" {
+		t.Errorf("unexpected file part content type %q", ct)
+	}
+	if b, _ := io.ReadAll(file); string(b) != "file content" {
+		t.Errorf("unexpected file part content %q", b)
+	}
+	field, err := mr.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cd := field.Header.Get("Content-Disposition"); cd != ` + "`" + `form-data; name="title"` + "`" + ` {
+		t.Errorf("unexpected form field disposition %q", cd)
+	}
+	if ct := field.Header.Get("Content-Type"); ct != "" {
+		t.Errorf("unexpected form field content type %q", ct)
+	}
+	if b, _ := io.ReadAll(field); string(b) != "quarterly report" {
+		t.Errorf("unexpected form field value %q", b)
+	}
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("expected end of body, got %v", err)
+	}
+}
+
+func TestWriteMultipartMissingFile(t *testing.T) {
+	w := multipart.NewWriter(io.Discard)
+	r := &UploadRequest{Document: filepath.Join(t.TempDir(), "missing.txt"), Title: "t"}
+	if err := r.WriteMultipart(w); err == nil {
+		t.Error("missing file not reported")
+	}
+}
+`
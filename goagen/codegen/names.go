@@ -0,0 +1,94 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/goadesign/goa/design"
+)
+
+// ValidateNames checks that the Go identifiers produced with Goify from the names of the given
+// data structure, its attributes and the user types it refers to are legal. It reports names that
+// produce an empty identifier (e.g. "$$") or an identifier that does not start with a letter (e.g.
+// "1st"), and sibling attributes that produce the same identifier, exported or not (e.g. "a_b" and
+// "a-b", or "type" and "type_" since Goify suffixes Go keywords with "_"). The names are checked
+// with GoifyAtt so that the "struct:field:name" metadata is taken into account. ValidateNames
+// returns nil if all the names are legal and an error listing all the illegal names otherwise.
+func ValidateNames(ds design.DataStructure) error {
+	v := &nameValidator{seen: make(map[string]bool)}
+	name := userTypeName(ds)
+	if name != "" {
+		v.seen[name] = true
+		v.checkIdentifier(name, Goify(name, true))
+	}
+	v.validate(name, ds.Definition())
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid names: %s", strings.Join(v.errs, ", "))
+}
+
+// nameValidator records the names that produce illegal identifiers.
+type nameValidator struct {
+	seen map[string]bool
+	errs []string
+}
+
+// validate checks the names of the attributes of att rooted at path.
+func (v *nameValidator) validate(path string, att *design.AttributeDefinition) {
+	switch actual := att.Type.(type) {
+	case *design.UserTypeDefinition, *design.MediaTypeDefinition:
+		ref := actual.(design.DataStructure)
+		name := userTypeName(ref)
+		if v.seen[name] {
+			return
+		}
+		v.seen[name] = true
+		v.checkIdentifier(name, Goify(name, true))
+		v.validate(name, ref.Definition())
+	case *design.Array:
+		v.validate(path+"[]", actual.ElemType)
+	case *design.Hash:
+		v.validate(path+"[key]", actual.KeyType)
+		v.validate(path+"[]", actual.ElemType)
+	case design.Object:
+		prefix := path
+		if prefix != "" {
+			prefix += "."
+		}
+		public := make(map[string]string)
+		private := make(map[string]string)
+		actual.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+			p := prefix + n
+			id := GoifyAtt(catt, n, true)
+			v.checkIdentifier(p, id)
+			if id != "" && !v.checkCollision(public, prefix, n, id) {
+				v.checkCollision(private, prefix, n, GoifyAtt(catt, n, false))
+			}
+			v.validate(p, catt)
+			return nil
+		})
+	}
+}
+
+// checkIdentifier records an error if id, the identifier produced for the name at path, is empty
+// or does not start with a letter.
+func (v *nameValidator) checkIdentifier(path, id string) {
+	if id == "" {
+		v.errs = append(v.errs, fmt.Sprintf("%q produces an empty identifier", path))
+	} else if r := []rune(id)[0]; !unicode.IsLetter(r) {
+		v.errs = append(v.errs, fmt.Sprintf("%q produces the invalid identifier %q", path, id))
+	}
+}
+
+// checkCollision records an error and returns true if id, the identifier produced for the
+// attribute n, was also produced for a sibling attribute listed in ids.
+func (v *nameValidator) checkCollision(ids map[string]string, prefix, n, id string) bool {
+	if other, ok := ids[id]; ok {
+		v.errs = append(v.errs, fmt.Sprintf("%q and %q both produce the identifier %q", prefix+other, prefix+n, id))
+		return true
+	}
+	ids[id] = n
+	return false
+}
@@ -0,0 +1,68 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateNames", func() {
+	var obj design.Object
+	var err error
+
+	BeforeEach(func() {
+		obj = design.Object{
+			"id":   &design.AttributeDefinition{Type: design.String},
+			"name": &design.AttributeDefinition{Type: design.String},
+		}
+	})
+
+	JustBeforeEach(func() {
+		ut := &design.UserTypeDefinition{
+			TypeName:            "User",
+			AttributeDefinition: &design.AttributeDefinition{Type: obj},
+		}
+		err = codegen.ValidateNames(ut)
+	})
+
+	It("accepts legal names", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("with a name that produces an empty identifier", func() {
+		BeforeEach(func() {
+			obj["$$"] = &design.AttributeDefinition{Type: design.String}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(Equal(`invalid names: "User.$$" produces an empty identifier`))
+		})
+	})
+
+	Context("with names that collide once goified", func() {
+		BeforeEach(func() {
+			obj["first_name"] = &design.AttributeDefinition{Type: design.String}
+			obj["first-name"] = &design.AttributeDefinition{Type: design.String}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(Equal(`invalid names: "User.first-name" and "User.first_name" both produce the identifier "FirstName"`))
+		})
+	})
+
+	Context("with a nested attribute that produces an invalid identifier", func() {
+		BeforeEach(func() {
+			obj["address"] = &design.AttributeDefinition{
+				Type: design.Object{"1st": &design.AttributeDefinition{Type: design.String}},
+			}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(Equal(`invalid names: "User.address.1st" produces the invalid identifier "1st"`))
+		})
+	})
+})
@@ -0,0 +1,104 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// FieldTransformKey is the name of the metadata used to list the transforms applied to the value
+// of a string attribute after it is decoded. The built-in transforms are "trim", "lower" and
+// "upper", any other value is the name of a custom transform registered with goa.RegisterTransform.
+const FieldTransformKey = "struct:field:transform"
+
+// builtinTransforms lists the Go functions that implement the built-in transforms.
+var builtinTransforms = map[string]string{
+	"trim":  "strings.TrimSpace",
+	"lower": "strings.ToLower",
+	"upper": "strings.ToUpper",
+}
+
+var normalizeT *template.Template
+
+func init() {
+	var err error
+	if normalizeT, err = template.New("normalize").Parse(normalizeTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoTransformUnmarshaler produces the Go code of the UnmarshalJSON method of the struct generated
+// for ut that applies the transforms listed in the FieldTransformKey metadata of its attributes
+// after decoding. Transforms are applied in the order they are listed, built-in transforms are
+// inlined while custom transforms are dispatched to goa.ApplyTransform. UnmarshalJSON returns the
+// error returned by goa.ApplyTransform if a custom transform is not registered.
+// The function returns the empty string if no attribute lists transforms and an error if an
+// attribute listing transforms is not a string or if another generator produces the JSON
+// methods of ut, see jsonMethodsConflict.
+func GoTransformUnmarshaler(ut *design.UserTypeDefinition) (string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("user type %s must be an object", ut.TypeName)
+	}
+	var fields []map[string]interface{}
+	err := obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		names, ok := att.Metadata[FieldTransformKey]
+		if !ok {
+			return nil
+		}
+		if att.Type.Kind() != design.StringKind {
+			return fmt.Errorf("%s.%s: transforms only apply to strings", ut.TypeName, n)
+		}
+		fa := newFieldAccess(ut.AttributeDefinition, n, "ut", false)
+		var steps []string
+		code, decl := fa.value, ":="
+		for _, name := range names {
+			if fn, ok := builtinTransforms[name]; ok {
+				code = fmt.Sprintf("%s(%s)", fn, code)
+				continue
+			}
+			steps = append(steps,
+				fmt.Sprintf("v, err %s goa.ApplyTransform(%q, %s)", decl, name, code),
+				"if err != nil {",
+				"\treturn err",
+				"}",
+			)
+			code, decl = "v", "="
+		}
+		fields = append(fields, map[string]interface{}{
+			"Guard": fa.guard,
+			"Block": len(steps) > 0,
+			"Steps": append(steps, fmt.Sprintf("%s = %s", fa.value, code)),
+		})
+		return nil
+	})
+	if err != nil || len(fields) == 0 {
+		return "", err
+	}
+	if err := jsonMethodsConflict(ut, FieldTransformKey); err != nil {
+		return "", err
+	}
+	data := map[string]interface{}{
+		"TypeName": Goify(ut.TypeName, true),
+		"Fields":   fields,
+	}
+	return RunTemplate(normalizeT, data), nil
+}
+
+const normalizeTmpl = `// UnmarshalJSON decodes the {{ .TypeName }} JSON representation and applies the attribute transforms.
+func (ut *{{ .TypeName }}) UnmarshalJSON(data []byte) error {
+	type alias {{ .TypeName }}
+	if err := json.Unmarshal(data, (*alias)(ut)); err != nil {
+		return err
+	}
+{{ range .Fields }}{{ if .Guard }}	if {{ .Guard }} {
+{{ range .Steps }}		{{ . }}
+{{ end }}	}
+{{ else if .Block }}	{
+{{ range .Steps }}		{{ . }}
+{{ end }}	}
+{{ else }}{{ range .Steps }}	{{ . }}
+{{ end }}{{ end }}{{ end }}	return nil
+}
+`
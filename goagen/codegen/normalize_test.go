@@ -0,0 +1,137 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoTransformUnmarshaler", func() {
+	var obj design.Object
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		obj = design.Object{
+			"name": &design.AttributeDefinition{
+				Type:     design.String,
+				Metadata: dslengine.MetadataDefinition{codegen.FieldTransformKey: {"trim"}},
+			},
+			"email": &design.AttributeDefinition{
+				Type:     design.String,
+				Metadata: dslengine.MetadataDefinition{codegen.FieldTransformKey: {"trim", "lower"}},
+			},
+			"slug": &design.AttributeDefinition{
+				Type:     design.String,
+				Metadata: dslengine.MetadataDefinition{codegen.FieldTransformKey: {"trim", "slugify", "lower"}},
+			},
+			"code": &design.AttributeDefinition{
+				Type:     design.String,
+				Metadata: dslengine.MetadataDefinition{codegen.FieldTransformKey: {"slugify"}},
+			},
+			"age": &design.AttributeDefinition{Type: design.Integer},
+		}
+	})
+
+	var ut *design.UserTypeDefinition
+
+	JustBeforeEach(func() {
+		ut = &design.UserTypeDefinition{
+			AttributeDefinition: &design.AttributeDefinition{
+				Type:       obj,
+				Validation: &dslengine.ValidationDefinition{Required: []string{"code", "name"}},
+			},
+			TypeName: "Account",
+		}
+		code, err = codegen.GoTransformUnmarshaler(ut)
+	})
+
+	It("applies the transforms after decoding", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(transformUnmarshalerCode))
+	})
+
+	It("returns an error when decoding with an unregistered transform", func() {
+		src := "package account\n\nimport (\n\t\"encoding/json\"\n\t\"strings\"\n\n\t\"github.com/goadesign/goa\"\n)\n\n" +
+			"type Account " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" + code
+		out, err := goTest(map[string]string{"account.go": src, "account_test.go": transformUsageTest})
+		Ω(err).ShouldNot(HaveOccurred(), out)
+	})
+
+	Context("with no transform", func() {
+		BeforeEach(func() {
+			obj = design.Object{"age": &design.AttributeDefinition{Type: design.Integer}}
+		})
+
+		It("generates nothing", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(BeEmpty())
+		})
+	})
+
+	Context("with a transform on an integer attribute", func() {
+		BeforeEach(func() {
+			obj["age"].Metadata = dslengine.MetadataDefinition{codegen.FieldTransformKey: {"trim"}}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const transformUnmarshalerCode = `// UnmarshalJSON decodes the Account JSON representation and applies the attribute transforms.
+func (ut *Account) UnmarshalJSON(data []byte) error {
+	type alias Account
+	if err := json.Unmarshal(data, (*alias)(ut)); err != nil {
+		return err
+	}
+	{
+		v, err := goa.ApplyTransform("slugify", ut.Code)
+		if err != nil {
+			return err
+		}
+		ut.Code = v
+	}
+	if ut.Email != nil {
+		*ut.Email = strings.ToLower(strings.TrimSpace(*ut.Email))
+	}
+	ut.Name = strings.TrimSpace(ut.Name)
+	if ut.Slug != nil {
+		v, err := goa.ApplyTransform("slugify", strings.TrimSpace(*ut.Slug))
+		if err != nil {
+			return err
+		}
+		*ut.Slug = strings.ToLower(v)
+	}
+	return nil
+}
+`
+
+const transformUsageTest = `package account
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+)
+
+func TestTransforms(t *testing.T) {
+	body := []byte(` + "`" + `{"code":"A B","name":" Joe ","slug":" Foo Bar "}` + "`" + `)
+	var a Account
+	if err := json.Unmarshal(body, &a); err == nil || !strings.Contains(err.Error(), "slugify") {
+		t.Fatalf("expected unregistered transform error, got %v", err)
+	}
+	goa.RegisterTransform("slugify", func(s string) string { return strings.Replace(s, " ", "-", -1) })
+	if err := json.Unmarshal(body, &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Code != "A-B" || a.Name != "Joe" || *a.Slug != "foo-bar" {
+		t.Errorf("unexpected transformed values %#v", a)
+	}
+}
+`
@@ -0,0 +1,50 @@
+package codegen
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// hasOptionalFields returns true if the Go type generated for att renders optional primitive
+// fields with the goa.Optional type. The attributes of the user types referred to by att are not
+// considered as their declarations are generated separately.
+func hasOptionalFields(att *design.AttributeDefinition) bool {
+	switch actual := att.Type.(type) {
+	case *design.Array:
+		return hasOptionalFields(actual.ElemType)
+	case *design.Hash:
+		return hasOptionalFields(actual.KeyType) || hasOptionalFields(actual.ElemType)
+	case design.Object:
+		for n, catt := range actual {
+			_, isChan := catt.Metadata[ChannelKey]
+			_, hasDepth := pointerDepth(catt)
+			if !isChan && !hasDepth && atomicType(catt) == "" && sqlNullType(att, n) == "" && att.IsPrimitivePointer(n) {
+				return true
+			}
+			if hasOptionalFields(catt) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// goVersionAtLeast returns true if the Go version v, e.g. "1.18" or "go1.18.2", is greater or
+// equal to major.minor. It returns false if v is empty or invalid.
+func goVersionAtLeast(v string, major, minor int) bool {
+	parts := strings.Split(strings.TrimPrefix(v, "go"), ".")
+	if len(parts) < 2 {
+		return false
+	}
+	maj, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	min, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false
+	}
+	return maj > major || maj == major && min >= minor
+}
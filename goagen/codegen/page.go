@@ -0,0 +1,71 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// PaginatedKey is the name of the metadata used to flag collection media types whose endpoints
+// are cursor paginated.
+const PaginatedKey = "collection:paginated"
+
+var pageT *template.Template
+
+func init() {
+	var err error
+	if pageT, err = template.New("page").Parse(pageTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoPageType produces the Go code of the page type that wraps the given collection media type in
+// cursor paginated responses, e.g. "BottlePage" for "BottleCollection", together with its
+// constructor. The page type holds the items of the page, the cursor of the next page and a flag
+// that indicates whether there are more items. The constructor expects the items to have been
+// fetched with a limit of page size + 1 and computes the flag from the number of items.
+// GoPageType returns an empty string if the media type is not flagged with the PaginatedKey
+// metadata and an error if it is not a collection.
+func GoPageType(mt *design.MediaTypeDefinition) (string, error) {
+	if _, ok := mt.Metadata[PaginatedKey]; !ok {
+		return "", nil
+	}
+	if !mt.IsArray() {
+		return "", fmt.Errorf("paginated media type %s must be a collection", mt.TypeName)
+	}
+	collection := Goify(mt.TypeName, true)
+	data := map[string]interface{}{
+		"Name":       strings.TrimSuffix(collection, "Collection") + "Page",
+		"Collection": collection,
+	}
+	return RunTemplate(pageT, data), nil
+}
+
+const pageTmpl = `// {{ .Name }} is a page of a cursor paginated {{ .Collection }}.
+type {{ .Name }} struct {
+	// Items lists the elements of the page.
+	Items {{ .Collection }} ` + "`" + `form:"items" json:"items" xml:"items"` + "`" + `
+	// NextCursor is the cursor of the next page, nil if there are no more elements.
+	NextCursor *string ` + "`" + `form:"next_cursor,omitempty" json:"next_cursor,omitempty" xml:"next_cursor,omitempty"` + "`" + `
+	// HasMore is true if there are more elements after the page.
+	HasMore bool ` + "`" + `form:"has_more" json:"has_more" xml:"has_more"` + "`" + `
+}
+
+// New{{ .Name }} creates a page from items fetched with a limit of pageSize+1. If there are more
+// than pageSize items the extra items are dropped, HasMore is set and NextCursor is set to
+// nextCursor.
+func New{{ .Name }}(items {{ .Collection }}, pageSize int, nextCursor string) *{{ .Name }} {
+	if items == nil {
+		items = {{ .Collection }}{}
+	}
+	page := &{{ .Name }}{Items: items}
+	if pageSize >= 0 && len(items) > pageSize {
+		page.Items = items[:pageSize]
+		page.HasMore = true
+		page.NextCursor = &nextCursor
+	}
+	return page
+}
+`
@@ -0,0 +1,94 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoPageType", func() {
+	var mt *design.MediaTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		bottle := &design.MediaTypeDefinition{
+			UserTypeDefinition: &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"id": &design.AttributeDefinition{Type: design.Integer}},
+				},
+				TypeName: "Bottle",
+			},
+			Identifier: "application/vnd.bottle",
+		}
+		mt = &design.MediaTypeDefinition{
+			UserTypeDefinition: &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type:     &design.Array{ElemType: &design.AttributeDefinition{Type: bottle}},
+					Metadata: dslengine.MetadataDefinition{"collection:paginated": nil},
+				},
+				TypeName: "BottleCollection",
+			},
+			Identifier: "application/vnd.bottle; type=collection",
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoPageType(mt)
+	})
+
+	It("generates the page wrapper and its constructor", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(pageCode))
+	})
+
+	Context("with a collection that is not paginated", func() {
+		BeforeEach(func() {
+			mt.Metadata = nil
+		})
+
+		It("generates nothing", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(BeEmpty())
+		})
+	})
+
+	Context("with a paginated media type that is not a collection", func() {
+		BeforeEach(func() {
+			mt.Type = design.Object{"id": &design.AttributeDefinition{Type: design.Integer}}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const pageCode = `// BottlePage is a page of a cursor paginated BottleCollection.
+type BottlePage struct {
+	// Items lists the elements of the page.
+	Items BottleCollection ` + "`" + `form:"items" json:"items" xml:"items"` + "`" + `
+	// NextCursor is the cursor of the next page, nil if there are no more elements.
+	NextCursor *string ` + "`" + `form:"next_cursor,omitempty" json:"next_cursor,omitempty" xml:"next_cursor,omitempty"` + "`" + `
+	// HasMore is true if there are more elements after the page.
+	HasMore bool ` + "`" + `form:"has_more" json:"has_more" xml:"has_more"` + "`" + `
+}
+
+// NewBottlePage creates a page from items fetched with a limit of pageSize+1. If there are more
+// than pageSize items the extra items are dropped, HasMore is set and NextCursor is set to
+// nextCursor.
+func NewBottlePage(items BottleCollection, pageSize int, nextCursor string) *BottlePage {
+	if items == nil {
+		items = BottleCollection{}
+	}
+	page := &BottlePage{Items: items}
+	if pageSize >= 0 && len(items) > pageSize {
+		page.Items = items[:pageSize]
+		page.HasMore = true
+		page.NextCursor = &nextCursor
+	}
+	return page
+}
+`
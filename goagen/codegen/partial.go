@@ -0,0 +1,62 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var partialCtorT *template.Template
+
+func init() {
+	var err error
+	if partialCtorT, err = template.New("partialCtor").Parse(partialCtorTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoPartialConstructor produces the Go code of the NewFooFromPartial function that instantiates
+// the struct generated for the media type Foo from the attributes of one of its views. This makes
+// it possible to build the struct from a group of fields only, e.g. the fields that may be
+// modified by an update endpoint. The function arguments are sorted by attribute name and have
+// the same types as the corresponding struct fields.
+// The function returns an error if the media type does not define the view or if the view lists
+// attributes that are not defined by the media type.
+func GoPartialConstructor(mt *design.MediaTypeDefinition, view string) (string, error) {
+	v, ok := mt.Views[view]
+	if !ok {
+		return "", fmt.Errorf("media type %s has no view %q", mt.TypeName, view)
+	}
+	obj := mt.Type.ToObject()
+	var fields []map[string]interface{}
+	err := v.Type.ToObject().IterateAttributes(func(n string, _ *design.AttributeDefinition) error {
+		att, ok := obj[n]
+		if !ok {
+			return fmt.Errorf("view %q of media type %s: unknown attribute %q", view, mt.TypeName, n)
+		}
+		fields = append(fields, map[string]interface{}{
+			"Field": GoifyAtt(att, n, true),
+			"Param": Goify(n, false),
+			"Type":  fieldTypeDef(mt.AttributeDefinition, n, 1, true, false, false),
+		})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	data := map[string]interface{}{
+		"TypeName": Goify(mt.TypeName, true),
+		"View":     view,
+		"Fields":   fields,
+	}
+	return RunTemplate(partialCtorT, data), nil
+}
+
+const partialCtorTmpl = `// New{{ .TypeName }}FromPartial instantiates a {{ .TypeName }} from the fields of its {{ printf "%q" .View }} view.
+func New{{ .TypeName }}FromPartial({{ range $i, $f := .Fields }}{{ if $i }}, {{ end }}{{ $f.Param }} {{ $f.Type }}{{ end }}) *{{ .TypeName }} {
+	return &{{ .TypeName }}{
+{{ range .Fields }}		{{ .Field }}: {{ .Param }},
+{{ end }}	}
+}
+`
@@ -0,0 +1,70 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoPartialConstructor", func() {
+	var view string
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		view = "update"
+	})
+
+	JustBeforeEach(func() {
+		att := &design.AttributeDefinition{
+			Type: design.Object{
+				"id":    &design.AttributeDefinition{Type: design.Integer},
+				"name":  &design.AttributeDefinition{Type: design.String},
+				"email": &design.AttributeDefinition{Type: design.String},
+			},
+			Validation: &dslengine.ValidationDefinition{Required: []string{"id", "name"}},
+		}
+		mt := &design.MediaTypeDefinition{
+			UserTypeDefinition: &design.UserTypeDefinition{AttributeDefinition: att, TypeName: "User"},
+		}
+		mt.Views = map[string]*design.ViewDefinition{
+			"update": {
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"name":  &design.AttributeDefinition{Type: design.String},
+						"email": &design.AttributeDefinition{Type: design.String},
+					},
+				},
+				Name:   "update",
+				Parent: mt,
+			},
+		}
+		code, err = codegen.GoPartialConstructor(mt, view)
+	})
+
+	It("generates the constructor for the view fields", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(partialCtorCode))
+	})
+
+	Context("given an unknown view", func() {
+		BeforeEach(func() {
+			view = "unknown"
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const partialCtorCode = `// NewUserFromPartial instantiates a User from the fields of its "update" view.
+func NewUserFromPartial(email *string, name string) *User {
+	return &User{
+		Email: email,
+		Name: name,
+	}
+}
+`
@@ -53,14 +53,55 @@ func RecursivePublicizer(att *design.AttributeDefinition, source, target string,
 			att = ds.Definition()
 		}
 		o.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+			// The primitive fields of the private struct are pointers, the public struct
+			// fields may use any number of pointer indirections.
+			sourceField := fmt.Sprintf("%s.%s", source, Goify(n, true))
+			dereference := false
+			if catt.Type.IsPrimitive() {
+				switch fieldPointers(att, n, false) {
+				case 0:
+					dereference = true
+				case 2:
+					sourceField = "&" + sourceField
+				}
+			}
+			targetField := fmt.Sprintf("%s.%s", target, Goify(n, true))
+			if isLazy(att, n, false) {
+				// Lazy fields are publicized into their cache.
+				targetField = fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, false))
+			}
 			publication := Publicizer(
 				catt,
-				fmt.Sprintf("%s.%s", source, Goify(n, true)),
-				fmt.Sprintf("%s.%s", target, Goify(n, true)),
-				catt.Type.IsPrimitive() && !att.IsPrimitivePointer(n),
+				sourceField,
+				targetField,
+				dereference,
 				depth+1,
 				false,
 			)
+			if isLazy(att, n, false) {
+				publication += fmt.Sprintf("\n%s%sComputed = true", Tabs(depth+1), targetField)
+			}
+			if null := sqlNullType(att, n); null != "" {
+				// The Null fields are set from the values of the private pointer fields.
+				// The Null types embed the database/sql type of the same name.
+				typ := strings.TrimPrefix(null, "goa.")
+				valField := strings.TrimPrefix(typ, "Null")
+				value := "*" + sourceField
+				if catt.Type.Kind() == design.IntegerKind {
+					value = "int64(" + value + ")"
+				}
+				publication = fmt.Sprintf("%s%s = %s{%s: sql.%s{%s: %s, Valid: true}}",
+					Tabs(depth+1), targetField, null, typ, typ, valField, value)
+			}
+			if isAtomic(att, n, false) {
+				// Atomic fields are stored, the values of the private integer fields are
+				// converted to the int64 values of atomic.Int64.
+				value := "*" + sourceField
+				if catt.Type.Kind() == design.IntegerKind {
+					value = "int64(" + value + ")"
+				}
+				publication = fmt.Sprintf("%s%s.Store(%s)", Tabs(depth+1), targetField, value)
+			}
 			publication = fmt.Sprintf("%sif %s.%s != nil {\n%s\n%s}",
 				Tabs(depth), source, Goify(n, true), publication, Tabs(depth))
 			publications = append(publications, publication)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -167,8 +168,64 @@ var _ = Describe("Struct publicize code generation", func() {
 			})
 		})
 	})
+
+	Describe("RecursivePublicizer", func() {
+		Context("given optional sqlnull fields", func() {
+			var ut *design.UserTypeDefinition
+
+			BeforeEach(func() {
+				null := dslengine.MetadataDefinition{codegen.SQLNullKey: nil}
+				ut = &design.UserTypeDefinition{
+					TypeName: "Item",
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"count":  &design.AttributeDefinition{Type: design.Integer, Metadata: null},
+							"name":   &design.AttributeDefinition{Type: design.String, Metadata: null},
+							"price":  &design.AttributeDefinition{Type: design.Number, Metadata: null},
+							"active": &design.AttributeDefinition{Type: design.Boolean, Metadata: null},
+						},
+					},
+				}
+			})
+
+			It("sets the Null fields from the private pointers", func() {
+				publication := codegen.RecursivePublicizer(ut.AttributeDefinition, "source", "target", 0)
+				Ω(publication).Should(Equal(sqlNullPublicizeCode))
+			})
+
+			It("generates code that compiles", func() {
+				src := "package item\n\nimport (\n\t\"database/sql\"\n\n\t\"github.com/goadesign/goa\"\n)\n\n" +
+					"type Item " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" +
+					"type item " + codegen.GoTypeDef(ut, 0, true, true) + "\n\n" +
+					"func (ut *item) Publicize() *Item {\n\tvar pub Item\n" +
+					codegen.RecursivePublicizer(ut.AttributeDefinition, "ut", "pub", 1) +
+					"\n\treturn &pub\n}\n"
+				out, err := goTest(map[string]string{"item.go": src, "item_test.go": sqlNullPublicizeTest})
+				Ω(err).ShouldNot(HaveOccurred(), out)
+			})
+		})
+	})
 })
 
+const sqlNullPublicizeTest = `package item
+
+import "testing"
+
+func TestPublicize(t *testing.T) {
+	count, name := 3, "n"
+	pub := (&item{Count: &count, Name: &name}).Publicize()
+	if !pub.Count.Valid || pub.Count.Int64 != 3 {
+		t.Errorf("unexpected count %+v", pub.Count)
+	}
+	if !pub.Name.Valid || pub.Name.String != "n" {
+		t.Errorf("unexpected name %+v", pub.Name)
+	}
+	if pub.Price.Valid || pub.Active.Valid {
+		t.Errorf("unset fields are valid: %+v", pub)
+	}
+}
+`
+
 const (
 	objectPublicizeCode = `target = &struct {
 	Foo *string ` + "`" + `form:"foo,omitempty" json:"foo,omitempty" xml:"foo,omitempty"` + "`" + `
@@ -189,3 +246,16 @@ for k0, v0 := range source {
 	target[pubk0] = pubv0
 }`
 )
+
+const sqlNullPublicizeCode = `if source.Active != nil {
+	target.Active = goa.NullBool{NullBool: sql.NullBool{Bool: *source.Active, Valid: true}}
+}
+if source.Count != nil {
+	target.Count = goa.NullInt64{NullInt64: sql.NullInt64{Int64: int64(*source.Count), Valid: true}}
+}
+if source.Name != nil {
+	target.Name = goa.NullString{NullString: sql.NullString{String: *source.Name, Valid: true}}
+}
+if source.Price != nil {
+	target.Price = goa.NullFloat64{NullFloat64: sql.NullFloat64{Float64: *source.Price, Valid: true}}
+}`
@@ -0,0 +1,111 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var queryEncoderT *template.Template
+
+func init() {
+	var err error
+	if queryEncoderT, err = template.New("queryEncoder").Parse(queryEncoderTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoQueryEncoder produces the Go code of the EncodeQuery method of the struct generated for the
+// given params attribute. EncodeQuery is the inverse of the param binding: it builds the
+// url.Values corresponding to the struct field values. Scalar fields are formatted using strconv,
+// array fields produce one value per element under the same key and optional fields that are not
+// set are omitted.
+// typeName is the name of the generated struct, e.g. "ListUsersParams".
+// The function returns an error if params is not an object or if one of its attributes cannot be
+// represented in a query string (objects, hashes and arrays of non primitive types).
+func GoQueryEncoder(params *design.AttributeDefinition, typeName string) (string, error) {
+	obj := params.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("params of %s must be an object", typeName)
+	}
+	var fields []map[string]interface{}
+	err := obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		field := "p." + GoifyAtt(att, n, true)
+		data := map[string]interface{}{"Name": n, "Field": field}
+		if arr := att.Type.ToArray(); arr != nil {
+			code, err := queryValueCode(arr.ElemType.Type, "v")
+			if err != nil {
+				return fmt.Errorf("%s.%s: %s", typeName, n, err)
+			}
+			data["Array"] = true
+			data["Value"] = code
+		} else {
+			pointer := params.IsPrimitivePointer(n)
+			val := field
+			if pointer {
+				val = "*" + field
+			}
+			code, err := queryValueCode(att.Type, val)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %s", typeName, n, err)
+			}
+			data["Pointer"] = pointer
+			data["Value"] = code
+		}
+		fields = append(fields, data)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	data := map[string]interface{}{
+		"TypeName": typeName,
+		"Fields":   fields,
+	}
+	return RunTemplate(queryEncoderT, data), nil
+}
+
+// queryValueCode returns the Go expression that formats the value held in the variable val of
+// type t as a query string value.
+func queryValueCode(t design.DataType, val string) (string, error) {
+	if !t.IsPrimitive() {
+		return "", fmt.Errorf("cannot encode values of type %s in a query string", t.Name())
+	}
+	recv := val
+	if strings.HasPrefix(recv, "*") {
+		// Dereferences must be grouped before the Format and String method calls.
+		recv = "(" + recv + ")"
+	}
+	switch t.Kind() {
+	case design.BooleanKind:
+		return fmt.Sprintf("strconv.FormatBool(%s)", val), nil
+	case design.IntegerKind:
+		return fmt.Sprintf("strconv.Itoa(%s)", val), nil
+	case design.NumberKind:
+		return fmt.Sprintf("strconv.FormatFloat(%s, 'f', -1, 64)", val), nil
+	case design.StringKind:
+		return val, nil
+	case design.DateTimeKind:
+		return fmt.Sprintf("%s.Format(time.RFC3339)", recv), nil
+	case design.UUIDKind, design.LanguageKind:
+		return fmt.Sprintf("%s.String()", recv), nil
+	default:
+		return fmt.Sprintf("fmt.Sprintf(\"%%v\", %s)", val), nil
+	}
+}
+
+const queryEncoderTmpl = `// EncodeQuery returns the query string values built from the {{ .TypeName }} fields.
+func (p *{{ .TypeName }}) EncodeQuery() url.Values {
+	values := url.Values{}
+{{ range .Fields }}{{ if .Array }}	for _, v := range {{ .Field }} {
+		values.Add({{ printf "%q" .Name }}, {{ .Value }})
+	}
+{{ else if .Pointer }}	if {{ .Field }} != nil {
+		values.Set({{ printf "%q" .Name }}, {{ .Value }})
+	}
+{{ else }}	values.Set({{ printf "%q" .Name }}, {{ .Value }})
+{{ end }}{{ end }}	return values
+}
+`
@@ -0,0 +1,128 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoQueryEncoder", func() {
+	var params *design.AttributeDefinition
+	var code string
+	var err error
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoQueryEncoder(params, "ListUsersParams")
+	})
+
+	Context("given params with scalar and array fields", func() {
+		BeforeEach(func() {
+			params = &design.AttributeDefinition{
+				Type: design.Object{
+					"limit":  &design.AttributeDefinition{Type: design.Integer},
+					"active": &design.AttributeDefinition{Type: design.Boolean},
+					"name":   &design.AttributeDefinition{Type: design.String},
+					"tags":   &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"limit", "active"}},
+			}
+		})
+
+		It("generates the EncodeQuery method", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(Equal(queryEncoderCode))
+		})
+	})
+
+	Context("given params with optional date time and UUID fields", func() {
+		BeforeEach(func() {
+			params = &design.AttributeDefinition{
+				Type: design.Object{
+					"since": &design.AttributeDefinition{Type: design.DateTime},
+					"owner": &design.AttributeDefinition{Type: design.UUID},
+				},
+			}
+		})
+
+		It("groups the dereferences before formatting the values", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(Equal(queryEncoderPointersCode))
+		})
+
+		It("generates code that compiles and encodes the set fields", func() {
+			src := "package query\n\nimport (\n\t\"net/url\"\n\t\"time\"\n\n\t\"github.com/goadesign/goa/uuid\"\n)\n\n" +
+				"type ListUsersParams " + codegen.GoTypeDef(params, 0, true, false) + "\n\n" + code
+			out, err := goTest(map[string]string{"query.go": src, "query_test.go": queryUsageTest})
+			Ω(err).ShouldNot(HaveOccurred(), out)
+		})
+	})
+
+	Context("given params with an object field", func() {
+		BeforeEach(func() {
+			params = &design.AttributeDefinition{
+				Type: design.Object{
+					"filter": &design.AttributeDefinition{Type: design.Object{}},
+				},
+			}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const queryEncoderCode = `// EncodeQuery returns the query string values built from the ListUsersParams fields.
+func (p *ListUsersParams) EncodeQuery() url.Values {
+	values := url.Values{}
+	values.Set("active", strconv.FormatBool(p.Active))
+	values.Set("limit", strconv.Itoa(p.Limit))
+	if p.Name != nil {
+		values.Set("name", *p.Name)
+	}
+	for _, v := range p.Tags {
+		values.Add("tags", v)
+	}
+	return values
+}
+`
+
+const queryEncoderPointersCode = `// EncodeQuery returns the query string values built from the ListUsersParams fields.
+func (p *ListUsersParams) EncodeQuery() url.Values {
+	values := url.Values{}
+	if p.Owner != nil {
+		values.Set("owner", (*p.Owner).String())
+	}
+	if p.Since != nil {
+		values.Set("since", (*p.Since).Format(time.RFC3339))
+	}
+	return values
+}
+`
+
+const queryUsageTest = `package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goadesign/goa/uuid"
+)
+
+func TestEncodeQuery(t *testing.T) {
+	if values := (&ListUsersParams{}).EncodeQuery(); len(values) != 0 {
+		t.Errorf("unexpected values %v", values)
+	}
+	since := time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+	owner := uuid.NewV4()
+	values := (&ListUsersParams{Since: &since, Owner: &owner}).EncodeQuery()
+	if v := values.Get("since"); v != "2016-01-02T03:04:05Z" {
+		t.Errorf("unexpected since %q", v)
+	}
+	if v := values.Get("owner"); v != owner.String() {
+		t.Errorf("unexpected owner %q", v)
+	}
+}
+`
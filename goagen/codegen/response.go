@@ -0,0 +1,70 @@
+package codegen
+
+import (
+	"fmt"
+	"net/http"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var responseWriterT *template.Template
+
+func init() {
+	var err error
+	if responseWriterT, err = template.New("responseWriter").Parse(responseWriterTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoResponseWriter produces the Go code of a function that writes the given response to a
+// http.ResponseWriter, e.g. "WriteShowBottleOK". name is the prefix of the function name after
+// "Write", typically the action and resource names. mt is the media type of the response body or
+// nil if the response has no body. The function marshals the body to JSON, sets the Content-Type
+// header to the media type identifier and writes the response status and body. The body of
+// responses using the error media type is a *goa.ErrorResponse.
+// The function returns an error if the response status is not set.
+func GoResponseWriter(name string, r *design.ResponseDefinition, mt *design.MediaTypeDefinition) (string, error) {
+	if r.Status == 0 {
+		return "", fmt.Errorf("response %s has no status", r.Name)
+	}
+	data := map[string]interface{}{
+		"FuncName":   "Write" + Goify(name, true) + Goify(r.Name, true),
+		"Status":     r.Status,
+		"StatusText": http.StatusText(r.Status),
+	}
+	if mt != nil {
+		bodyType := GoTypeRef(mt, mt.AllRequired(), 0, false)
+		bodyName := Goify(mt.TypeName, true)
+		if mt.IsError() {
+			bodyType = "*goa.ErrorResponse"
+			bodyName = "error"
+		}
+		contentType := mt.Identifier
+		if r.MediaType != "" {
+			contentType = r.MediaType
+		}
+		data["BodyType"] = bodyType
+		data["BodyName"] = bodyName
+		data["ContentType"] = contentType
+	}
+	return RunTemplate(responseWriterT, data), nil
+}
+
+const responseWriterTmpl = `{{ if .BodyType }}// {{ .FuncName }} writes a {{ .Status }} {{ .StatusText }} response with the given {{ .BodyName }} body to w.
+func {{ .FuncName }}(w http.ResponseWriter, body {{ .BodyType }}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", {{ printf "%q" .ContentType }})
+	w.WriteHeader({{ .Status }})
+	_, err = w.Write(b)
+	return err
+}
+{{ else }}// {{ .FuncName }} writes a {{ .Status }} {{ .StatusText }} response with no body to w.
+func {{ .FuncName }}(w http.ResponseWriter) error {
+	w.WriteHeader({{ .Status }})
+	return nil
+}
+{{ end }}`
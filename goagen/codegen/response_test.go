@@ -0,0 +1,104 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoResponseWriter", func() {
+	var resp *design.ResponseDefinition
+	var mt *design.MediaTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		mt = &design.MediaTypeDefinition{
+			UserTypeDefinition: &design.UserTypeDefinition{
+				TypeName:            "Bottle",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.Object{"name": {Type: design.String}}},
+			},
+			Identifier: "application/vnd.goa.bottle",
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoResponseWriter("ShowBottle", resp, mt)
+	})
+
+	Context("with a 200 response", func() {
+		BeforeEach(func() {
+			resp = &design.ResponseDefinition{Name: "OK", Status: 200, MediaType: "application/vnd.goa.bottle"}
+		})
+
+		It("generates the response writer", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(Equal(okResponseWriterCode))
+		})
+	})
+
+	Context("with a 201 response", func() {
+		BeforeEach(func() {
+			resp = &design.ResponseDefinition{Name: "Created", Status: 201}
+		})
+
+		It("generates the response writer using the media type identifier", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(Equal(createdResponseWriterCode))
+		})
+	})
+
+	Context("with an error response", func() {
+		BeforeEach(func() {
+			resp = &design.ResponseDefinition{Name: "BadRequest", Status: 400}
+			mt = design.ErrorMedia
+		})
+
+		It("generates a writer taking the goa error response", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(ContainSubstring("func WriteShowBottleBadRequest(w http.ResponseWriter, body *goa.ErrorResponse) error {\n"))
+			Ω(code).Should(ContainSubstring("\tw.WriteHeader(400)\n"))
+		})
+	})
+
+	Context("with a response with no body", func() {
+		BeforeEach(func() {
+			resp = &design.ResponseDefinition{Name: "NoContent", Status: 204}
+			mt = nil
+		})
+
+		It("only writes the status", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(Equal("// WriteShowBottleNoContent writes a 204 No Content response with no body to w.\nfunc WriteShowBottleNoContent(w http.ResponseWriter) error {\n\tw.WriteHeader(204)\n\treturn nil\n}\n"))
+		})
+	})
+})
+
+const (
+	okResponseWriterCode = `// WriteShowBottleOK writes a 200 OK response with the given Bottle body to w.
+func WriteShowBottleOK(w http.ResponseWriter, body *Bottle) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/vnd.goa.bottle")
+	w.WriteHeader(200)
+	_, err = w.Write(b)
+	return err
+}
+`
+
+	createdResponseWriterCode = `// WriteShowBottleCreated writes a 201 Created response with the given Bottle body to w.
+func WriteShowBottleCreated(w http.ResponseWriter, body *Bottle) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/vnd.goa.bottle")
+	w.WriteHeader(201)
+	_, err = w.Write(b)
+	return err
+}
+`
+)
@@ -0,0 +1,185 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/goadesign/goa/design"
+)
+
+var roundTripT *template.Template
+
+func init() {
+	var err error
+	if roundTripT, err = template.New("roundTrip").Parse(roundTripTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoRoundTripTest produces the Go code of a test function that checks that the struct generated
+// for the given user type survives a JSON round trip, e.g. "TestUserMarshalRoundTrip". The test
+// decodes count JSON examples built with the design example generator using different random
+// seeds, encodes the decoded values, decodes the result again and checks that both decoded values
+// are equal. The examples use the JSON names of the struct fields and encode the duration
+// attributes like the goa Duration types of their fields. The generated code requires the "encoding/json", "reflect" and "testing" packages.
+// The function returns an error if ut is not an object or if an example cannot be encoded.
+func GoRoundTripTest(ut *design.UserTypeDefinition, count int) (string, error) {
+	if ut.Type.ToObject() == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	examples := make([]string, count)
+	for i := range examples {
+		seed := fmt.Sprintf("%s%d", ut.TypeName, i)
+		r := design.NewRandomGenerator(seed)
+		att := withoutExamples(ut.AttributeDefinition, make(map[string]design.DataType))
+		ex := jsonExample(att, att.GenerateExample(r, nil), r)
+		b, err := json.Marshal(ex)
+		if err != nil {
+			return "", fmt.Errorf("type %s: failed to encode example: %s", ut.TypeName, err)
+		}
+		examples[i] = strconv.Quote(string(b))
+	}
+	data := map[string]interface{}{
+		"Name":     Goify(ut.TypeName, true),
+		"Examples": examples,
+	}
+	return RunTemplate(roundTripT, data), nil
+}
+
+// jsonExample returns the JSON representation of the value ex generated by the design example
+// generator for att: the keys of objects are renamed after the JSON names of the struct fields,
+// the keys of the fields that are not encoded are removed and the values of duration attributes
+// are replaced with random durations in the encoding of their fields.
+func jsonExample(att *design.AttributeDefinition, ex interface{}, r *design.RandomGenerator) interface{} {
+	if d := durationType(att); d != "" {
+		sec := r.Int() % 3600
+		if d == "goa.DurationSeconds" {
+			return sec
+		}
+		return (time.Duration(sec) * time.Second).String()
+	}
+	if ex == nil {
+		return nil
+	}
+	if obj := att.Type.ToObject(); obj != nil {
+		m, ok := ex.(map[string]interface{})
+		if !ok {
+			return ex
+		}
+		res := make(map[string]interface{}, len(m))
+		obj.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+			v, ok := m[n]
+			if !ok {
+				return nil
+			}
+			if name := jsonName(catt, n); name != "-" {
+				res[name] = jsonExample(catt, v, r)
+			}
+			return nil
+		})
+		return res
+	}
+	if arr := att.Type.ToArray(); arr != nil {
+		elems, ok := ex.([]interface{})
+		if !ok {
+			return ex
+		}
+		res := make([]interface{}, len(elems))
+		for i, e := range elems {
+			res[i] = jsonExample(arr.ElemType, e, r)
+		}
+		return res
+	}
+	if h := att.Type.ToHash(); h != nil {
+		m := reflect.ValueOf(ex)
+		if m.Kind() != reflect.Map {
+			return ex
+		}
+		res := reflect.MakeMap(reflect.MapOf(m.Type().Key(), reflect.TypeOf((*interface{})(nil)).Elem()))
+		for _, k := range m.MapKeys() {
+			v := jsonExample(h.ElemType, m.MapIndex(k).Interface(), r)
+			res.SetMapIndex(k, reflect.ValueOf(&v).Elem())
+		}
+		return res.Interface()
+	}
+	return ex
+}
+
+// withoutExamples returns a deep copy of att where the examples are cleared so that the design
+// example generator produces new examples instead of returning the ones it recorded previously.
+// copies records the copies of the user and media types indexed by name to handle cycles.
+func withoutExamples(att *design.AttributeDefinition, copies map[string]design.DataType) *design.AttributeDefinition {
+	dup := design.DupAtt(att)
+	dup.Example = nil
+	switch actual := att.Type.(type) {
+	case *design.Array:
+		dup.Type = &design.Array{ElemType: withoutExamples(actual.ElemType, copies)}
+	case *design.Hash:
+		dup.Type = &design.Hash{
+			KeyType:  withoutExamples(actual.KeyType, copies),
+			ElemType: withoutExamples(actual.ElemType, copies),
+		}
+	case design.Object:
+		obj := make(design.Object, len(actual))
+		for n, catt := range actual {
+			obj[n] = withoutExamples(catt, copies)
+		}
+		dup.Type = obj
+	case *design.UserTypeDefinition:
+		if c, ok := copies[actual.TypeName]; ok {
+			dup.Type = c
+			break
+		}
+		ut := &design.UserTypeDefinition{TypeName: actual.TypeName}
+		copies[actual.TypeName] = ut
+		ut.AttributeDefinition = withoutExamples(actual.AttributeDefinition, copies)
+		dup.Type = ut
+	case *design.MediaTypeDefinition:
+		if c, ok := copies[actual.TypeName]; ok {
+			dup.Type = c
+			break
+		}
+		mt := &design.MediaTypeDefinition{
+			Identifier: actual.Identifier,
+			Links:      actual.Links,
+			Views:      actual.Views,
+			Resource:   actual.Resource,
+		}
+		copies[actual.TypeName] = mt
+		mt.UserTypeDefinition = &design.UserTypeDefinition{
+			TypeName:            actual.TypeName,
+			AttributeDefinition: withoutExamples(actual.AttributeDefinition, copies),
+		}
+		dup.Type = mt
+	}
+	return dup
+}
+
+const roundTripTmpl = `// Test{{ .Name }}MarshalRoundTrip checks that {{ .Name }} values survive a JSON round trip.
+func Test{{ .Name }}MarshalRoundTrip(t *testing.T) {
+	examples := []string{
+{{ range .Examples }}		{{ . }},
+{{ end }}	}
+	for i, ex := range examples {
+		var v {{ .Name }}
+		if err := json.Unmarshal([]byte(ex), &v); err != nil {
+			t.Fatalf("example %d: failed to decode: %s", i, err)
+		}
+		b, err := json.Marshal(&v)
+		if err != nil {
+			t.Fatalf("example %d: failed to encode: %s", i, err)
+		}
+		var v2 {{ .Name }}
+		if err := json.Unmarshal(b, &v2); err != nil {
+			t.Fatalf("example %d: failed to decode encoded value: %s", i, err)
+		}
+		if !reflect.DeepEqual(v, v2) {
+			t.Errorf("example %d: round trip mismatch:\n%#v\n%#v", i, v, v2)
+		}
+	}
+}
+`
@@ -0,0 +1,132 @@
+package codegen_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoRoundTripTest", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		if design.Design == nil {
+			design.Design = design.NewAPIDefinition()
+		}
+		ut = &design.UserTypeDefinition{
+			TypeName: "Sample",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"name":  &design.AttributeDefinition{Type: design.String},
+					"count": &design.AttributeDefinition{Type: design.Integer},
+					"ratio": &design.AttributeDefinition{Type: design.Number},
+					"at":    &design.AttributeDefinition{Type: design.DateTime},
+					"tags":  &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+					"labels": &design.AttributeDefinition{Type: &design.Hash{
+						KeyType:  &design.AttributeDefinition{Type: design.String},
+						ElemType: &design.AttributeDefinition{Type: design.Integer},
+					}},
+					"nested": &design.AttributeDefinition{Type: design.Object{
+						"enabled": &design.AttributeDefinition{Type: design.Boolean},
+					}},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name", "count"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoRoundTripTest(ut, 3)
+	})
+
+	It("generates a round trip test with one example per seed", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(ContainSubstring("func TestSampleMarshalRoundTrip(t *testing.T) {"))
+		again, err := codegen.GoRoundTripTest(ut, 3)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(again).Should(Equal(code))
+	})
+
+	It("generates a harness that compiles and passes", func() {
+		dir, err := ioutil.TempDir("", "roundtrip")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		src, err := codegen.GenerateFile("sample", []design.DataStructure{ut})
+		Ω(err).ShouldNot(HaveOccurred())
+		test := "package sample\n\nimport (\n\t\"encoding/json\"\n\t\"reflect\"\n\t\"testing\"\n)\n\n" + code
+		Ω(ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module sample\n"), 0644)).Should(Succeed())
+		Ω(ioutil.WriteFile(filepath.Join(dir, "sample.go"), src, 0644)).Should(Succeed())
+		Ω(ioutil.WriteFile(filepath.Join(dir, "sample_test.go"), []byte(test), 0644)).Should(Succeed())
+
+		cmd := exec.Command(filepath.Join(runtime.GOROOT(), "bin", "go"), "test", ".")
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		Ω(err).ShouldNot(HaveOccurred(), string(out))
+	})
+
+	Context("with a renamed field and duration fields", func() {
+		BeforeEach(func() {
+			obj := ut.Type.ToObject()
+			obj["createdBy"] = &design.AttributeDefinition{
+				Type:     design.String,
+				Metadata: dslengine.MetadataDefinition{"struct:tag:json": {"created_by,omitempty"}},
+			}
+			obj["timeout"] = &design.AttributeDefinition{
+				Type:     design.String,
+				Metadata: dslengine.MetadataDefinition{codegen.DurationKey: nil},
+			}
+			obj["ttl"] = &design.AttributeDefinition{
+				Type:     design.Number,
+				Metadata: dslengine.MetadataDefinition{codegen.DurationKey: {"seconds"}},
+			}
+		})
+
+		It("builds the examples with the JSON names of the fields", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(ContainSubstring(`\"created_by\":`))
+			Ω(code).ShouldNot(ContainSubstring(`\"createdBy\":`))
+		})
+
+		It("generates a harness that compiles and passes", func() {
+			src, err := codegen.GenerateFile("sample", []design.DataStructure{ut})
+			Ω(err).ShouldNot(HaveOccurred())
+			test := "package sample\n\nimport (\n\t\"encoding/json\"\n\t\"reflect\"\n\t\"testing\"\n)\n\n" + code +
+				roundTripFieldsTest
+			out, err := goTest(map[string]string{"sample.go": string(src), "sample_test.go": test})
+			Ω(err).ShouldNot(HaveOccurred(), out)
+		})
+	})
+
+	Context("with a type that is not an object", func() {
+		BeforeEach(func() {
+			ut.Type = design.String
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const roundTripFieldsTest = `
+func TestSampleExampleFields(t *testing.T) {
+	var v Sample
+	if err := json.Unmarshal([]byte(` + "`" + `{"created_by":"joe","timeout":"1m30s","ttl":90}` + "`" + `), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.CreatedBy == nil || *v.CreatedBy != "joe" {
+		t.Errorf("unexpected created by %v", v.CreatedBy)
+	}
+}
+`
@@ -0,0 +1,128 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var pathCtorT *template.Template
+
+func init() {
+	var err error
+	if pathCtorT, err = template.New("pathCtor").Parse(pathCtorTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoPathConstructors produces the Go code of the functions that build the request paths of the
+// given action routes, e.g. "ShowPostPath" for the "show" action of the "post" resource. The
+// functions accept one string argument per route parameter in the order the parameters appear in
+// the route path and build the path from the route constant with goa.RoutePath. The constants are
+// not part of the code, callers emit them once per package with GoRouteConstants. The argument values are URL-escaped: the "/"
+// character is escaped for named parameters (":id") but not for catch-all parameters ("*path").
+// Functions generated for the second and subsequent routes have a numeric suffix, e.g.
+// "ShowPostPath2".
+func GoPathConstructors(a *design.ActionDefinition) string {
+	base := Goify(a.Name+strings.Title(a.Parent.Name), true) + "Path"
+	var code string
+	for i, r := range a.Routes {
+		name := base
+		if i > 0 {
+			name = fmt.Sprintf("%s%d", base, i+1)
+		}
+		var params, args []string
+		for _, p := range r.Params() {
+			p = Goify(p, false)
+			params = append(params, p+" string")
+			args = append(args, p)
+		}
+		data := map[string]interface{}{
+			"Name":     name,
+			"Action":   a.Name,
+			"Resource": a.Parent.Name,
+			"Params":   strings.Join(params, ", "),
+			"Path":     GoRoutePath(r),
+			"Args":     args,
+		}
+		if i > 0 {
+			code += "\n"
+		}
+		code += RunTemplate(pathCtorT, data)
+	}
+	return code
+}
+
+// GoRouteConstants produces the Go code of the constants that hold the path templates of the given
+// action routes verbatim as written in the design, e.g.
+//
+//	const ShowUserRoute = "/:id"
+//
+// for the "show" action of the "user" resource, so that the code that builds request paths and the
+// code that registers the action handlers may share the same definition, see GoRoutePath.
+// Constants generated for the second and subsequent routes have a numeric suffix, e.g.
+// "ShowUserRoute2".
+func GoRouteConstants(a *design.ActionDefinition) string {
+	if len(a.Routes) == 0 {
+		return ""
+	}
+	code := fmt.Sprintf("// Route path templates of the %s action of %s.\nconst (\n", a.Name, a.Parent.Name)
+	for i, r := range a.Routes {
+		code += fmt.Sprintf("\t%s = %q\n", routeConstName(a, i), r.Path)
+	}
+	return code + ")\n"
+}
+
+// GoRoutePath returns the Go expression that computes the full path of the given route from the
+// route constant produced by GoRouteConstants, e.g. "/users" + ShowUserRoute where "/users" is the
+// base path of the resource. The expression is the full path literal if the route does not belong
+// to an action or if its full path does not end with the route path, e.g. when the route path has
+// a trailing slash that is cleaned.
+func GoRoutePath(r *design.RouteDefinition) string {
+	full := r.FullPath()
+	lit := fmt.Sprintf("%q", full)
+	a := r.Parent
+	if a == nil || a.Parent == nil {
+		return lit
+	}
+	idx := -1
+	for i, ar := range a.Routes {
+		if ar == r {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return lit
+	}
+	name, p := routeConstName(a, idx), r.Path
+	if r.IsAbsolute() {
+		name, p = name+"[1:]", p[1:]
+	}
+	if !strings.HasSuffix(full, p) {
+		return lit
+	}
+	if base := strings.TrimSuffix(full, p); base != "" {
+		return fmt.Sprintf("%q + %s", base, name)
+	}
+	return name
+}
+
+// routeConstName returns the name of the constant produced by GoRouteConstants for the route of a
+// at index i.
+func routeConstName(a *design.ActionDefinition, i int) string {
+	name := Goify(a.Name+strings.Title(a.Parent.Name), true) + "Route"
+	if i > 0 {
+		name = fmt.Sprintf("%s%d", name, i+1)
+	}
+	return name
+}
+
+const pathCtorTmpl = `// {{ .Name }} computes a request path to the {{ .Action }} action of {{ .Resource }}.
+func {{ .Name }}({{ .Params }}) string {
+{{ if .Args }}	return goa.RoutePath({{ .Path }}{{ range .Args }}, {{ . }}{{ end }})
+{{ else }}	return {{ .Path }}
+{{ end }}}
+`
@@ -0,0 +1,206 @@
+package codegen_test
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoPathConstructors", func() {
+	var paths []string
+	var code, consts string
+
+	BeforeEach(func() {
+		paths = []string{"/users/:id/posts/:postID"}
+	})
+
+	JustBeforeEach(func() {
+		res := &design.ResourceDefinition{Name: "post"}
+		action := &design.ActionDefinition{Name: "show", Parent: res}
+		for _, p := range paths {
+			action.Routes = append(action.Routes, &design.RouteDefinition{Verb: "GET", Path: p, Parent: action})
+		}
+		code = codegen.GoPathConstructors(action)
+		consts = codegen.GoRouteConstants(action)
+	})
+
+	It("generates the path constructor", func() {
+		Ω(code).Should(Equal(twoParamsPathCode))
+	})
+
+	It("does not declare the route constants", func() {
+		Ω(code).ShouldNot(ContainSubstring("const"))
+	})
+
+	It("generates code that escapes special characters in path segments", func() {
+		src := "package paths\n\nimport \"github.com/goadesign/goa\"\n\n" + consts + "\n" + code
+		out, err := goTest(map[string]string{"paths.go": src, "paths_test.go": pathEscapeTest})
+		Ω(err).ShouldNot(HaveOccurred(), out)
+	})
+
+	Context("with a literal percent character in the route", func() {
+		BeforeEach(func() {
+			paths = []string{"/rates/100%/:id"}
+		})
+
+		It("generates code that keeps the percent character", func() {
+			src := "package paths\n\nimport \"github.com/goadesign/goa\"\n\n" + consts + "\n" + code
+			out, err := goTest(map[string]string{"paths.go": src, "paths_test.go": percentPathTest})
+			Ω(err).ShouldNot(HaveOccurred(), out)
+		})
+	})
+
+	Context("with multiple routes and a catch-all parameter", func() {
+		BeforeEach(func() {
+			paths = []string{"/posts/:postID", "//files/*filepath"}
+		})
+
+		It("generates one constructor per route", func() {
+			Ω(code).Should(Equal(multiRoutesPathCode))
+		})
+
+		It("generates code that keeps the slashes of catch-all parameters", func() {
+			src := "package paths\n\nimport \"github.com/goadesign/goa\"\n\n" + consts + "\n" + code
+			out, err := goTest(map[string]string{"paths.go": src, "paths_test.go": catchAllEscapeTest})
+			Ω(err).ShouldNot(HaveOccurred(), out)
+		})
+	})
+})
+
+var _ = Describe("GoRouteConstants", func() {
+	var action *design.ActionDefinition
+	var code string
+
+	BeforeEach(func() {
+		res := &design.ResourceDefinition{Name: "user"}
+		action = &design.ActionDefinition{Name: "show", Parent: res}
+		for _, p := range []string{"/users/:id", "//accounts/:accountID/users/*path"} {
+			action.Routes = append(action.Routes, &design.RouteDefinition{Verb: "GET", Path: p, Parent: action})
+		}
+	})
+
+	JustBeforeEach(func() {
+		code = codegen.GoRouteConstants(action)
+	})
+
+	It("generates one constant per route holding the design route verbatim", func() {
+		Ω(code).Should(Equal(routeConstantsCode))
+		Ω(code).Should(ContainSubstring(fmt.Sprintf("ShowUserRoute = %q", action.Routes[0].Path)))
+		Ω(code).Should(ContainSubstring(fmt.Sprintf("ShowUserRoute2 = %q", action.Routes[1].Path)))
+	})
+
+	Context("with no route", func() {
+		BeforeEach(func() {
+			action.Routes = nil
+		})
+
+		It("generates nothing", func() {
+			Ω(code).Should(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("GoRoutePath", func() {
+	var res *design.ResourceDefinition
+	var action *design.ActionDefinition
+
+	BeforeEach(func() {
+		res = &design.ResourceDefinition{Name: "user", BasePath: "/users"}
+		action = &design.ActionDefinition{Name: "show", Parent: res}
+		for _, p := range []string{"/:id", "//accounts/:accountID/users/*path", "/:id/"} {
+			action.Routes = append(action.Routes, &design.RouteDefinition{Verb: "GET", Path: p, Parent: action})
+		}
+	})
+
+	It("prepends the resource base path to the route constant", func() {
+		Ω(codegen.GoRoutePath(action.Routes[0])).Should(Equal(`"/users" + ShowUserRoute`))
+	})
+
+	It("strips the leading slash of absolute routes", func() {
+		Ω(codegen.GoRoutePath(action.Routes[1])).Should(Equal("ShowUserRoute2[1:]"))
+	})
+
+	It("generates code that computes the full path of the routes", func() {
+		src := "package paths\n\n" + codegen.GoRouteConstants(action) + "\nvar fullPaths = []string{\n"
+		for _, r := range action.Routes {
+			src += "\t" + codegen.GoRoutePath(r) + ",\n"
+		}
+		src += "}\n"
+		test := "package paths\n\nimport \"testing\"\n\nfunc TestFullPaths(t *testing.T) {\n\texpected := []string{"
+		for _, r := range action.Routes {
+			test += fmt.Sprintf("%q, ", r.FullPath())
+		}
+		test += "}\n\tfor i, p := range fullPaths {\n\t\tif p != expected[i] {\n\t\t\tt.Errorf(\"got %s, expected %s\", p, expected[i])\n\t\t}\n\t}\n}\n"
+		out, err := goTest(map[string]string{"paths.go": src, "paths_test.go": test})
+		Ω(err).ShouldNot(HaveOccurred(), out)
+	})
+
+	Context("with a route that does not belong to an action", func() {
+		It("returns the full path literal", func() {
+			Ω(codegen.GoRoutePath(&design.RouteDefinition{Verb: "GET", Path: "/health"})).Should(Equal(`"/health"`))
+		})
+	})
+})
+
+const routeConstantsCode = `// Route path templates of the show action of user.
+const (
+	ShowUserRoute = "/users/:id"
+	ShowUserRoute2 = "//accounts/:accountID/users/*path"
+)
+`
+
+const twoParamsPathCode = `// ShowPostPath computes a request path to the show action of post.
+func ShowPostPath(id string, postID string) string {
+	return goa.RoutePath(ShowPostRoute, id, postID)
+}
+`
+
+const multiRoutesPathCode = `// ShowPostPath computes a request path to the show action of post.
+func ShowPostPath(postID string) string {
+	return goa.RoutePath(ShowPostRoute, postID)
+}
+
+// ShowPostPath2 computes a request path to the show action of post.
+func ShowPostPath2(filepath string) string {
+	return goa.RoutePath(ShowPostRoute2[1:], filepath)
+}
+`
+
+const pathEscapeTest = `package paths
+
+import "testing"
+
+func TestShowPostPath(t *testing.T) {
+	if p := ShowPostPath("a b/c", "100%+?"); p != "/users/a%20b%2Fc/posts/100%25%2B%3F" {
+		t.Errorf("unexpected path %s", p)
+	}
+}
+`
+
+const catchAllEscapeTest = `package paths
+
+import "testing"
+
+func TestShowPostPath(t *testing.T) {
+	if p := ShowPostPath("a/b"); p != "/posts/a%2Fb" {
+		t.Errorf("unexpected path %s", p)
+	}
+	if p := ShowPostPath2("docs/a b/100%.txt"); p != "/files/docs/a%20b/100%25.txt" {
+		t.Errorf("unexpected path %s", p)
+	}
+}
+`
+
+const percentPathTest = `package paths
+
+import "testing"
+
+func TestShowPostPath(t *testing.T) {
+	if p := ShowPostPath("a%b"); p != "/rates/100%/a%25b" {
+		t.Errorf("unexpected path %s", p)
+	}
+}
+`
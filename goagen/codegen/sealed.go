@@ -0,0 +1,141 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var (
+	sealedT      *template.Template
+	sealedSliceT *template.Template
+)
+
+func init() {
+	var err error
+	if sealedT, err = template.New("sealed").Parse(sealedTmpl); err != nil {
+		panic(err) // bug
+	}
+	if sealedSliceT, err = template.New("sealedSlice").Parse(sealedSliceTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoSealedInterface produces the Go code that defines a sealed interface named after name and the
+// structs generated for the given member types. The interface consists of a single unexported
+// marker method implemented by the member types only so that no type defined outside of the
+// generated package may implement it.
+// The function returns an error if no member is given, if a member is not an object or if two
+// members have the same Go name.
+func GoSealedInterface(name string, members ...*design.UserTypeDefinition) (string, error) {
+	if len(members) == 0 {
+		return "", fmt.Errorf("sealed interface %s must have at least one member", name)
+	}
+	iface := Goify(name, true)
+	seen := make(map[string]bool, len(members))
+	data := make([]map[string]interface{}, len(members))
+	names := make([]string, len(members))
+	for i, ut := range members {
+		if !ut.IsObject() {
+			return "", fmt.Errorf("member %s of sealed interface %s must be an object", ut.TypeName, iface)
+		}
+		n := Goify(ut.TypeName, true)
+		if seen[n] {
+			return "", fmt.Errorf("sealed interface %s: duplicate member %s", iface, n)
+		}
+		seen[n] = true
+		names[i] = n
+		data[i] = map[string]interface{}{
+			"Desc": GoTypeDesc(ut, true),
+			"Name": n,
+			"Def":  GoTypeDef(ut, 0, true, false),
+		}
+	}
+	return RunTemplate(sealedT, map[string]interface{}{
+		"Name":    iface,
+		"Marker":  "is" + iface,
+		"Members": data,
+		"List":    names,
+	}), nil
+}
+
+// GoSealedSlice produces the Go code that defines a slice of the sealed interface generated by
+// GoSealedInterface for the given name and members together with its UnmarshalJSON method. The
+// method decodes each element independently into the member identified by the value of the
+// element discriminator field. disc is the JSON name of the discriminator field, the values of
+// the DiscriminatorKey metadata of the members define their discriminators.
+// The function returns an error if no member is given or if a member does not define its
+// discriminator or if two members use the same discriminator.
+func GoSealedSlice(name, disc string, members ...*design.UserTypeDefinition) (string, error) {
+	iface := Goify(name, true)
+	if len(members) == 0 {
+		return "", fmt.Errorf("sealed interface %s must have at least one member", iface)
+	}
+	seen := make(map[string]bool, len(members))
+	cases := make([]map[string]interface{}, len(members))
+	for i, ut := range members {
+		val, err := discriminator(ut)
+		if err != nil {
+			return "", err
+		}
+		if seen[val] {
+			return "", fmt.Errorf("sealed interface %s: duplicate discriminator %q", iface, val)
+		}
+		seen[val] = true
+		cases[i] = map[string]interface{}{"Name": Goify(ut.TypeName, true), "Discriminator": val}
+	}
+	data := map[string]interface{}{
+		"Name":      iface,
+		"Disc":      disc,
+		"DiscField": Goify(disc, true),
+		"Cases":     cases,
+	}
+	return RunTemplate(sealedSliceT, data), nil
+}
+
+const sealedTmpl = `// {{ .Name }} is implemented by {{ range $i, $n := .List }}{{ if $i }}, {{ end }}{{ $n }}{{ end }} only.
+type {{ .Name }} interface {
+	{{ .Marker }}()
+}
+{{ range .Members }}
+// {{ .Desc }}
+type {{ .Name }} {{ .Def }}
+
+func (*{{ .Name }}) {{ $.Marker }}() {}
+{{ end }}`
+
+const sealedSliceTmpl = `// {{ .Name }}List is a list of {{ .Name }} values decoded according to their {{ printf "%q" .Disc }} field.
+type {{ .Name }}List []{{ .Name }}
+
+// UnmarshalJSON decodes each element of the list into the {{ .Name }} member identified by its
+// {{ printf "%q" .Disc }} field.
+func (l *{{ .Name }}List) UnmarshalJSON(data []byte) error {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return err
+	}
+	res := make({{ .Name }}List, len(raws))
+	for i, raw := range raws {
+		var disc struct {
+			{{ .DiscField }} string ` + "`" + `json:"{{ .Disc }}"` + "`" + `
+		}
+		if err := json.Unmarshal(raw, &disc); err != nil {
+			return err
+		}
+		var v {{ .Name }}
+		switch disc.{{ .DiscField }} {
+{{ range .Cases }}		case {{ printf "%q" .Discriminator }}:
+			v = &{{ .Name }}{}
+{{ end }}		default:
+			return fmt.Errorf("element %d: unknown {{ .Disc }} %q", i, disc.{{ .DiscField }})
+		}
+		if err := json.Unmarshal(raw, v); err != nil {
+			return err
+		}
+		res[i] = v
+	}
+	*l = res
+	return nil
+}
+`
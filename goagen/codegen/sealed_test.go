@@ -0,0 +1,172 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoSealedInterface", func() {
+	var members []*design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		members = []*design.UserTypeDefinition{
+			{
+				TypeName: "Circle",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"radius": &design.AttributeDefinition{Type: design.Number}},
+				},
+			},
+			{
+				TypeName: "Square",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"side": &design.AttributeDefinition{Type: design.Number}},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoSealedInterface("shape", members...)
+	})
+
+	It("defines the interface with an unexported marker method", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(ContainSubstring("type Shape interface {\n\tisShape()\n}\n"))
+	})
+
+	It("implements the marker method on each member", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(ContainSubstring("func (*Circle) isShape() {}\n"))
+		Ω(code).Should(ContainSubstring("func (*Square) isShape() {}\n"))
+		Ω(code).Should(Equal(sealedInterfaceCode))
+	})
+
+	Context("with a member that is not an object", func() {
+		BeforeEach(func() {
+			members = append(members, &design.UserTypeDefinition{
+				TypeName:            "Name",
+				AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+			})
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with no member", func() {
+		BeforeEach(func() {
+			members = nil
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const sealedInterfaceCode = `// Shape is implemented by Circle, Square only.
+type Shape interface {
+	isShape()
+}
+
+// Circle user type.
+type Circle struct {
+	Radius *float64 ` + "`" + `form:"radius,omitempty" json:"radius,omitempty" xml:"radius,omitempty"` + "`" + `
+}
+
+func (*Circle) isShape() {}
+
+// Square user type.
+type Square struct {
+	Side *float64 ` + "`" + `form:"side,omitempty" json:"side,omitempty" xml:"side,omitempty"` + "`" + `
+}
+
+func (*Square) isShape() {}
+`
+
+var _ = Describe("GoSealedSlice", func() {
+	var members []*design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		members = []*design.UserTypeDefinition{
+			{
+				TypeName: "Circle",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type:     design.Object{"radius": &design.AttributeDefinition{Type: design.Number}},
+					Metadata: dslengine.MetadataDefinition{codegen.DiscriminatorKey: {"circle"}},
+				},
+			},
+			{
+				TypeName: "Square",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type:     design.Object{"side": &design.AttributeDefinition{Type: design.Number}},
+					Metadata: dslengine.MetadataDefinition{codegen.DiscriminatorKey: {"square"}},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoSealedSlice("shape", "kind", members...)
+	})
+
+	It("decodes each element into the member identified by its discriminator", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(sealedSliceCode))
+	})
+
+	Context("with a member missing its discriminator", func() {
+		BeforeEach(func() {
+			members[1].Metadata = nil
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+const sealedSliceCode = `// ShapeList is a list of Shape values decoded according to their "kind" field.
+type ShapeList []Shape
+
+// UnmarshalJSON decodes each element of the list into the Shape member identified by its
+// "kind" field.
+func (l *ShapeList) UnmarshalJSON(data []byte) error {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return err
+	}
+	res := make(ShapeList, len(raws))
+	for i, raw := range raws {
+		var disc struct {
+			Kind string ` + "`" + `json:"kind"` + "`" + `
+		}
+		if err := json.Unmarshal(raw, &disc); err != nil {
+			return err
+		}
+		var v Shape
+		switch disc.Kind {
+		case "circle":
+			v = &Circle{}
+		case "square":
+			v = &Square{}
+		default:
+			return fmt.Errorf("element %d: unknown kind %q", i, disc.Kind)
+		}
+		if err := json.Unmarshal(raw, v); err != nil {
+			return err
+		}
+		res[i] = v
+	}
+	*l = res
+	return nil
+}
+`
@@ -0,0 +1,61 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// Signature returns a human readable summary of the given action suitable for logging, e.g.
+// "ShowUser(id string) -> User". The path parameters are listed first in the order they appear in
+// the action routes followed by the other parameters in alphabetical order and by the payload if
+// any. The parameter types are rendered with GoTypeRef. The response type is the name of the media
+// type of the first successful response that has one, the " -> " suffix is omitted if there is
+// none.
+func Signature(a *design.ActionDefinition) string {
+	params := a.AllParams().Type.ToObject()
+	var names []string
+	seen := make(map[string]bool)
+	for _, r := range a.Routes {
+		for _, p := range r.Params() {
+			if _, ok := params[p]; ok && !seen[p] {
+				seen[p] = true
+				names = append(names, p)
+			}
+		}
+	}
+	params.IterateAttributes(func(n string, _ *design.AttributeDefinition) error {
+		if !seen[n] {
+			names = append(names, n)
+		}
+		return nil
+	})
+	args := make([]string, len(names))
+	for i, n := range names {
+		att := params[n]
+		args[i] = Goify(n, false) + " " + GoTypeRef(att.Type, att.AllRequired(), 0, false)
+	}
+	if a.Payload != nil {
+		args = append(args, "payload "+GoTypeRef(a.Payload, a.Payload.AllRequired(), 0, false))
+	}
+	sig := fmt.Sprintf("%s(%s)", Goify(a.Name+strings.Title(a.Parent.Name), true), strings.Join(args, ", "))
+	if mt := successMediaType(a); mt != nil {
+		sig += " -> " + GoTypeName(mt, nil, 0, false)
+	}
+	return sig
+}
+
+// successMediaType returns the media type of the first successful response of the given action
+// in alphabetical order of response names that defines one, nil if there is none.
+func successMediaType(a *design.ActionDefinition) *design.MediaTypeDefinition {
+	var mt *design.MediaTypeDefinition
+	a.IterateResponses(func(r *design.ResponseDefinition) error {
+		if mt != nil || r.Status < 200 || r.Status >= 300 || r.MediaType == "" {
+			return nil
+		}
+		mt = design.Design.MediaTypeWithIdentifier(r.MediaType)
+		return nil
+	})
+	return mt
+}
@@ -0,0 +1,82 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Signature", func() {
+	var action *design.ActionDefinition
+	var oldDesign *design.APIDefinition
+
+	BeforeEach(func() {
+		user := &design.MediaTypeDefinition{
+			UserTypeDefinition: &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"id": &design.AttributeDefinition{Type: design.String}},
+				},
+				TypeName: "User",
+			},
+			Identifier: "application/vnd.user",
+		}
+		users := &design.MediaTypeDefinition{
+			UserTypeDefinition: &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: &design.Array{ElemType: &design.AttributeDefinition{Type: user}},
+				},
+				TypeName: "UserCollection",
+			},
+			Identifier: "application/vnd.user; type=collection",
+		}
+		oldDesign = design.Design
+		design.Design = &design.APIDefinition{
+			Name:       "test",
+			MediaTypes: map[string]*design.MediaTypeDefinition{user.Identifier: user, users.Identifier: users},
+		}
+		res := &design.ResourceDefinition{Name: "user"}
+		action = &design.ActionDefinition{
+			Name:   "list",
+			Parent: res,
+			Params: &design.AttributeDefinition{
+				Type: design.Object{
+					"orgID": &design.AttributeDefinition{Type: design.String},
+					"limit": &design.AttributeDefinition{Type: design.Integer},
+				},
+			},
+			Responses: map[string]*design.ResponseDefinition{
+				"NotFound": {Name: "NotFound", Status: 404},
+				"OK":       {Name: "OK", Status: 200, MediaType: users.Identifier},
+			},
+		}
+		action.Routes = []*design.RouteDefinition{{Verb: "GET", Path: "/orgs/:orgID/users", Parent: action}}
+	})
+
+	AfterEach(func() {
+		design.Design = oldDesign
+	})
+
+	It("lists the path parameters first and the collection response type", func() {
+		Ω(codegen.Signature(action)).Should(Equal("ListUser(orgID string, limit int) -> UserCollection"))
+	})
+
+	Context("with a payload and no response body", func() {
+		BeforeEach(func() {
+			action.Name = "update"
+			action.Params = nil
+			action.Payload = &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"name": &design.AttributeDefinition{Type: design.String}},
+				},
+				TypeName: "UpdateUserPayload",
+			}
+			action.Responses = map[string]*design.ResponseDefinition{"NoContent": {Name: "NoContent", Status: 204}}
+			action.Routes[0].Path = "/orgs/users"
+		})
+
+		It("lists the payload and omits the response type", func() {
+			Ω(codegen.Signature(action)).Should(Equal("UpdateUser(payload *UpdateUserPayload)"))
+		})
+	})
+})
@@ -0,0 +1,104 @@
+package codegen
+
+import (
+	"sort"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+// SSEKey is the name of the metadata used to flag actions that stream their responses as
+// server-sent events.
+const SSEKey = "stream:sse"
+
+var sseEventT *template.Template
+
+func init() {
+	var err error
+	if sseEventT, err = template.New("sseEvent").Parse(sseEventTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoSSEEvents produces the Go code that defines the event types of an action flagged with the
+// SSEKey metadata. There is one event type per distinct response body type, each event type comes
+// with a WriteFooEvent function that writes the JSON representation of an event as a server-sent
+// event "data" frame. JSON encoding escapes new lines so that each frame fits on a single line.
+// The function returns the empty string if the action is not flagged and an error if a response
+// media type cannot be projected.
+func GoSSEEvents(a *design.ActionDefinition) (string, error) {
+	if _, ok := a.Metadata[SSEKey]; !ok {
+		return "", nil
+	}
+	var statuses []int
+	byStatus := make(map[int]*design.ResponseDefinition, len(a.Responses))
+	for _, resp := range a.Responses {
+		statuses = append(statuses, resp.Status)
+		byStatus[resp.Status] = resp
+	}
+	sort.Ints(statuses)
+	var code string
+	seen := make(map[string]bool)
+	for _, status := range statuses {
+		ut, err := sseEventType(byStatus[status])
+		if err != nil {
+			return "", err
+		}
+		if ut == nil || seen[ut.TypeName] {
+			continue
+		}
+		seen[ut.TypeName] = true
+		data := map[string]interface{}{
+			"Name": Goify(ut.TypeName, true),
+			"Desc": GoTypeDesc(ut, true),
+			"Def":  GoTypeDef(ut, 0, true, false),
+		}
+		code += RunTemplate(sseEventT, data)
+	}
+	return code, nil
+}
+
+// sseEventType returns the user type that describes the body of the given response, nil if the
+// response has no body or if its body type is not a user type.
+func sseEventType(resp *design.ResponseDefinition) (*design.UserTypeDefinition, error) {
+	switch actual := resp.Type.(type) {
+	case *design.UserTypeDefinition:
+		return actual, nil
+	case *design.MediaTypeDefinition:
+		return projectedEventType(actual, resp.ViewName)
+	}
+	if resp.MediaType == "" || design.Design == nil {
+		return nil, nil
+	}
+	if mt := design.Design.MediaTypeWithIdentifier(resp.MediaType); mt != nil {
+		return projectedEventType(mt, resp.ViewName)
+	}
+	return nil, nil
+}
+
+// projectedEventType returns the user type of the media type rendered with the given view.
+func projectedEventType(mt *design.MediaTypeDefinition, view string) (*design.UserTypeDefinition, error) {
+	if view == "" {
+		view = design.DefaultView
+	}
+	p, _, err := mt.Project(view)
+	if err != nil {
+		return nil, err
+	}
+	return p.UserTypeDefinition, nil
+}
+
+const sseEventTmpl = `// {{ .Desc }}
+type {{ .Name }} {{ .Def }}
+
+// Write{{ .Name }}Event writes e to w as a server-sent event data frame.
+func Write{{ .Name }}Event(w io.Writer, e *{{ .Name }}) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", b)
+	return err
+}
+
+`
@@ -0,0 +1,70 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoSSEEvents", func() {
+	var metadata dslengine.MetadataDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		metadata = dslengine.MetadataDefinition{codegen.SSEKey: nil}
+	})
+
+	JustBeforeEach(func() {
+		tick := &design.UserTypeDefinition{
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{"price": &design.AttributeDefinition{Type: design.Number}},
+			},
+			TypeName: "Tick",
+		}
+		action := &design.ActionDefinition{
+			Name:     "watch",
+			Metadata: metadata,
+			Responses: map[string]*design.ResponseDefinition{
+				"OK":       {Name: "OK", Status: 200, Type: tick},
+				"NotFound": {Name: "NotFound", Status: 404},
+			},
+		}
+		code, err = codegen.GoSSEEvents(action)
+	})
+
+	It("generates the event types and writers", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(sseEventsCode))
+	})
+
+	Context("with an action that is not streaming", func() {
+		BeforeEach(func() {
+			metadata = nil
+		})
+
+		It("generates nothing", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(BeEmpty())
+		})
+	})
+})
+
+const sseEventsCode = "// Tick user type.\n" +
+	"type Tick struct {\n" +
+	"	Price *float64 `form:\"price,omitempty\" json:\"price,omitempty\" xml:\"price,omitempty\"`\n" +
+	"}\n" +
+	`
+// WriteTickEvent writes e to w as a server-sent event data frame.
+func WriteTickEvent(w io.Writer, e *Tick) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", b)
+	return err
+}
+
+`
@@ -3,7 +3,9 @@ package codegen
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"unicode"
@@ -16,6 +18,36 @@ import (
 // generating the code that transforms one data structure into another.
 const TransformMapKey = "transform:key"
 
+const (
+	// ChannelKey is the name of the metadata used to flag attributes whose struct fields are
+	// channels of the attribute type. Such fields are tagged with "-" so that the encoders
+	// skip them as they cannot be serialized.
+	ChannelKey = "struct:field:channel"
+
+	// ChannelDirKey is the name of the metadata used to restrict the direction of the channel
+	// fields generated for attributes flagged with ChannelKey. Valid values are "recv" and
+	// "send", channels are bidirectional by default.
+	ChannelDirKey = "struct:field:channel:dir"
+)
+
+// SQLNullKey is the name of the metadata used to flag optional primitive attributes whose public
+// struct fields use the goa Null types (e.g. goa.NullString) rather than pointers. The Null types
+// can be scanned from database rows and are encoded as null in JSON when not valid.
+const SQLNullKey = "struct:field:sqlnull"
+
+// DurationKey is the name of the metadata used to flag attributes whose struct fields hold
+// durations. The value of the metadata selects the JSON encoding of the field: "string" uses
+// goa.DurationString (e.g. "1m30s") and "seconds" uses goa.DurationSeconds (e.g. 90). The
+// encoding defaults to "string". The goa types are time.Duration values that carry their JSON
+// encoding, a time.Duration field would be encoded as a number of nanoseconds. The design
+// validation rejects other encodings as well as validations and default values on the attribute.
+const DurationKey = "struct:field:duration"
+
+// PointerDepthKey is the name of the metadata used to set the number of pointer indirections of the
+// struct field generated for an attribute, overriding the default pointer rules. Valid values are
+// "0", "1" and "2".
+const PointerDepthKey = "struct:field:pointer"
+
 var (
 	// TempCount holds the value appended to variable names to make them unique.
 	TempCount int
@@ -64,29 +96,35 @@ func init() {
 // private controls whether the field is a pointer or not. All fields in the struct are
 //   pointers for a private struct.
 func GoTypeDef(ds design.DataStructure, tabs int, jsonTags, private bool) string {
+	return goTypeDef(ds, tabs, jsonTags, private, false)
+}
+
+// goTypeDef implements GoTypeDef. optional controls whether optional primitive fields are
+// rendered using the generic goa.Optional type instead of pointers.
+func goTypeDef(ds design.DataStructure, tabs int, jsonTags, private, optional bool) string {
 	def := ds.Definition()
 	t := def.Type
 	switch actual := t.(type) {
 	case design.Primitive:
 		return GoTypeName(t, nil, tabs, private)
 	case *design.Array:
-		d := GoTypeDef(actual.ElemType, tabs, jsonTags, private)
+		d := goTypeDef(actual.ElemType, tabs, jsonTags, private, optional)
 		if actual.ElemType.Type.IsObject() {
 			d = "*" + d
 		}
 		return "[]" + d
 	case *design.Hash:
-		keyDef := GoTypeDef(actual.KeyType, tabs, jsonTags, private)
+		keyDef := goTypeDef(actual.KeyType, tabs, jsonTags, private, optional)
 		if actual.KeyType.Type.IsObject() {
 			keyDef = "*" + keyDef
 		}
-		elemDef := GoTypeDef(actual.ElemType, tabs, jsonTags, private)
+		elemDef := goTypeDef(actual.ElemType, tabs, jsonTags, private, optional)
 		if actual.ElemType.Type.IsObject() {
 			elemDef = "*" + elemDef
 		}
 		return fmt.Sprintf("map[%s]%s", keyDef, elemDef)
 	case design.Object:
-		return goTypeDefObject(actual, def, tabs, jsonTags, private)
+		return goTypeDefObject(actual, def, tabs, jsonTags, private, optional)
 	case *design.UserTypeDefinition:
 		return GoTypeName(actual, actual.AllRequired(), tabs, private)
 	case *design.MediaTypeDefinition:
@@ -97,7 +135,7 @@ func GoTypeDef(ds design.DataStructure, tabs int, jsonTags, private bool) string
 }
 
 // goTypeDefObject returns the Go code that defines a Go struct.
-func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs int, jsonTags, private bool) string {
+func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs int, jsonTags, private, optional bool) string {
 	var buffer bytes.Buffer
 	buffer.WriteString("struct {\n")
 	keys := make([]string, len(obj))
@@ -110,20 +148,39 @@ func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs in
 	for _, name := range keys {
 		WriteTabs(&buffer, tabs+1)
 		field := obj[name]
-		typedef := GoTypeDef(field, tabs+1, jsonTags, private)
-		if (field.Type.IsPrimitive() && private) || field.Type.IsObject() || def.IsPrimitivePointer(name) {
-			typedef = "*" + typedef
-		}
+		typedef := fieldTypeDef(def, name, tabs, jsonTags, private, optional)
+		_, isChan := field.Metadata[ChannelKey]
 		fname := GoifyAtt(field, name, true)
 		var tags string
-		if jsonTags {
+		if jsonTags && (isAtomic(def, name, private) || isChan) {
+			tags = skipTags
+		} else if jsonTags {
 			tags = attributeTags(def, field, name, private)
+			if strings.HasPrefix(typedef, "goa.Optional[") {
+				// encoding/json ignores omitempty for struct values, omitzero omits
+				// the absent values with Go 1.24 or later.
+				tags = strings.Replace(tags, "json:\""+name+",omitempty\"", "json:\""+name+",omitzero\"", 1)
+			}
 		}
 		desc := obj[name].Description
 		if desc != "" {
 			desc = strings.Replace(desc, "\n", "\n\t// ", -1)
 			desc = fmt.Sprintf("// %s\n\t", desc)
 		}
+		if isLazy(def, name, private) {
+			cache := GoifyAtt(field, name, false)
+			buffer.WriteString(fmt.Sprintf("%s%s %s\n", desc, cache, typedef))
+			WriteTabs(&buffer, tabs+1)
+			buffer.WriteString(cache + "Computed bool\n")
+			WriteTabs(&buffer, tabs+1)
+			buffer.WriteString(fmt.Sprintf("// %sProvider computes the value returned by %s.\n", fname, fname))
+			WriteTabs(&buffer, tabs+1)
+			if jsonTags {
+				tags = skipTags
+			}
+			buffer.WriteString(fmt.Sprintf("%sProvider func() (%s, error)%s\n", fname, typedef, tags))
+			continue
+		}
 		buffer.WriteString(fmt.Sprintf("%s%s %s%s\n", desc, fname, typedef, tags))
 	}
 	WriteTabs(&buffer, tabs)
@@ -131,6 +188,214 @@ func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs in
 	return buffer.String()
 }
 
+// skipTags holds the tags of the struct fields skipped by the form, JSON and XML encoders.
+const skipTags = " `form:\"-\" json:\"-\" xml:\"-\"`"
+
+// fieldTypeDef returns the Go type of the struct field generated for the child attribute of def
+// with the given name, e.g. "*string" or "goa.NullString". The parameters have the same meaning as
+// for goTypeDef.
+func fieldTypeDef(def *design.AttributeDefinition, name string, tabs int, jsonTags, private, optional bool) string {
+	field := def.Type.ToObject()[name]
+	typedef := goTypeDef(field, tabs+1, jsonTags, private, optional)
+	if dur := durationType(field); dur != "" {
+		typedef = dur
+	} else if id := idType(field); id != "" {
+		typedef = id
+	}
+	_, hasDepth := pointerDepth(field)
+	if _, isChan := field.Metadata[ChannelKey]; isChan {
+		if field.Type.IsObject() {
+			typedef = "*" + typedef
+		}
+		return channelType(field) + " " + typedef
+	}
+	if isAtomic(def, name, private) {
+		return atomicType(field)
+	}
+	if null := sqlNullType(def, name); null != "" && !private {
+		return null
+	}
+	if optional && !private && !hasDepth && def.IsPrimitivePointer(name) {
+		return "goa.Optional[" + typedef + "]"
+	}
+	return strings.Repeat("*", fieldPointers(def, name, private)) + typedef
+}
+
+// fieldPointers returns the number of pointer indirections of the struct field generated for the
+// child attribute of parent with the given name. PointerDepthKey only applies to the primitive
+// fields of public structs, the primitive fields of private structs are always pointers.
+func fieldPointers(parent *design.AttributeDefinition, name string, private bool) int {
+	att := parent.Type.ToObject()[name]
+	if isAtomic(parent, name, private) {
+		return 0
+	}
+	if depth, ok := pointerDepth(att); ok && !private && att.Type.IsPrimitive() {
+		return depth
+	}
+	if (att.Type.IsPrimitive() && private) || att.Type.IsObject() || parent.IsPrimitivePointer(name) {
+		return 1
+	}
+	return 0
+}
+
+// fieldAccess holds the Go expressions used by the generated code to access the value of a struct
+// field.
+type fieldAccess struct {
+	// guard evaluates to true if value may be evaluated, it is empty if value can always be
+	// evaluated.
+	guard string
+	// value evaluates to the field value, e.g. "**ut.Name".
+	value string
+	// set evaluates to true if the field is set, i.e. is not nil or holds a non zero value.
+	set string
+	// unset is the negation of set.
+	unset string
+}
+
+// newFieldAccess returns the expressions that access the field of the struct held by the variable
+// target generated for the child attribute of parent with the given name.
+func newFieldAccess(parent *design.AttributeDefinition, name, target string, private bool) *fieldAccess {
+	att := parent.Type.ToObject()[name]
+	field := target + "." + GoifyAtt(att, name, true)
+	if isLazy(parent, name, private) {
+		// Lazy fields are accessed through their cache, the value is set if it was computed
+		// or can be computed.
+		cache := target + "." + GoifyAtt(att, name, false)
+		computed := cache + "Computed"
+		provider := field + "Provider"
+		fa := &fieldAccess{
+			guard: computed,
+			value: cache,
+			set:   computed + " || " + provider + " != nil",
+			unset: "!" + computed + " && " + provider + " == nil",
+		}
+		pointers := 1
+		if att.Type.IsPrimitive() {
+			pointers = fieldPointers(parent, name, private)
+		} else if !att.Type.IsObject() {
+			pointers = 0
+		}
+		for i := 0; i < pointers; i++ {
+			fa.guard += " && " + strings.Repeat("*", i) + cache + " != nil"
+		}
+		if att.Type.IsPrimitive() {
+			fa.value = strings.Repeat("*", pointers) + cache
+		}
+		return fa
+	}
+	if isAtomic(parent, name, private) {
+		load := field + ".Load()"
+		if att.Type.Kind() == design.BooleanKind {
+			return &fieldAccess{value: load, set: load, unset: "!" + load}
+		}
+		return &fieldAccess{value: load, set: load + " != 0", unset: load + " == 0"}
+	}
+	if !att.Type.IsPrimitive() {
+		fa := &fieldAccess{value: field, set: field + " != nil", unset: field + " == nil"}
+		if private || !(parent.IsRequired(name) || parent.HasDefaultValue(name) || parent.IsNonZero(name)) {
+			fa.guard = fa.set
+		}
+		return fa
+	}
+	if null := sqlNullType(parent, name); null != "" && !private {
+		valid := field + ".Valid"
+		return &fieldAccess{
+			guard: valid,
+			value: field + "." + strings.TrimPrefix(null, "goa.Null"),
+			set:   valid,
+			unset: "!" + valid,
+		}
+	}
+	pointers := fieldPointers(parent, name, private)
+	if pointers == 0 {
+		zero := ZeroValue(att.Type)
+		if strings.HasSuffix(zero, "}") {
+			// Composite literals must be parenthesized in if statements.
+			zero = "(" + zero + ")"
+		}
+		if att.Type.Kind() == design.DateTimeKind {
+			return &fieldAccess{value: field, set: "!" + field + ".IsZero()", unset: field + ".IsZero()"}
+		}
+		return &fieldAccess{value: field, set: field + " != " + zero, unset: field + " == " + zero}
+	}
+	set := make([]string, pointers)
+	unset := make([]string, pointers)
+	for i := range set {
+		set[i] = strings.Repeat("*", i) + field + " != nil"
+		unset[i] = strings.Repeat("*", i) + field + " == nil"
+	}
+	fa := &fieldAccess{
+		guard: strings.Join(set, " && "),
+		value: strings.Repeat("*", pointers) + field,
+		unset: strings.Join(unset, " || "),
+	}
+	fa.set = fa.guard
+	return fa
+}
+
+// sqlNullType returns the goa Null type used by the field generated for the child attribute of
+// parent with the given name if the attribute is optional and defines the SQLNullKey metadata, the
+// empty string otherwise.
+func sqlNullType(parent *design.AttributeDefinition, name string) string {
+	att := parent.Type.ToObject()[name]
+	if _, ok := att.Metadata[SQLNullKey]; !ok || !parent.IsPrimitivePointer(name) {
+		return ""
+	}
+	switch att.Type.Kind() {
+	case design.StringKind:
+		return "goa.NullString"
+	case design.IntegerKind:
+		return "goa.NullInt64"
+	case design.NumberKind:
+		return "goa.NullFloat64"
+	case design.BooleanKind:
+		return "goa.NullBool"
+	default:
+		return ""
+	}
+}
+
+// durationType returns the goa Duration type used by the field generated for the given attribute
+// if it defines the DurationKey metadata, the empty string otherwise.
+func durationType(att *design.AttributeDefinition) string {
+	mode, ok := att.Metadata[DurationKey]
+	if !ok {
+		return ""
+	}
+	if len(mode) > 0 && mode[0] == "seconds" {
+		return "goa.DurationSeconds"
+	}
+	return "goa.DurationString"
+}
+
+// pointerDepth returns the number of pointer indirections set with PointerDepthKey on the given
+// attribute. The second value is false if the metadata is absent or invalid.
+func pointerDepth(att *design.AttributeDefinition) (int, bool) {
+	vals, ok := att.Metadata[PointerDepthKey]
+	if !ok || len(vals) == 0 {
+		return 0, false
+	}
+	depth, err := strconv.Atoi(vals[0])
+	if err != nil || depth < 0 || depth > 2 {
+		return 0, false
+	}
+	return depth, true
+}
+
+// channelType returns the channel type keyword(s) of the field generated for the given attribute
+// flagged with ChannelKey.
+func channelType(att *design.AttributeDefinition) string {
+	if dir, ok := att.Metadata[ChannelDirKey]; ok && len(dir) > 0 {
+		switch dir[0] {
+		case "recv":
+			return "<-chan"
+		case "send":
+			return "chan<-"
+		}
+	}
+	return "chan"
+}
+
 // attributeTags computes the struct field tags.
 func attributeTags(parent, att *design.AttributeDefinition, name string, private bool) string {
 	var elems []string
@@ -234,6 +499,8 @@ func GoNativeType(t design.DataType) string {
 			return "time.Time"
 		case design.UUIDKind:
 			return "uuid.UUID"
+		case design.LanguageKind:
+			return "language.Tag"
 		case design.AnyKind:
 			return "interface{}"
 		default:
@@ -254,6 +521,79 @@ func GoNativeType(t design.DataType) string {
 	}
 }
 
+// ZeroValue returns the Go expression of the zero value of the Go type generated for t.
+// Objects and user types are referred to via pointers so that their zero value is nil, the same
+// goes for arrays, hashes and the Any type.
+func ZeroValue(t design.DataType) string {
+	switch actual := t.(type) {
+	case design.Primitive:
+		switch actual.Kind() {
+		case design.BooleanKind:
+			return "false"
+		case design.IntegerKind, design.NumberKind:
+			return "0"
+		case design.StringKind:
+			return `""`
+		case design.DateTimeKind, design.UUIDKind, design.LanguageKind:
+			return GoNativeType(actual) + "{}"
+		case design.AnyKind:
+			return "nil"
+		default:
+			panic(fmt.Sprintf("goa bug: unknown primitive type %#v", actual))
+		}
+	case *design.Array, *design.Hash, design.Object:
+		return "nil"
+	case *design.MediaTypeDefinition:
+		return ZeroValue(actual.UserTypeDefinition)
+	case *design.UserTypeDefinition:
+		if actual.IsObject() {
+			return "nil"
+		}
+		return ZeroValue(actual.Type)
+	default:
+		panic(fmt.Sprintf("goa bug: unknown type %#v", actual))
+	}
+}
+
+// ReflectKind returns the reflect.Kind of the Go type generated for t. Objects map to
+// reflect.Struct, arrays to reflect.Slice and hashes to reflect.Map. User types map to the kind of
+// their underlying type.
+func ReflectKind(t design.DataType) reflect.Kind {
+	switch actual := t.(type) {
+	case design.Primitive:
+		switch actual.Kind() {
+		case design.BooleanKind:
+			return reflect.Bool
+		case design.IntegerKind:
+			return reflect.Int
+		case design.NumberKind:
+			return reflect.Float64
+		case design.StringKind:
+			return reflect.String
+		case design.DateTimeKind, design.LanguageKind:
+			return reflect.Struct
+		case design.UUIDKind:
+			return reflect.Array
+		case design.AnyKind:
+			return reflect.Interface
+		default:
+			panic(fmt.Sprintf("goa bug: unknown primitive type %#v", actual))
+		}
+	case *design.Array:
+		return reflect.Slice
+	case *design.Hash:
+		return reflect.Map
+	case design.Object:
+		return reflect.Struct
+	case *design.MediaTypeDefinition:
+		return ReflectKind(actual.UserTypeDefinition)
+	case *design.UserTypeDefinition:
+		return ReflectKind(actual.Type)
+	default:
+		panic(fmt.Sprintf("goa bug: unknown type %#v", actual))
+	}
+}
+
 // GoTypeDesc returns the description of a type.  If no description is defined
 // for the type, one will be generated.
 func GoTypeDesc(t design.DataType, upper bool) string {
@@ -421,6 +761,20 @@ func Goify(str string, firstUpper bool) string {
 	return fixReserved(string(runes))
 }
 
+// UniqueGoName returns a Go identifier made out of base with Goify that is not already in taken.
+// It appends a numeric suffix to the identifier until it is not in taken, records the result in
+// taken and returns it. This makes it possible to generate package level symbols that collide
+// neither with Go reserved keywords nor with symbols generated previously.
+func UniqueGoName(base string, firstUpper bool, taken map[string]bool) string {
+	name := Goify(base, firstUpper)
+	unique := name
+	for i := 1; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	taken[unique] = true
+	return unique
+}
+
 // Reserved golang keywords and package names
 var Reserved = map[string]bool{
 	"byte":       true,
@@ -2,6 +2,7 @@ package codegen_test
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	. "github.com/goadesign/goa/design"
@@ -173,6 +174,24 @@ var _ = Describe("code generation", func() {
 
 		})
 
+		Context("given a string with underscore separated digits", func() {
+			It("collapses the digits into the identifier", func() {
+				Ω(codegen.Goify("field_2", true)).Should(Equal("Field2"))
+				Ω(codegen.Goify("field_2", false)).Should(Equal("field2"))
+				Ω(codegen.Goify("v_1", true)).Should(Equal("V1"))
+			})
+
+			It("capitalizes the words that follow the digits", func() {
+				Ω(codegen.Goify("v_1_beta", true)).Should(Equal("V1Beta"))
+				Ω(codegen.Goify("v_1_beta", false)).Should(Equal("v1Beta"))
+			})
+
+			It("keeps the letters that are part of the digits word as is", func() {
+				Ω(codegen.Goify("a_1b", true)).Should(Equal("A1b"))
+				Ω(codegen.Goify("a_1b", false)).Should(Equal("a1b"))
+			})
+		})
+
 	})
 
 	Describe("GoTypeDef", func() {
@@ -256,6 +275,115 @@ var _ = Describe("code generation", func() {
 				})
 			})
 
+			Context("of language type", func() {
+				BeforeEach(func() {
+					object = Object{
+						"locale": &AttributeDefinition{Type: Language},
+					}
+					required = nil
+				})
+
+				It("produces the struct go code", func() {
+					Ω(st).Should(Equal("struct {\n\tLocale *language.Tag `form:\"locale,omitempty\" json:\"locale,omitempty\" xml:\"locale,omitempty\"`\n}"))
+				})
+
+				It("produces code that round-trips language tags through the struct tags", func() {
+					src := "package prefs\n\nimport \"golang.org/x/text/language\"\n\ntype Prefs " + st + "\n"
+					out, err := goTest(map[string]string{"prefs.go": src, "prefs_test.go": languageRoundTripTest})
+					Ω(err).ShouldNot(HaveOccurred(), out)
+				})
+			})
+
+			Context("of channel fields", func() {
+				BeforeEach(func() {
+					object = Object{
+						"events": &AttributeDefinition{
+							Type:     String,
+							Metadata: dslengine.MetadataDefinition{"struct:field:channel": nil},
+						},
+						"results": &AttributeDefinition{
+							Type: Integer,
+							Metadata: dslengine.MetadataDefinition{
+								"struct:field:channel":     nil,
+								"struct:field:channel:dir": []string{"recv"},
+							},
+						},
+					}
+					required = nil
+				})
+
+				It("produces channel fields skipped by the encoders", func() {
+					Ω(st).Should(Equal("struct {\n\tEvents chan string `form:\"-\" json:\"-\" xml:\"-\"`\n\tResults <-chan int `form:\"-\" json:\"-\" xml:\"-\"`\n}"))
+				})
+			})
+
+			Context("of duration fields", func() {
+				BeforeEach(func() {
+					object = Object{
+						"timeout": &AttributeDefinition{Type: String, Metadata: dslengine.MetadataDefinition{"struct:field:duration": nil}},
+						"ttl":     &AttributeDefinition{Type: Number, Metadata: dslengine.MetadataDefinition{"struct:field:duration": {"seconds"}}},
+					}
+					required = &dslengine.ValidationDefinition{Required: []string{"ttl"}}
+				})
+
+				It("produces the goa duration type fields", func() {
+					expected := "struct {\n" +
+						"	Timeout *goa.DurationString `form:\"timeout,omitempty\" json:\"timeout,omitempty\" xml:\"timeout,omitempty\"`\n" +
+						"	TTL goa.DurationSeconds `form:\"ttl\" json:\"ttl\" xml:\"ttl\"`\n" +
+						"}"
+					Ω(st).Should(Equal(expected))
+				})
+			})
+
+			Context("of primitive fields with an explicit pointer depth", func() {
+				BeforeEach(func() {
+					object = Object{
+						"a": &AttributeDefinition{Type: String, Metadata: dslengine.MetadataDefinition{"struct:field:pointer": {"0"}}},
+						"b": &AttributeDefinition{Type: String, Metadata: dslengine.MetadataDefinition{"struct:field:pointer": {"1"}}},
+						"c": &AttributeDefinition{Type: String, Metadata: dslengine.MetadataDefinition{"struct:field:pointer": {"2"}}},
+						"d": &AttributeDefinition{Type: String, Metadata: dslengine.MetadataDefinition{"struct:field:pointer": {"3"}}},
+					}
+					required = &dslengine.ValidationDefinition{Required: []string{"b"}}
+				})
+
+				It("produces the requested number of pointer indirections", func() {
+					expected := "struct {\n" +
+						"	A string `form:\"a,omitempty\" json:\"a,omitempty\" xml:\"a,omitempty\"`\n" +
+						"	B *string `form:\"b\" json:\"b\" xml:\"b\"`\n" +
+						"	C **string `form:\"c,omitempty\" json:\"c,omitempty\" xml:\"c,omitempty\"`\n" +
+						"	D *string `form:\"d,omitempty\" json:\"d,omitempty\" xml:\"d,omitempty\"`\n" +
+						"}"
+					Ω(st).Should(Equal(expected))
+				})
+			})
+
+			Context("of optional primitive fields using the sql null types", func() {
+				BeforeEach(func() {
+					null := dslengine.MetadataDefinition{"struct:field:sqlnull": nil}
+					object = Object{
+						"active": &AttributeDefinition{Type: Boolean, Metadata: null},
+						"count":  &AttributeDefinition{Type: Integer, Metadata: null},
+						"id":     &AttributeDefinition{Type: Integer, Metadata: null},
+						"name":   &AttributeDefinition{Type: String, Metadata: null},
+						"score":  &AttributeDefinition{Type: Number, Metadata: null},
+						"since":  &AttributeDefinition{Type: DateTime, Metadata: null},
+					}
+					required = &dslengine.ValidationDefinition{Required: []string{"id"}}
+				})
+
+				It("produces the goa null type fields", func() {
+					expected := "struct {\n" +
+						"	Active goa.NullBool `form:\"active,omitempty\" json:\"active,omitempty\" xml:\"active,omitempty\"`\n" +
+						"	Count goa.NullInt64 `form:\"count,omitempty\" json:\"count,omitempty\" xml:\"count,omitempty\"`\n" +
+						"	ID int `form:\"id\" json:\"id\" xml:\"id\"`\n" +
+						"	Name goa.NullString `form:\"name,omitempty\" json:\"name,omitempty\" xml:\"name,omitempty\"`\n" +
+						"	Score goa.NullFloat64 `form:\"score,omitempty\" json:\"score,omitempty\" xml:\"score,omitempty\"`\n" +
+						"	Since *time.Time `form:\"since,omitempty\" json:\"since,omitempty\" xml:\"since,omitempty\"`\n" +
+						"}"
+					Ω(st).Should(Equal(expected))
+				})
+			})
+
 			Context("of hash of primitive types", func() {
 				BeforeEach(func() {
 					elemType := &AttributeDefinition{Type: Integer}
@@ -272,6 +400,33 @@ var _ = Describe("code generation", func() {
 				})
 			})
 
+			Context("of nested hashes", func() {
+				BeforeEach(func() {
+					str := &AttributeDefinition{Type: String}
+					inner := &Hash{KeyType: str, ElemType: &AttributeDefinition{Type: Integer}}
+					middle := &Hash{KeyType: str, ElemType: &AttributeDefinition{Type: inner}}
+					outer := &Hash{KeyType: str, ElemType: &AttributeDefinition{Type: middle}}
+					object = Object{
+						"three": &AttributeDefinition{Type: outer},
+						"two":   &AttributeDefinition{Type: middle},
+					}
+					required = nil
+				})
+
+				It("produces the nested map go code", func() {
+					Ω(st).Should(Equal("struct {\n" +
+						"\tThree map[string]map[string]map[string]int `form:\"three,omitempty\" json:\"three,omitempty\" xml:\"three,omitempty\"`\n" +
+						"\tTwo map[string]map[string]int `form:\"two,omitempty\" json:\"two,omitempty\" xml:\"two,omitempty\"`\n" +
+						"}"))
+				})
+
+				It("produces the nested map go type references", func() {
+					Ω(codegen.GoTypeRef(object["two"].Type, nil, 0, false)).Should(Equal("map[string]map[string]int"))
+					Ω(codegen.GoTypeRef(object["three"].Type, nil, 0, false)).Should(Equal("map[string]map[string]map[string]int"))
+					Ω(codegen.GoNativeType(object["three"].Type)).Should(Equal("map[string]map[string]map[string]int"))
+				})
+			})
+
 			Context("of array of primitive types", func() {
 				BeforeEach(func() {
 					elemType := &AttributeDefinition{Type: Integer}
@@ -628,3 +783,117 @@ var _ = Describe("GoTypeDesc", func() {
 		})
 	})
 })
+
+var _ = Describe("ZeroValue", func() {
+	It("handles all the primitive kinds", func() {
+		Ω(codegen.ZeroValue(Boolean)).Should(Equal("false"))
+		Ω(codegen.ZeroValue(Integer)).Should(Equal("0"))
+		Ω(codegen.ZeroValue(Number)).Should(Equal("0"))
+		Ω(codegen.ZeroValue(String)).Should(Equal(`""`))
+		Ω(codegen.ZeroValue(DateTime)).Should(Equal("time.Time{}"))
+		Ω(codegen.ZeroValue(UUID)).Should(Equal("uuid.UUID{}"))
+		Ω(codegen.ZeroValue(Language)).Should(Equal("language.Tag{}"))
+		Ω(codegen.ZeroValue(Any)).Should(Equal("nil"))
+	})
+
+	It("returns nil for composite types", func() {
+		elem := &AttributeDefinition{Type: String}
+		Ω(codegen.ZeroValue(&Array{ElemType: elem})).Should(Equal("nil"))
+		Ω(codegen.ZeroValue(&Hash{KeyType: elem, ElemType: elem})).Should(Equal("nil"))
+		Ω(codegen.ZeroValue(Object{})).Should(Equal("nil"))
+	})
+
+	It("handles user types", func() {
+		obj := &UserTypeDefinition{AttributeDefinition: &AttributeDefinition{Type: Object{}}, TypeName: "Obj"}
+		str := &UserTypeDefinition{AttributeDefinition: &AttributeDefinition{Type: String}, TypeName: "Str"}
+		mt := &MediaTypeDefinition{UserTypeDefinition: obj}
+		Ω(codegen.ZeroValue(obj)).Should(Equal("nil"))
+		Ω(codegen.ZeroValue(str)).Should(Equal(`""`))
+		Ω(codegen.ZeroValue(mt)).Should(Equal("nil"))
+	})
+})
+
+var _ = Describe("ReflectKind", func() {
+	It("handles all the primitive kinds", func() {
+		Ω(codegen.ReflectKind(Boolean)).Should(Equal(reflect.Bool))
+		Ω(codegen.ReflectKind(Integer)).Should(Equal(reflect.Int))
+		Ω(codegen.ReflectKind(Number)).Should(Equal(reflect.Float64))
+		Ω(codegen.ReflectKind(String)).Should(Equal(reflect.String))
+		Ω(codegen.ReflectKind(DateTime)).Should(Equal(reflect.Struct))
+		Ω(codegen.ReflectKind(UUID)).Should(Equal(reflect.Array))
+		Ω(codegen.ReflectKind(Language)).Should(Equal(reflect.Struct))
+		Ω(codegen.ReflectKind(Any)).Should(Equal(reflect.Interface))
+	})
+
+	It("handles composite types", func() {
+		elem := &AttributeDefinition{Type: String}
+		Ω(codegen.ReflectKind(&Array{ElemType: elem})).Should(Equal(reflect.Slice))
+		Ω(codegen.ReflectKind(&Hash{KeyType: elem, ElemType: elem})).Should(Equal(reflect.Map))
+		Ω(codegen.ReflectKind(Object{})).Should(Equal(reflect.Struct))
+	})
+
+	It("handles user types", func() {
+		obj := &UserTypeDefinition{AttributeDefinition: &AttributeDefinition{Type: Object{}}, TypeName: "Obj"}
+		str := &UserTypeDefinition{AttributeDefinition: &AttributeDefinition{Type: String}, TypeName: "Str"}
+		mt := &MediaTypeDefinition{UserTypeDefinition: obj}
+		Ω(codegen.ReflectKind(obj)).Should(Equal(reflect.Struct))
+		Ω(codegen.ReflectKind(str)).Should(Equal(reflect.String))
+		Ω(codegen.ReflectKind(mt)).Should(Equal(reflect.Struct))
+	})
+})
+
+var _ = Describe("UniqueGoName", func() {
+	var taken map[string]bool
+
+	BeforeEach(func() {
+		taken = map[string]bool{"UserID": true}
+	})
+
+	It("appends numeric suffixes to successive colliding names", func() {
+		Ω(codegen.UniqueGoName("user_id", true, taken)).Should(Equal("UserID1"))
+		Ω(codegen.UniqueGoName("user_id", true, taken)).Should(Equal("UserID2"))
+		Ω(codegen.UniqueGoName("user", true, taken)).Should(Equal("User"))
+		Ω(codegen.UniqueGoName("user", true, taken)).Should(Equal("User1"))
+		Ω(taken).Should(HaveLen(5))
+	})
+
+	It("avoids reserved keywords", func() {
+		Ω(codegen.UniqueGoName("type", false, taken)).Should(Equal("type_"))
+		Ω(codegen.UniqueGoName("type", false, taken)).Should(Equal("type_1"))
+	})
+})
+
+const languageRoundTripTest = `package prefs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestLanguageRoundTrip(t *testing.T) {
+	tag := language.MustParse("zh-Hant-TW")
+	b, err := json.Marshal(Prefs{Locale: &tag})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != ` + "`" + `{"locale":"zh-Hant-TW"}` + "`" + ` {
+		t.Errorf("unexpected encoding %s", b)
+	}
+	var p Prefs
+	if err := json.Unmarshal(b, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Locale == nil || *p.Locale != tag {
+		t.Errorf("unexpected locale %v", p.Locale)
+	}
+}
+
+func TestMalformedLanguage(t *testing.T) {
+	var p Prefs
+	if err := json.Unmarshal([]byte(` + "`" + `{"locale":"e"}` + "`" + `), &p); err == nil {
+		t.Errorf("malformed tag accepted: %v", p.Locale)
+	}
+}
+`
@@ -0,0 +1,82 @@
+package codegen
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+)
+
+var uniqueT *template.Template
+
+func init() {
+	var err error
+	if uniqueT, err = template.New("unique").Parse(uniqueTmpl); err != nil {
+		panic(err) // bug
+	}
+}
+
+// GoUniqueUnmarshaler produces the Go code of the UnmarshalJSON method of the struct generated for
+// the given user type that removes the duplicate elements of the arrays of primitive values whose
+// attributes define the uniqueItems validation after decoding. The first occurrence of each
+// element is kept so that the order of the decoded elements is preserved. Values built in code
+// are not deduplicated: the Validate method rejects their duplicate elements.
+// The function returns the empty string if no attribute defines the validation and an error if an
+// array with unique items has elements that are not primitive values other than Any or if
+// another generator produces the JSON methods of ut, see jsonMethodsConflict.
+func GoUniqueUnmarshaler(ut *design.UserTypeDefinition) (string, error) {
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return "", fmt.Errorf("type %s must be an object", ut.TypeName)
+	}
+	var fields []map[string]interface{}
+	err := obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		arr := att.Type.ToArray()
+		if arr == nil || att.Validation == nil || !att.Validation.UniqueItems {
+			return nil
+		}
+		if !arr.ElemType.Type.IsPrimitive() || arr.ElemType.Type.Kind() == design.AnyKind {
+			return fmt.Errorf("%s.%s: cannot deduplicate elements of type %s", ut.TypeName, n, arr.ElemType.Type.Name())
+		}
+		fields = append(fields, map[string]interface{}{
+			"Field":    "a." + GoifyAtt(att, n, true),
+			"ElemType": GoNativeType(arr.ElemType.Type),
+		})
+		return nil
+	})
+	if err != nil || len(fields) == 0 {
+		return "", err
+	}
+	if err := jsonMethodsConflict(ut, "uniqueItems"); err != nil {
+		return "", err
+	}
+	data := map[string]interface{}{
+		"Name":   Goify(ut.TypeName, true),
+		"Fields": fields,
+	}
+	return RunTemplate(uniqueT, data), nil
+}
+
+const uniqueTmpl = `// UnmarshalJSON decodes the {{ .Name }} and removes the duplicate elements of its arrays with
+// unique items, the first occurrence of each element is kept.
+func (ut *{{ .Name }}) UnmarshalJSON(data []byte) error {
+	type alias {{ .Name }}
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+{{ range .Fields }}	if {{ .Field }} != nil {
+		seen := make(map[{{ .ElemType }}]bool, len({{ .Field }}))
+		res := {{ .Field }}[:0]
+		for _, e := range {{ .Field }} {
+			if !seen[e] {
+				seen[e] = true
+				res = append(res, e)
+			}
+		}
+		{{ .Field }} = res
+	}
+{{ end }}	*ut = {{ .Name }}(a)
+	return nil
+}
+`
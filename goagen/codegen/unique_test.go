@@ -0,0 +1,157 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoUniqueUnmarshaler", func() {
+	var ut *design.UserTypeDefinition
+	var code string
+	var err error
+
+	BeforeEach(func() {
+		unique := &dslengine.ValidationDefinition{UniqueItems: true}
+		ut = &design.UserTypeDefinition{
+			TypeName: "Post",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"tags":    &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}, Validation: unique},
+					"ratings": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}}, Validation: unique},
+					"authors": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		code, err = codegen.GoUniqueUnmarshaler(ut)
+	})
+
+	It("removes the duplicate elements after decoding", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(code).Should(Equal(uniqueCode))
+	})
+
+	It("generates code that compiles, deduplicates decoded values and validates built values", func() {
+		src := "package post\n\nimport (\n\t\"encoding/json\"\n\n\t\"github.com/goadesign/goa\"\n)\n\n" +
+			"type Post " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" +
+			"func (ut *Post) Validate() (err error) {\n" +
+			codegen.NewValidator().Code(ut.AttributeDefinition, false, false, false, "ut", "response", 1, false) +
+			"\n\treturn\n}\n\n" + code
+		out, err := goTest(map[string]string{"post.go": src, "post_test.go": uniqueUsageTest})
+		Ω(err).ShouldNot(HaveOccurred(), out)
+	})
+
+	Context("with unique items that are not primitive values", func() {
+		BeforeEach(func() {
+			ut.Type.ToObject()["sections"] = &design.AttributeDefinition{
+				Type:       &design.Array{ElemType: &design.AttributeDefinition{Type: design.Object{}}},
+				Validation: &dslengine.ValidationDefinition{UniqueItems: true},
+			}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an interned attribute", func() {
+		BeforeEach(func() {
+			ut.Type.ToObject()["tenant"] = &design.AttributeDefinition{
+				Type:     design.String,
+				Metadata: dslengine.MetadataDefinition{codegen.InternKey: nil},
+			}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring(codegen.InternKey))
+		})
+	})
+
+	Context("with no unique items", func() {
+		BeforeEach(func() {
+			ut.Type = design.Object{"authors": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}}}
+		})
+
+		It("returns the empty string", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(code).Should(BeEmpty())
+		})
+	})
+})
+
+const uniqueCode = `// UnmarshalJSON decodes the Post and removes the duplicate elements of its arrays with
+// unique items, the first occurrence of each element is kept.
+func (ut *Post) UnmarshalJSON(data []byte) error {
+	type alias Post
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	if a.Ratings != nil {
+		seen := make(map[int]bool, len(a.Ratings))
+		res := a.Ratings[:0]
+		for _, e := range a.Ratings {
+			if !seen[e] {
+				seen[e] = true
+				res = append(res, e)
+			}
+		}
+		a.Ratings = res
+	}
+	if a.Tags != nil {
+		seen := make(map[string]bool, len(a.Tags))
+		res := a.Tags[:0]
+		for _, e := range a.Tags {
+			if !seen[e] {
+				seen[e] = true
+				res = append(res, e)
+			}
+		}
+		a.Tags = res
+	}
+	*ut = Post(a)
+	return nil
+}
+`
+
+const uniqueUsageTest = `package post
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDecodedDuplicates(t *testing.T) {
+	var p Post
+	doc := ` + "`" + `{"tags":["a","b","a"],"ratings":[3,1,3,3],"authors":["x","x"]}` + "`" + `
+	if err := json.Unmarshal([]byte(doc), &p); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p.Tags, []string{"a", "b"}) {
+		t.Errorf("unexpected tags %v", p.Tags)
+	}
+	if !reflect.DeepEqual(p.Ratings, []int{3, 1}) {
+		t.Errorf("unexpected ratings %v", p.Ratings)
+	}
+	if !reflect.DeepEqual(p.Authors, []string{"x", "x"}) {
+		t.Errorf("unexpected authors %v", p.Authors)
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("decoded value invalid: %s", err)
+	}
+}
+
+func TestBuiltDuplicates(t *testing.T) {
+	p := Post{Tags: []string{"a", "a"}}
+	if err := p.Validate(); err == nil {
+		t.Error("duplicate tags not rejected")
+	}
+}
+`
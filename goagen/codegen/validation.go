@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -17,6 +18,7 @@ var (
 	patternValT  *template.Template
 	minMaxValT   *template.Template
 	lengthValT   *template.Template
+	uniqueValT   *template.Template
 	requiredValT *template.Template
 )
 
@@ -30,6 +32,8 @@ func init() {
 		"constant": constant,
 		"goifyAtt": GoifyAtt,
 		"add":      Add,
+		"tempvar":  Tempvar,
+		"gonative": GoNativeType,
 	}
 	if enumValT, err = template.New("enum").Funcs(fm).Parse(enumValTmpl); err != nil {
 		panic(err)
@@ -46,22 +50,50 @@ func init() {
 	if lengthValT, err = template.New("length").Funcs(fm).Parse(lengthValTmpl); err != nil {
 		panic(err)
 	}
+	if uniqueValT, err = template.New("unique").Funcs(fm).Parse(uniqueValTmpl); err != nil {
+		panic(err)
+	}
 	if requiredValT, err = template.New("required").Funcs(fm).Parse(requiredValTmpl); err != nil {
 		panic(err)
 	}
 }
 
+const (
+	// ExclusiveKeyPrefix is the prefix of the name of the metadata used to define a group of
+	// mutually exclusive attributes of an object, e.g. "validation:exclusive:contact". The
+	// metadata values are the names of the attributes of the group, at most one of them may be
+	// set.
+	ExclusiveKeyPrefix = "validation:exclusive:"
+
+	// AtLeastOneKeyPrefix is the prefix of the name of the metadata used to define a group of
+	// attributes of an object at least one of which must be set, e.g.
+	// "validation:atleastone:contact". Combined with ExclusiveKeyPrefix on the same group
+	// exactly one of the attributes must be set.
+	AtLeastOneKeyPrefix = "validation:atleastone:"
+)
+
+// attributeGroup is a group of attributes defined with ExclusiveKeyPrefix or AtLeastOneKeyPrefix.
+type attributeGroup struct {
+	exclusive bool
+	names     []string
+}
+
 // Validator is the code generator for the 'Validate' type methods.
 type Validator struct {
 	arrayValT *template.Template
 	userValT  *template.Template
+	enumSetT  *template.Template
 	seen      map[string]*bytes.Buffer
+	enumSets  map[*design.AttributeDefinition]string
 }
 
 // NewValidator instantiates a validate code generator.
 func NewValidator() *Validator {
 	var (
-		v   = &Validator{seen: make(map[string]*bytes.Buffer)}
+		v = &Validator{
+			seen:     make(map[string]*bytes.Buffer),
+			enumSets: make(map[*design.AttributeDefinition]string),
+		}
 		err error
 	)
 	fm := template.FuncMap{
@@ -81,16 +113,55 @@ func NewValidator() *Validator {
 	if err != nil {
 		panic(err)
 	}
+	v.enumSetT, err = template.New("enumSet").Funcs(fm).Parse(enumSetTmpl)
+	if err != nil {
+		panic(err)
+	}
 	return v
 }
 
+// EnumSets produces the Go code that declares a package level map of the valid values of each
+// string enum attribute of the given user or media type, e.g. "accountStatusValid". The attributes
+// considered are the direct attributes of the type and the elements of its array attributes.
+// The validation code produced by the validator for these attributes subsequently checks enum
+// membership with a map lookup rather than a linear scan of the values. EnumSets returns an empty
+// string if the type does not define string enum attributes.
+func (v *Validator) EnumSets(ds design.DataStructure) string {
+	typeName := dataStructureName(ds)
+	obj := ds.Definition().Type.ToObject()
+	if typeName == "" || obj == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	obj.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		if arr := att.Type.ToArray(); arr != nil {
+			att = arr.ElemType
+		}
+		if att.Type.Kind() != design.StringKind || att.Validation == nil || len(att.Validation.Values) == 0 {
+			return nil
+		}
+		name := Goify(typeName, false) + Goify(n, true) + "Valid"
+		v.enumSets[att] = name
+		data := map[string]interface{}{
+			"name":     name,
+			"typeName": typeName,
+			"attName":  n,
+			"values":   att.Validation.Values,
+		}
+		buf.WriteString(RunTemplate(v.enumSetT, data))
+		return nil
+	})
+	return buf.String()
+}
+
 // Code produces Go code that runs the validation checks recursively over the given attribute.
 func (v *Validator) Code(att *design.AttributeDefinition, nonzero, required, hasDefault bool, target, context string, depth int, private bool) string {
-	buf := v.recurse(att, nonzero, required, hasDefault, target, context, depth, private)
+	buf := v.recurse(att, nonzero, checkedAccess(att, target, nonzero, required, hasDefault, private), target, context, depth, private)
 	return buf.String()
 }
 
-func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required, hasDefault bool, target, context string, depth int, private bool) *bytes.Buffer {
+// recurse produces the validation code for att whose value is accessed with fa.
+func (v *Validator) recurse(att *design.AttributeDefinition, nonzero bool, fa *fieldAccess, target, context string, depth int, private bool) *bytes.Buffer {
 	var (
 		buf   = new(bytes.Buffer)
 		first = true
@@ -114,11 +185,18 @@ func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required,
 		if ds, ok := att.Type.(design.DataStructure); ok {
 			att = ds.Definition()
 		}
-		validation := ValidationChecker(att, nonzero, required, hasDefault, target, context, depth, private)
+		validation := validationChecker(att, nonzero, fa, target, context, depth, private, "")
 		if validation != "" {
 			buf.WriteString(validation)
 			first = false
 		}
+		if groups := groupsCode(att, target, context, depth, private); groups != "" {
+			if !first {
+				buf.WriteByte('\n')
+			}
+			buf.WriteString(groups)
+			first = false
+		}
 		o.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
 			validation := v.recurseAttribute(att, catt, n, target, context, depth, private)
 			if validation != "" {
@@ -133,7 +211,7 @@ func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required,
 		})
 	} else if a := att.Type.ToArray(); a != nil {
 		// Perform any validation on the array type such as MinLength, MaxLength, etc.
-		validation := ValidationChecker(att, nonzero, required, hasDefault, target, context, depth, private)
+		validation := validationChecker(att, nonzero, fa, target, context, depth, private, "")
 		first := true
 		if validation != "" {
 			buf.WriteString(validation)
@@ -158,7 +236,7 @@ func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required,
 			buf.WriteString(validation)
 		}
 	} else {
-		validation := ValidationChecker(att, nonzero, required, hasDefault, target, context, depth, private)
+		validation := validationChecker(att, nonzero, fa, target, context, depth, private, v.enumSets[att])
 		if validation != "" {
 			buf.WriteString(validation)
 		}
@@ -166,8 +244,84 @@ func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required,
 	return buf
 }
 
+// attributeGroups returns the attribute groups defined by the metadata of the object attribute
+// att sorted by metadata name.
+func attributeGroups(att *design.AttributeDefinition) []*attributeGroup {
+	var keys []string
+	for k := range att.Metadata {
+		if strings.HasPrefix(k, ExclusiveKeyPrefix) || strings.HasPrefix(k, AtLeastOneKeyPrefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	groups := make([]*attributeGroup, len(keys))
+	for i, k := range keys {
+		groups[i] = &attributeGroup{
+			exclusive: strings.HasPrefix(k, ExclusiveKeyPrefix),
+			names:     att.Metadata[k],
+		}
+	}
+	return groups
+}
+
+// groupsCode produces the Go code that validates the attribute groups of the object attribute att
+// whose value is held in the variable target.
+func groupsCode(att *design.AttributeDefinition, target, context string, depth int, private bool) string {
+	obj := att.Type.ToObject()
+	var res []string
+	for _, g := range attributeGroups(att) {
+		var checks, unset, names []string
+		for _, n := range g.names {
+			catt, ok := obj[n]
+			if !ok {
+				continue
+			}
+			checks = append(checks, setCheck(att, catt, n, target, private, true))
+			u := setCheck(att, catt, n, target, private, false)
+			if strings.Contains(u, " || ") {
+				// The checks of the fields with more than one pointer indirection are
+				// disjunctions that must be grouped before they are combined.
+				u = "(" + u + ")"
+			}
+			unset = append(unset, u)
+			names = append(names, fmt.Sprintf("%q", n))
+		}
+		if len(checks) == 0 {
+			continue
+		}
+		tabs := Tabs(depth)
+		list := fmt.Sprintf("[]string{%s}", strings.Join(names, ", "))
+		if g.exclusive {
+			count := Tempvar()
+			var buf bytes.Buffer
+			fmt.Fprintf(&buf, "%s%s := 0\n", tabs, count)
+			for _, c := range checks {
+				fmt.Fprintf(&buf, "%sif %s {\n%s\t%s++\n%s}\n", tabs, c, tabs, count, tabs)
+			}
+			fmt.Fprintf(&buf, "%sif %s > 1 {\n%s\terr = goa.MergeErrors(err, goa.ExclusiveAttributesError(`%s`, %s))\n%s}",
+				tabs, count, tabs, context, list, tabs)
+			res = append(res, buf.String())
+		} else {
+			res = append(res, fmt.Sprintf("%sif %s {\n%s\terr = goa.MergeErrors(err, goa.MissingAttributeGroupError(`%s`, %s))\n%s}",
+				tabs, strings.Join(unset, " && "), tabs, context, list, tabs))
+		}
+	}
+	return strings.Join(res, "\n")
+}
+
+// setCheck returns the Go expression that evaluates to set if the field generated for the child
+// attribute catt of parent with the given name is set.
+func setCheck(parent, catt *design.AttributeDefinition, name, target string, private, set bool) string {
+	fa := newFieldAccess(parent, name, target, private)
+	if set {
+		return fa.set
+	}
+	return fa.unset
+}
+
 func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, target, context string, depth int, private bool) string {
 	var validation string
+	fa := newFieldAccess(att, n, target, private)
 	if ds, ok := catt.Type.(design.DataStructure); ok {
 		// We need to check empirically whether there are validations to be
 		// generated, we can't just generate and check whether something was
@@ -175,6 +329,10 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 		hasValidations := false
 		done := errors.New("done")
 		ds.Walk(func(a *design.AttributeDefinition) error {
+			if len(attributeGroups(a)) > 0 {
+				hasValidations = true
+				return done
+			}
 			if a.Validation != nil {
 				if private {
 					hasValidations = true
@@ -203,7 +361,7 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 		if hasValidations {
 			validation = RunTemplate(v.userValT, map[string]interface{}{
 				"depth":  depth,
-				"target": fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true)),
+				"target": fa.value,
 			})
 		}
 	} else {
@@ -211,12 +369,19 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 		if catt.Type.IsObject() {
 			dp++
 		}
+		field := fa.value
+		if catt.Type.IsPrimitive() && !isLazy(att, n, private) {
+			field = fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true))
+		}
+		if id := idType(catt); id != "" && catt.Type.IsPrimitive() {
+			// Validations apply to the underlying scalar of identifier types.
+			fa.value = fmt.Sprintf("%s(%s)", GoNativeType(catt.Type), fa.value)
+		}
 		validation = v.recurse(
 			catt,
 			att.IsNonZero(n),
-			att.IsRequired(n),
-			att.HasDefaultValue(n),
-			fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true)),
+			fa,
+			field,
 			fmt.Sprintf("%s.%s", context, n),
 			dp,
 			private,
@@ -224,8 +389,12 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 	}
 	if validation != "" {
 		if catt.Type.IsObject() {
-			validation = fmt.Sprintf("%sif %s.%s != nil {\n%s\n%s}",
-				Tabs(depth), target, GoifyAtt(catt, n, true), validation, Tabs(depth))
+			guard := fa.guard
+			if guard == "" {
+				guard = fa.value + " != nil"
+			}
+			validation = fmt.Sprintf("%sif %s {\n%s\n%s}",
+				Tabs(depth), guard, validation, Tabs(depth))
 		}
 	}
 	return validation
@@ -239,23 +408,40 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 // error. It initializes that variable in case a validation fails.
 // Note: we do not want to recurse here, recursion is done by the marshaler/unmarshaler code.
 func ValidationChecker(att *design.AttributeDefinition, nonzero, required, hasDefault bool, target, context string, depth int, private bool) string {
-	t := target
-	isPointer := private || (!required && !hasDefault && !nonzero)
-	if isPointer && att.Type.IsPrimitive() {
-		t = "*" + t
+	return validationChecker(att, nonzero, checkedAccess(att, target, nonzero, required, hasDefault, private), target, context, depth, private, "")
+}
+
+// checkedAccess returns the expressions that access the value of the variable target holding a
+// value of the type of att given the attribute properties.
+func checkedAccess(att *design.AttributeDefinition, target string, nonzero, required, hasDefault, private bool) *fieldAccess {
+	if !private && (required || hasDefault || nonzero) {
+		return &fieldAccess{value: target}
+	}
+	fa := &fieldAccess{guard: target + " != nil", value: target}
+	if att.Type.IsPrimitive() {
+		fa.value = "*" + target
 	}
+	return fa
+}
+
+// validationChecker implements ValidationChecker, fa holds the expressions that access the value
+// of target. enumSet is the name of the map of valid enum values declared by Validator.EnumSets for
+// att if any.
+func validationChecker(att *design.AttributeDefinition, nonzero bool, fa *fieldAccess, target, context string, depth int, private bool, enumSet string) string {
 	data := map[string]interface{}{
 		"attribute": att,
-		"isPointer": private || isPointer,
+		"isPointer": fa.guard != "",
+		"guard":     fa.guard,
 		"nonzero":   nonzero,
 		"context":   context,
 		"target":    target,
-		"targetVal": t,
+		"targetVal": fa.value,
 		"string":    att.Type.Name() == "string",
 		"array":     att.Type.IsArray(),
 		"hash":      att.Type.IsHash(),
 		"depth":     depth,
 		"private":   private,
+		"enumSet":   enumSet,
 	}
 	res := validationsCode(att.Validation, data)
 	return strings.Join(res, "\n")
@@ -315,6 +501,14 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 			res = append(res, val)
 		}
 	}
+	if validation.UniqueItems {
+		if arr := data["attribute"].(*design.AttributeDefinition).Type.ToArray(); arr != nil {
+			data["elemType"] = arr.ElemType.Type
+			if val := RunTemplate(uniqueValT, data); val != "" {
+				res = append(res, val)
+			}
+		}
+	}
 	if required := validation.Required; len(required) > 0 {
 		var val string
 		for i, r := range required {
@@ -322,6 +516,7 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 				val += "\n"
 			}
 			data["required"] = r
+			data["requiredUnset"] = requiredUnset(data["attribute"].(*design.AttributeDefinition), r, data["target"].(string), data["private"].(bool))
 			val += RunTemplate(requiredValT, data)
 		}
 		res = append(res, val)
@@ -329,6 +524,28 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 	return
 }
 
+// requiredUnset returns the Go expression that evaluates to true if the field generated for the
+// required child attribute of att with the given name is missing, the empty string if the field
+// cannot be missing, e.g. an integer field of a public struct.
+func requiredUnset(att *design.AttributeDefinition, name, target string, private bool) string {
+	catt := att.Type.ToObject()[name]
+	if catt == nil {
+		return fmt.Sprintf("%s.%s == nil", target, Goify(name, true))
+	}
+	field := fmt.Sprintf("%s.%s", target, GoifyAtt(catt, name, true))
+	switch {
+	case isAtomic(att, name, private):
+		return ""
+	case isLazy(att, name, private):
+		return newFieldAccess(att, name, target, private).unset
+	case !catt.Type.IsPrimitive() || fieldPointers(att, name, private) > 0:
+		return field + " == nil"
+	case catt.Type.Kind() == design.StringKind:
+		return field + ` == ""`
+	}
+	return ""
+}
+
 // oneof produces code that compares target with each element of vals and ORs
 // the result, e.g. "target == 1 || target == 2".
 func oneof(target string, vals []interface{}) string {
@@ -362,6 +579,8 @@ func constant(formatName string) string {
 		return "goa.FormatCIDR"
 	case "regexp":
 		return "goa.FormatRegexp"
+	case "color":
+		return "goa.FormatColor"
 	}
 	panic("unknown format") // bug
 }
@@ -376,28 +595,35 @@ const (
 {{ tabs .depth }}}`
 
 	enumValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
-*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
-{{ end }}{{ tabs $depth }}if !({{ oneof .targetVal .values }}) {
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .guard }} {
+{{ end }}{{ tabs $depth }}if {{ if .enumSet }}_, ok := {{ .enumSet }}[{{ .targetVal }}]; !ok{{ else }}!({{ oneof .targetVal .values }}){{ end }} {
 {{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, {{ slice .values }}))
 {{ if .isPointer }}{{ tabs $depth }}}
 {{ end }}{{ tabs .depth }}}`
 
+	enumSetTmpl = `// {{ .name }} holds the valid values of the {{ .typeName }} {{ .attName }} attribute.
+var {{ .name }} = map[string]struct{}{
+{{ range .values }}	{{ printf "%q" . }}: {},
+{{ end }}}
+
+`
+
 	patternValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
-*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .guard }} {
 {{ end }}{{ tabs $depth }}if ok := goa.ValidatePattern(` + "`{{ .pattern }}`" + `, {{ .targetVal }}); !ok {
 {{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, ` + "`{{ .pattern }}`" + `))
 {{ tabs $depth }}}{{ if .isPointer }}
 {{ tabs .depth }}}{{ end }}`
 
 	formatValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
-*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .guard }} {
 {{ end }}{{ tabs $depth }}if err2 := goa.ValidateFormat({{ constant .format }}, {{ .targetVal }}); err2 != nil {
 {{ tabs $depth }}		err = goa.MergeErrors(err, goa.InvalidFormatError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, {{ constant .format }}, err2))
 {{ if .isPointer }}{{ tabs $depth }}}
 {{ end }}{{ tabs .depth }}}`
 
 	minMaxValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
-*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .guard }} {
 {{ end }}{{ tabs .depth }}	if {{ .targetVal }} {{ if .isMin }}<{{ else }}>{{ end }} {{ if .isMin }}{{ .min }}{{ else }}{{ .max }}{{ end }} {
 {{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, {{ if .isMin }}{{ .min }}, true{{ else }}{{ .max }}, false{{ end }}))
 {{ if .isPointer }}{{ tabs $depth }}}
@@ -405,16 +631,22 @@ const (
 
 	lengthValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
 */}}{{ $target := or (and (or (or .array .hash) .nonzero) .target) .targetVal }}{{/*
-*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .guard }} {
 {{ end }}{{ tabs .depth }}	if {{ if .string }}utf8.RuneCountInString({{ $target }}){{ else }}len({{ $target }}){{ end }} {{ if .isMinLength }}<{{ else }}>{{ end }} {{ if .isMinLength }}{{ .minLength }}{{ else }}{{ .maxLength }}{{ end }} {
 {{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `{{ .context }}` + "`" + `, {{ $target }}, {{ if .string }}utf8.RuneCountInString({{ $target }}){{ else }}len({{ $target }}){{ end }}, {{ if .isMinLength }}{{ .minLength }}, true{{ else }}{{ .maxLength }}, false{{ end }}))
 {{ if .isPointer }}{{ tabs $depth }}}
 {{ end }}{{ tabs .depth }}}`
 
-	requiredValTmpl = `{{ $att := index $.attribute.Type.ToObject .required }}{{/*
-*/}}{{ if and (not $.private) (eq $att.Type.Kind 4) }}{{ tabs $.depth }}if {{ $.target }}.{{ goifyAtt $att .required true }} == "" {
-{{ tabs $.depth }}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{ $.context }}` + "`" + `, "{{  .required  }}"))
-{{ tabs $.depth }}}{{ else if or $.private (not $att.Type.IsPrimitive) }}{{ tabs $.depth }}if {{ $.target }}.{{ goifyAtt $att .required true }} == nil {
-{{ tabs $.depth }}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{ $.context }}` + "`" + `, "{{ .required }}"))
-{{ tabs $.depth }}}{{ end }}`
+	uniqueValTmpl = `{{ $seen := tempvar }}{{ tabs .depth }}{{ $seen }} := make(map[{{ gonative .elemType }}]bool, len({{ .target }}))
+{{ tabs .depth }}for _, e := range {{ .target }} {
+{{ tabs .depth }}	if {{ $seen }}[e] {
+{{ tabs .depth }}		err = goa.MergeErrors(err, goa.InvalidUniqueItemsError(` + "`" + `{{ .context }}` + "`" + `, e))
+{{ tabs .depth }}		break
+{{ tabs .depth }}	}
+{{ tabs .depth }}	{{ $seen }}[e] = true
+{{ tabs .depth }}}`
+
+	requiredValTmpl = `{{ if .requiredUnset }}{{ tabs .depth }}if {{ .requiredUnset }} {
+{{ tabs .depth }}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{ .context }}` + "`" + `, "{{ .required }}"))
+{{ tabs .depth }}}{{ end }}`
 )
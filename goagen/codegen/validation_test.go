@@ -1,7 +1,9 @@
 package codegen_test
 
 import (
+	"fmt"
 	"strings"
+	"testing"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
@@ -89,6 +91,23 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of array unique items", func() {
+				BeforeEach(func() {
+					attType = &design.Array{
+						ElemType: &design.AttributeDefinition{
+							Type: design.Integer,
+						},
+					}
+					validation = &dslengine.ValidationDefinition{
+						UniqueItems: true,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(arrayUniqueItemsValCode))
+				})
+			})
+
 			Context("of string min length 2", func() {
 				BeforeEach(func() {
 					attType = design.String
@@ -220,9 +239,314 @@ var _ = Describe("validation code generation", func() {
 
 		})
 	})
+
+	Describe("Validator with attribute groups", func() {
+		var att *design.AttributeDefinition
+		var code string
+
+		BeforeEach(func() {
+			att = &design.AttributeDefinition{
+				Type: design.Object{
+					"email": {Type: design.String},
+					"phone": {Type: design.String},
+					"fax":   {Type: design.Integer},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"fax"}},
+			}
+		})
+
+		JustBeforeEach(func() {
+			code = codegen.NewValidator().Code(att, false, false, false, "ut", "response", 1, false)
+		})
+
+		Context("with mutually exclusive attributes", func() {
+			BeforeEach(func() {
+				att.Metadata = dslengine.MetadataDefinition{"validation:exclusive:contact": {"email", "phone", "fax"}}
+			})
+
+			It("fails when more than one attribute is set", func() {
+				Ω(code).Should(Equal(exclusiveGroupCode))
+			})
+		})
+
+		Context("with attributes at least one of which is required", func() {
+			BeforeEach(func() {
+				att.Metadata = dslengine.MetadataDefinition{"validation:atleastone:contact": {"email", "phone", "fax"}}
+			})
+
+			It("fails when no attribute is set", func() {
+				Ω(code).Should(Equal(atLeastOneGroupCode))
+			})
+		})
+
+		Context("with attributes exactly one of which is required", func() {
+			BeforeEach(func() {
+				att.Metadata = dslengine.MetadataDefinition{
+					"validation:exclusive:contact":  {"email", "phone"},
+					"validation:atleastone:contact": {"email", "phone"},
+				}
+			})
+
+			It("fails when none or more than one attribute is set", func() {
+				Ω(code).Should(Equal(exactlyOneGroupCode))
+			})
+		})
+
+		Context("with attributes with two pointer indirections at least one of which is required", func() {
+			BeforeEach(func() {
+				obj := att.Type.ToObject()
+				obj["email"].Metadata = dslengine.MetadataDefinition{codegen.PointerDepthKey: {"2"}}
+				att.Metadata = dslengine.MetadataDefinition{"validation:atleastone:contact": {"email", "phone"}}
+			})
+
+			It("groups the checks of each attribute", func() {
+				Ω(code).Should(HavePrefix("\tif (ut.Email == nil || *ut.Email == nil) && ut.Phone == nil {\n"))
+			})
+		})
+
+		Context("with required and optional attributes exactly one of which is required", func() {
+			BeforeEach(func() {
+				att.Type.ToObject()["id"] = &design.AttributeDefinition{Type: design.UUID}
+				att.Validation.Required = append(att.Validation.Required, "id")
+				att.Metadata = dslengine.MetadataDefinition{
+					"validation:exclusive:key":  {"email", "id"},
+					"validation:atleastone:key": {"email", "id"},
+				}
+			})
+
+			It("generates code that compiles and accepts exactly one attribute", func() {
+				ut := &design.UserTypeDefinition{TypeName: "Contact", AttributeDefinition: att}
+				src := "package contact\n\nimport (\n\t\"github.com/goadesign/goa\"\n\tuuid \"github.com/satori/go.uuid\"\n)\n\n" +
+					"type Contact " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" +
+					"func (ut *Contact) Validate() (err error) {\n" + code + "\n\treturn\n}\n"
+				out, err := goTest(map[string]string{"contact.go": src, "contact_test.go": exactlyOneGroupTest})
+				Ω(err).ShouldNot(HaveOccurred(), out)
+			})
+		})
+	})
+
+	Describe("Validator with explicit pointer depths", func() {
+		var ut *design.UserTypeDefinition
+
+		BeforeEach(func() {
+			depth := func(d string) dslengine.MetadataDefinition {
+				return dslengine.MetadataDefinition{"struct:field:pointer": {d}}
+			}
+			minLength, maxLength := 2, 3
+			ut = &design.UserTypeDefinition{
+				TypeName: "Sample",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"zero": &design.AttributeDefinition{
+							Type:       design.String,
+							Metadata:   depth("0"),
+							Validation: &dslengine.ValidationDefinition{MinLength: &minLength},
+						},
+						"one": &design.AttributeDefinition{
+							Type:       design.String,
+							Metadata:   depth("1"),
+							Validation: &dslengine.ValidationDefinition{MaxLength: &maxLength},
+						},
+						"two": &design.AttributeDefinition{
+							Type:       design.String,
+							Metadata:   dslengine.MetadataDefinition{"struct:field:pointer": {"2"}, "struct:field:intern": nil},
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{"a", "b"}},
+						},
+					},
+					Validation: &dslengine.ValidationDefinition{Required: []string{"one", "two"}},
+				},
+			}
+		})
+
+		It("generates code that compiles and dereferences the fields", func() {
+			intern, err := codegen.GoInternUnmarshaler(ut)
+			Ω(err).ShouldNot(HaveOccurred())
+			src := "package sample\n\nimport (\n\t\"encoding/json\"\n\t\"unicode/utf8\"\n\n\t\"github.com/goadesign/goa\"\n)\n\n" +
+				"type Sample " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" +
+				"type sample " + codegen.GoTypeDef(ut, 0, true, true) + "\n\n" +
+				"func (ut *Sample) Validate() (err error) {\n" +
+				codegen.NewValidator().Code(ut.AttributeDefinition, false, false, false, "ut", "response", 1, false) +
+				"\n\treturn\n}\n\n" +
+				"func (ut *sample) Publicize() *Sample {\n\tvar pub Sample\n" +
+				codegen.RecursivePublicizer(ut.AttributeDefinition, "ut", "pub", 1) +
+				"\n\treturn &pub\n}\n\n" + intern
+			out, err := goTest(map[string]string{"sample.go": src, "sample_test.go": pointerDepthTest})
+			Ω(err).ShouldNot(HaveOccurred(), out)
+		})
+	})
+
+	Describe("Validator with sql null fields", func() {
+		var ut *design.UserTypeDefinition
+
+		BeforeEach(func() {
+			null := dslengine.MetadataDefinition{"struct:field:sqlnull": nil}
+			minLength, min := 2, 1.0
+			ut = &design.UserTypeDefinition{
+				TypeName: "Sample",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"name": &design.AttributeDefinition{
+							Type:       design.String,
+							Metadata:   null,
+							Validation: &dslengine.ValidationDefinition{MinLength: &minLength},
+						},
+						"count": &design.AttributeDefinition{
+							Type:       design.Integer,
+							Metadata:   null,
+							Validation: &dslengine.ValidationDefinition{Minimum: &min},
+						},
+					},
+					Metadata: dslengine.MetadataDefinition{"validation:atleastone:fields": {"name", "count"}},
+				},
+			}
+		})
+
+		It("generates code that compiles and checks the valid values", func() {
+			src := "package sample\n\nimport (\n\t\"unicode/utf8\"\n\n\t\"github.com/goadesign/goa\"\n)\n\n" +
+				"type Sample " + codegen.GoTypeDef(ut, 0, true, false) + "\n\n" +
+				"func (ut *Sample) Validate() (err error) {\n" +
+				codegen.NewValidator().Code(ut.AttributeDefinition, false, false, false, "ut", "response", 1, false) +
+				"\n\treturn\n}\n"
+			out, err := goTest(map[string]string{"sample.go": src, "sample_test.go": sqlNullValidationTest})
+			Ω(err).ShouldNot(HaveOccurred(), out)
+		})
+	})
+
+	Describe("Validator EnumSets", func() {
+		var ut *design.UserTypeDefinition
+		var validator *codegen.Validator
+		var sets, code string
+
+		BeforeEach(func() {
+			enum := func(vals ...interface{}) *dslengine.ValidationDefinition {
+				return &dslengine.ValidationDefinition{Values: vals}
+			}
+			ut = &design.UserTypeDefinition{
+				TypeName: "account",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"status": &design.AttributeDefinition{Type: design.String, Validation: enum("active", "closed")},
+						"tags": &design.AttributeDefinition{Type: &design.Array{
+							ElemType: &design.AttributeDefinition{Type: design.String, Validation: enum("a", "b")},
+						}},
+						"tier": &design.AttributeDefinition{Type: design.Integer, Validation: enum(1, 2)},
+					},
+					Validation: &dslengine.ValidationDefinition{Required: []string{"status"}},
+				},
+			}
+			validator = codegen.NewValidator()
+		})
+
+		JustBeforeEach(func() {
+			sets = validator.EnumSets(ut)
+			code = validator.Code(ut.AttributeDefinition, false, false, false, "ut", "response", 1, false)
+		})
+
+		It("declares one map per string enum attribute", func() {
+			Ω(sets).Should(Equal(enumSetsCode))
+		})
+
+		It("checks string enums with map lookups", func() {
+			Ω(code).Should(ContainSubstring("\tif _, ok := accountStatusValid[ut.Status]; !ok {\n"))
+			Ω(code).Should(ContainSubstring("\t\tif _, ok := accountTagsValid[e]; !ok {\n"))
+			Ω(code).Should(ContainSubstring("if !(*ut.Tier == 1 || *ut.Tier == 2) {"))
+		})
+
+		It("does not affect validators that did not declare the maps", func() {
+			code := codegen.NewValidator().Code(ut.AttributeDefinition, false, false, false, "ut", "response", 1, false)
+			Ω(code).Should(ContainSubstring(`if !(ut.Status == "active" || ut.Status == "closed") {`))
+		})
+	})
 })
 
+// largeEnum returns the values of a large enum, the map of the values and the last value.
+func largeEnum() ([]string, map[string]struct{}, string) {
+	values := make([]string, 200)
+	valid := make(map[string]struct{}, len(values))
+	for i := range values {
+		values[i] = fmt.Sprintf("value%d", i)
+		valid[values[i]] = struct{}{}
+	}
+	return values, valid, values[len(values)-1]
+}
+
+// BenchmarkEnumMapLookup measures the map lookup used for the string enums declared with
+// Validator.EnumSets.
+func BenchmarkEnumMapLookup(b *testing.B) {
+	_, valid, target := largeEnum()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, ok := valid[target]; !ok {
+			b.Fatal("invalid")
+		}
+	}
+}
+
+// BenchmarkEnumLinearLookup measures the linear scan used for the other enums.
+func BenchmarkEnumLinearLookup(b *testing.B) {
+	values, _, target := largeEnum()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		found := false
+		for _, v := range values {
+			if v == target {
+				found = true
+				break
+			}
+		}
+		if !found {
+			b.Fatal("invalid")
+		}
+	}
+}
+
 const (
+	exclusiveGroupCode = `	tmp1 := 0
+	if ut.Email != nil {
+		tmp1++
+	}
+	if ut.Phone != nil {
+		tmp1++
+	}
+	if ut.Fax != 0 {
+		tmp1++
+	}
+	if tmp1 > 1 {
+		err = goa.MergeErrors(err, goa.ExclusiveAttributesError(` + "`response`" + `, []string{"email", "phone", "fax"}))
+	}`
+
+	atLeastOneGroupCode = `	if ut.Email == nil && ut.Phone == nil && ut.Fax == 0 {
+		err = goa.MergeErrors(err, goa.MissingAttributeGroupError(` + "`response`" + `, []string{"email", "phone", "fax"}))
+	}`
+
+	exactlyOneGroupCode = `	if ut.Email == nil && ut.Phone == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeGroupError(` + "`response`" + `, []string{"email", "phone"}))
+	}
+	tmp1 := 0
+	if ut.Email != nil {
+		tmp1++
+	}
+	if ut.Phone != nil {
+		tmp1++
+	}
+	if tmp1 > 1 {
+		err = goa.MergeErrors(err, goa.ExclusiveAttributesError(` + "`response`" + `, []string{"email", "phone"}))
+	}`
+
+	enumSetsCode = `// accountStatusValid holds the valid values of the Account status attribute.
+var accountStatusValid = map[string]struct{}{
+	"active": {},
+	"closed": {},
+}
+
+// accountTagsValid holds the valid values of the Account tags attribute.
+var accountTagsValid = map[string]struct{}{
+	"a": {},
+	"b": {},
+}
+
+`
+
 	enumValCode = `	if val != nil {
 		if !(*val == 1 || *val == 2 || *val == 3) {
 			err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`context`" + `, *val, []interface{}{1, 2, 3}))
@@ -247,6 +571,15 @@ const (
 		}
 	}`
 
+	arrayUniqueItemsValCode = `	tmp1 := make(map[int]bool, len(val))
+	for _, e := range val {
+		if tmp1[e] {
+			err = goa.MergeErrors(err, goa.InvalidUniqueItemsError(` + "`" + `context` + "`" + `, e))
+			break
+		}
+		tmp1[e] = true
+	}`
+
 	stringMinLengthValCode = `	if val != nil {
 		if utf8.RuneCountInString(*val) < 2 {
 			err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `context` + "`" + `, *val, utf8.RuneCountInString(*val), 2, true))
@@ -298,3 +631,108 @@ const (
 		}
 	}`
 )
+
+const pointerDepthTest = `package sample
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPointerDepths(t *testing.T) {
+	one, two := "abcd", "c"
+	ptwo := &two
+	err := (&Sample{Zero: "x", One: &one, Two: &ptwo}).Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, field := range []string{"response.zero", "response.one", "response.two"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("missing %s error in %s", field, err)
+		}
+	}
+	one, two = "abc", "b"
+	if err := (&Sample{Zero: "xy", One: &one, Two: &ptwo}).Validate(); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	var nilTwo *string
+	if err := (&Sample{Zero: "xy", One: &one, Two: &nilTwo}).Validate(); err != nil {
+		t.Errorf("unexpected error for nil inner pointer %s", err)
+	}
+	if err := (&Sample{Zero: "xy"}).Validate(); err == nil || !strings.Contains(err.Error(), "\"two\"") {
+		t.Errorf("expected missing attribute errors, got %v", err)
+	}
+	zero := "xy"
+	pub := (&sample{Zero: &zero, One: &one, Two: &two}).Publicize()
+	if pub.Zero != "xy" || *pub.One != "abc" || **pub.Two != "b" {
+		t.Errorf("unexpected publicized value %#v", pub)
+	}
+	var decoded Sample
+	if err := json.Unmarshal([]byte(` + "`" + `{"zero":"xy","two":"a"}` + "`" + `), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Two == nil || *decoded.Two == nil || **decoded.Two != "a" {
+		t.Errorf("unexpected decoded value %#v", decoded)
+	}
+}
+`
+
+const sqlNullValidationTest = `package sample
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+)
+
+func TestSQLNullValidation(t *testing.T) {
+	if err := (&Sample{}).Validate(); err == nil || !strings.Contains(err.Error(), "name") {
+		t.Errorf("expected missing group error, got %v", err)
+	}
+	invalid := &Sample{
+		Name:  goa.NullString{NullString: sql.NullString{String: "a", Valid: true}},
+		Count: goa.NullInt64{NullInt64: sql.NullInt64{Int64: 0, Valid: true}},
+	}
+	err := invalid.Validate()
+	if err == nil || !strings.Contains(err.Error(), "response.name") || !strings.Contains(err.Error(), "response.count") {
+		t.Errorf("expected length and range errors, got %v", err)
+	}
+	countUnset := &Sample{
+		Name:  goa.NullString{NullString: sql.NullString{String: "ab", Valid: true}},
+		Count: goa.NullInt64{NullInt64: sql.NullInt64{Int64: 0}},
+	}
+	if err := countUnset.Validate(); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+}
+`
+
+const exactlyOneGroupTest = `package contact
+
+import (
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+func TestExactlyOne(t *testing.T) {
+	email := "joe@example.com"
+	cases := map[string]struct {
+		contact *Contact
+		valid   bool
+	}{
+		"none":       {&Contact{}, false},
+		"email only": {&Contact{Email: &email}, true},
+		"id only":    {&Contact{ID: uuid.NewV4()}, true},
+		"too many":   {&Contact{Email: &email, ID: uuid.NewV4()}, false},
+	}
+	for name, c := range cases {
+		if err := c.contact.Validate(); (err == nil) != c.valid {
+			t.Errorf("%s: unexpected validation result %v", name, err)
+		}
+	}
+}
+`
@@ -355,6 +355,29 @@ func PackageName(path string) (string, error) {
 	return pkgNames[0], nil
 }
 
+// GoPackageName returns a valid Go package name derived from the given API or service name, e.g.
+// "myapiv2" for "My API-V2". The function is not called PackageName as that name is taken by the
+// function that returns the name of the package at a given path.
+// The name is lowercased and stripped of the characters other than the ASCII letters and digits
+// as well as of its leading digits so that it never contains underscores. Names that collide with
+// Go keywords or with the identifiers listed in Reserved are suffixed with "api", e.g. "typeapi"
+// for "Type". GoPackageName returns "api" if no character of name can be used.
+func GoPackageName(name string) string {
+	var b bytes.Buffer
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9' && b.Len() > 0) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "api"
+	}
+	if Reserved[b.String()] {
+		b.WriteString("api")
+	}
+	return b.String()
+}
+
 const (
 	headerT = `{{if .Title}}//************************************************************************//
 // {{.Title}}
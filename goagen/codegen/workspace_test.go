@@ -0,0 +1,32 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GoPackageName", func() {
+	It("lowercases the name and strips spaces and hyphens", func() {
+		Ω(codegen.GoPackageName("My API-V2")).Should(Equal("myapiv2"))
+		Ω(codegen.GoPackageName("cellar_service")).Should(Equal("cellarservice"))
+	})
+
+	It("keeps digits but not leading ones", func() {
+		Ω(codegen.GoPackageName("2fa Service 3")).Should(Equal("faservice3"))
+	})
+
+	It("strips non-ASCII letters", func() {
+		Ω(codegen.GoPackageName("Café Ünïcode API")).Should(Equal("cafncodeapi"))
+		Ω(codegen.GoPackageName("日本 API")).Should(Equal("api"))
+	})
+
+	It("suffixes reserved words without underscores", func() {
+		Ω(codegen.GoPackageName("Type")).Should(Equal("typeapi"))
+		Ω(codegen.GoPackageName("HTTP")).Should(Equal("httpapi"))
+	})
+
+	It("falls back to a default name", func() {
+		Ω(codegen.GoPackageName("--- 42")).Should(Equal("api"))
+	})
+})
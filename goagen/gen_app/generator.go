@@ -14,19 +14,20 @@ import (
 
 // Generator is the application code generator.
 type Generator struct {
-	API       *design.APIDefinition // The API definition
-	OutDir    string                // Path to output directory
-	Target    string                // Name of generated package
-	NoTest    bool                  // Whether to skip test generation
-	genfiles  []string              // Generated files
-	validator *codegen.Validator    // Validation code generator
+	API        *design.APIDefinition // The API definition
+	OutDir     string                // Path to output directory
+	Target     string                // Name of generated package
+	NoTest     bool                  // Whether to skip test generation
+	Interfaces bool                  // Whether to generate the context interfaces
+	genfiles   []string              // Generated files
+	validator  *codegen.Validator    // Validation code generator
 }
 
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
 		outDir, target, ver string
-		notest              bool
+		notest, interfaces  bool
 	)
 
 	set := flag.NewFlagSet("app", flag.PanicOnError)
@@ -35,6 +36,7 @@ func Generate() (files []string, err error) {
 	set.StringVar(&target, "pkg", "app", "")
 	set.StringVar(&ver, "version", "", "")
 	set.BoolVar(&notest, "notest", false, "")
+	set.BoolVar(&interfaces, "interfaces", false, "")
 	set.Bool("force", false, "")
 	set.Parse(os.Args[1:])
 	outDir = filepath.Join(outDir, target)
@@ -44,7 +46,7 @@ func Generate() (files []string, err error) {
 	}
 
 	target = codegen.Goify(target, false)
-	g := &Generator{OutDir: outDir, Target: target, NoTest: notest, API: design.Design, validator: codegen.NewValidator()}
+	g := &Generator{OutDir: outDir, Target: target, NoTest: notest, Interfaces: interfaces, API: design.Design, validator: codegen.NewValidator()}
 
 	return g.Generate()
 }
@@ -115,8 +117,10 @@ func (g *Generator) generateContexts() error {
 	if err != nil {
 		panic(err) // bug
 	}
+	ctxWr.Interfaces = g.Interfaces
 	title := fmt.Sprintf("%s: Application Contexts", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("database/sql"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("strconv"),
@@ -125,6 +129,7 @@ func (g *Generator) generateContexts() error {
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+		codegen.SimpleImport("golang.org/x/text/language"),
 	}
 	g.genfiles = append(g.genfiles, ctxFile)
 	ctxWr.WriteHeader(title, g.Target, imports)
@@ -244,6 +249,7 @@ func (g *Generator) generateControllers() error {
 			action := map[string]interface{}{
 				"Name":            codegen.Goify(a.Name, true),
 				"Routes":          a.Routes,
+				"RouteConstants":  codegen.GoRouteConstants(a),
 				"Context":         context,
 				"Unmarshal":       unmarshal,
 				"Payload":         a.Payload,
@@ -358,6 +364,7 @@ func (g *Generator) generateMediaTypes() error {
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+		codegen.SimpleImport("golang.org/x/text/language"),
 	}
 	mtWr.WriteHeader(title, g.Target, imports)
 	err = g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
@@ -378,6 +385,28 @@ func (g *Generator) generateMediaTypes() error {
 
 // generateUserTypes iterates through the user types and generates the data structures and
 // marshaling code.
+// dataStructures returns the user types, media types and action payloads of the API.
+func (g *Generator) dataStructures() []design.DataStructure {
+	var types []design.DataStructure
+	g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
+		types = append(types, t)
+		return nil
+	})
+	g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		types = append(types, mt)
+		return nil
+	})
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Payload != nil {
+				types = append(types, a.Payload)
+			}
+			return nil
+		})
+	})
+	return types
+}
+
 func (g *Generator) generateUserTypes() error {
 	utFile := filepath.Join(g.OutDir, "user_types.go")
 	utWr, err := NewUserTypesWriter(utFile)
@@ -386,13 +415,24 @@ func (g *Generator) generateUserTypes() error {
 	}
 	title := fmt.Sprintf("%s: Application User Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("database/sql"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+		codegen.SimpleImport("golang.org/x/text/language"),
 	}
 	utWr.WriteHeader(title, g.Target, imports)
+	// The identifier types are shared by the user types, media types and payloads of the
+	// package so they are all declared here.
+	ids, err := codegen.GoIDTypes(g.dataStructures())
+	if err != nil {
+		return err
+	}
+	if ids != "" {
+		utWr.Write([]byte(ids + "\n"))
+	}
 	err = g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
 		return utWr.Execute(t)
 	})
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"text/template"
 
@@ -69,6 +71,70 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with user and media types using identifier types", func() {
+		BeforeEach(func() {
+			id := func(name string) dslengine.MetadataDefinition {
+				return dslengine.MetadataDefinition{"struct:field:id": {name}}
+			}
+			minLength := 2
+			ut := &design.UserTypeDefinition{
+				TypeName: "Author",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"id": &design.AttributeDefinition{
+							Type:       design.String,
+							Metadata:   id("UserID"),
+							Validation: &dslengine.ValidationDefinition{MinLength: &minLength, Pattern: "^[a-z]+$"},
+						},
+						"name": &design.AttributeDefinition{Type: design.String},
+						"reviewer": &design.AttributeDefinition{
+							Type:         design.String,
+							Metadata:     id("UserID"),
+							DefaultValue: "admin",
+						},
+					},
+					Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
+				},
+			}
+			postType := &design.UserTypeDefinition{
+				TypeName: "Post",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"id":       &design.AttributeDefinition{Type: design.Integer, Metadata: id("PostID")},
+						"authorID": &design.AttributeDefinition{Type: design.String, Metadata: id("UserID")},
+					},
+				},
+			}
+			mt := &design.MediaTypeDefinition{
+				UserTypeDefinition: postType,
+				Identifier:         "application/vnd.post",
+				Views: map[string]*design.ViewDefinition{
+					"default": {AttributeDefinition: postType.AttributeDefinition, Name: "default"},
+				},
+			}
+			design.Design = &design.APIDefinition{
+				Name:       "test api",
+				Types:      map[string]*design.UserTypeDefinition{"Author": ut},
+				MediaTypes: map[string]*design.MediaTypeDefinition{"application/vnd.post": mt},
+			}
+		})
+
+		It("declares the identifier types once and generates code that compiles", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "user_types.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(strings.Count(string(content), "type UserID string")).Should(Equal(1))
+			Ω(string(content)).Should(ContainSubstring("type PostID int"))
+			Ω(string(content)).Should(ContainSubstring("goa.ValidatePattern(`^[a-z]+$`, string(ut.ID))"))
+			Ω(string(content)).Should(ContainSubstring(`var defaultReviewer = UserID("admin")`))
+			cmd := exec.Command(filepath.Join(runtime.GOROOT(), "bin", "go"), "build", ".")
+			cmd.Dir = filepath.Join(outDir, "app")
+			cmd.Env = append(os.Environ(), "GOPATH="+workspace.Path+string(os.PathListSeparator)+os.Getenv("GOPATH"))
+			out, err := cmd.CombinedOutput()
+			Ω(err).ShouldNot(HaveOccurred(), string(out))
+		})
+	})
+
 	Context("with a simple API", func() {
 		var contextsCode, controllersCode, hrefsCode, mediaTypesCode string
 		var payload *design.UserTypeDefinition
@@ -199,6 +265,76 @@ var _ = Describe("Generate", func() {
 			})
 		})
 
+		Context("with interfaces enabled and routes that belong to their action", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--interfaces")
+				mt := design.Design.MediaTypes["application/vnd.rightscale.codegen.test.widgets"]
+				mt.Type = design.Object{"id": &design.AttributeDefinition{Type: design.String}}
+				get := design.Design.Resources["Widget"].Actions["get"]
+				get.Routes[0].Parent = get
+			})
+
+			It("generates the context interfaces and code that compiles", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("type GetWidgetContexter interface {\n"))
+				Ω(string(content)).Should(ContainSubstring("var _ GetWidgetContexter = (*GetWidgetContext)(nil)\n"))
+				Ω(string(content)).Should(ContainSubstring("\tGetID() string\n"))
+				Ω(string(content)).Should(ContainSubstring("func (ctx *GetWidgetContext) GetID() string {\n"))
+				content, err = ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("\tGetWidgetRoute = \"/:id\"\n"))
+				Ω(string(content)).Should(ContainSubstring(`service.Mux.Handle("GET", "/widgets"+GetWidgetRoute, ctrl.MuxHandler("Get", h, nil))`))
+				cmd := exec.Command(filepath.Join(runtime.GOROOT(), "bin", "go"), "build", ".")
+				cmd.Dir = filepath.Join(outDir, "app")
+				cmd.Env = append(os.Environ(), "GOPATH="+workspace.Path+string(os.PathListSeparator)+os.Getenv("GOPATH"))
+				out, err := cmd.CombinedOutput()
+				Ω(err).ShouldNot(HaveOccurred(), string(out))
+			})
+		})
+
+		Context("with a payload using sqlnull fields", func() {
+			BeforeEach(func() {
+				payload = &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"count": &design.AttributeDefinition{
+								Type:     design.Integer,
+								Metadata: dslengine.MetadataDefinition{codegen.SQLNullKey: nil},
+							},
+							"name": &design.AttributeDefinition{
+								Type:     design.String,
+								Metadata: dslengine.MetadataDefinition{codegen.SQLNullKey: nil},
+							},
+						},
+					},
+					TypeName: "Item",
+				}
+				design.Design.Resources["Widget"].Actions["get"].Payload = payload
+				item := *payload
+				item.TypeName = "StoredItem"
+				design.Design.Types = map[string]*design.UserTypeDefinition{"StoredItem": &item}
+				mt := design.Design.MediaTypes["application/vnd.rightscale.codegen.test.widgets"]
+				mt.Type = design.Object{"id": &design.AttributeDefinition{Type: design.String}}
+			})
+
+			It("publicizes the payload and user type and generates code that compiles", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("pub.Count = goa.NullInt64{NullInt64: sql.NullInt64{Int64: int64(*payload.Count), Valid: true}}"))
+				content, err = ioutil.ReadFile(filepath.Join(outDir, "app", "user_types.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("pub.Name = goa.NullString{NullString: sql.NullString{String: *ut.Name, Valid: true}}"))
+				cmd := exec.Command(filepath.Join(runtime.GOROOT(), "bin", "go"), "build", ".")
+				cmd.Dir = filepath.Join(outDir, "app")
+				cmd.Env = append(os.Environ(), "GOPATH="+workspace.Path+string(os.PathListSeparator)+os.Getenv("GOPATH"))
+				out, err := cmd.CombinedOutput()
+				Ω(err).ShouldNot(HaveOccurred(), string(out))
+			})
+		})
+
 		Context("with a slice payload", func() {
 			BeforeEach(func() {
 				elemType := &design.AttributeDefinition{Type: design.Integer}
@@ -290,6 +426,20 @@ func NewGetWidgetContext(ctx context.Context, service *goa.Service) (*GetWidgetC
 	return &rctx, err
 }
 
+// getWidgetContextKey is the type of the key used to store GetWidgetContext values in a context.Context.
+type getWidgetContextKey struct{}
+
+// WithGetWidgetContext returns a copy of ctx that holds the given GetWidgetContext.
+func WithGetWidgetContext(ctx context.Context, c *GetWidgetContext) context.Context {
+	return context.WithValue(ctx, getWidgetContextKey{}, c)
+}
+
+// GetWidgetContextFrom returns the GetWidgetContext stored in ctx by WithGetWidgetContext if any.
+func GetWidgetContextFrom(ctx context.Context) (*GetWidgetContext, bool) {
+	c, ok := ctx.Value(getWidgetContextKey{}).(*GetWidgetContext)
+	return c, ok
+}
+
 // OK sends a HTTP response with status code 200.
 func (ctx *GetWidgetContext) OK(r ID) error {
 	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.rightscale.codegen.test.widgets")
@@ -330,6 +480,11 @@ type WidgetController interface {
 	Get(*GetWidgetContext) error
 }
 
+// Route path templates of the get action of Widget.
+const (
+	GetWidgetRoute = "/:id"
+)
+
 // MountWidgetController "mounts" a Widget resource controller on the given service.
 func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 	initService(service)
@@ -394,6 +549,11 @@ package app
 `
 
 const controllersSlicePayloadCode = `
+// Route path templates of the get action of Widget.
+const (
+	GetWidgetRoute = "/:id"
+)
+
 // MountWidgetController "mounts" a Widget resource controller on the given service.
 func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 	initService(service)
@@ -433,6 +593,11 @@ func unmarshalGetWidgetPayload(ctx context.Context, service *goa.Service, req *h
 `
 
 const controllersOptionalPayloadCode = `
+// Route path templates of the get action of Widget.
+const (
+	GetWidgetRoute = "/:id"
+)
+
 // MountWidgetController "mounts" a Widget resource controller on the given service.
 func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 	initService(service)
@@ -274,6 +274,7 @@ func attToObject(name string, parent, att *design.AttributeDefinition) *ObjectTy
 }
 
 func goPathFormat(path string) string {
+	path = strings.Replace(path, "%", "%%", -1)
 	return design.WildcardRegex.ReplaceAllLiteralString(path, "/%v")
 }
 
@@ -16,6 +16,11 @@ import (
 // WildcardRegex is the regex used to capture path parameters.
 var WildcardRegex = regexp.MustCompile("(?:[^/]*/:([^/]+))+")
 
+// SplitParamKey is the name of the metadata used to flag array query params whose elements are
+// given as a single delimited value (e.g. "tags=a,b,c") rather than repeated keys. The optional
+// metadata value overrides the default "," delimiter.
+const SplitParamKey = "param:split"
+
 type (
 	// ContextsWriter generate codes for a goa application contexts.
 	ContextsWriter struct {
@@ -26,6 +31,10 @@ type (
 		PayloadTmpl *template.Template
 		Finalizer   *codegen.Finalizer
 		Validator   *codegen.Validator
+		// Interfaces controls whether to generate the interfaces implemented by the contexts.
+		// The interfaces describe the context param and payload getters and response methods
+		// so that tests may substitute mocks to the concrete context types.
+		Interfaces bool
 	}
 
 	// ControllersWriter generate code for a goa application handlers.
@@ -91,7 +100,7 @@ type (
 	ControllerTemplateData struct {
 		API            *design.APIDefinition          // API definition
 		Resource       string                         // Lower case plural resource name, e.g. "bottles"
-		Actions        []map[string]interface{}       // Array of actions, each action has keys "Name", "Routes", "Context" and "Unmarshal"
+		Actions        []map[string]interface{}       // Array of actions, each action has keys "Name", "Routes", "RouteConstants", "Context" and "Unmarshal"
 		FileServers    []*design.FileServerDefinition // File servers
 		Encoders       []*EncoderTemplateData         // Encoder data
 		Decoders       []*EncoderTemplateData         // Decoder data
@@ -213,10 +222,16 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 		"newCoerceData":      newCoerceData,
 		"arrayAttribute":     arrayAttribute,
 		"canonicalHeaderKey": http.CanonicalHeaderKey,
+		"paramDelimiter":     paramDelimiter,
+		"languageKind":       func() design.Kind { return design.LanguageKind },
 	}
 	if err := w.ExecuteTemplate("new", ctxNewT, fn, data); err != nil {
 		return err
 	}
+	keyFn := template.FuncMap{"goify": codegen.Goify}
+	if err := w.ExecuteTemplate("key", ctxKeyT, keyFn, data); err != nil {
+		return err
+	}
 	if data.Payload != nil {
 		found := false
 		for _, t := range design.Design.Types {
@@ -235,7 +250,20 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 			}
 		}
 	}
-	return data.IterateResponses(func(resp *design.ResponseDefinition) error {
+	var methods []string
+	if w.Interfaces {
+		getters := contextGetters(data)
+		for _, g := range getters {
+			methods = append(methods, fmt.Sprintf("%s() %s", g["Name"], g["Type"]))
+		}
+		if len(getters) > 0 {
+			getterData := map[string]interface{}{"Context": data, "Getters": getters}
+			if err := w.ExecuteTemplate("getters", ctxGettersT, nil, getterData); err != nil {
+				return err
+			}
+		}
+	}
+	err := data.IterateResponses(func(resp *design.ResponseDefinition) error {
 		respData := map[string]interface{}{
 			"Context":  data,
 			"Response": resp,
@@ -246,6 +274,8 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 			if mt, ok = resp.Type.(*design.MediaTypeDefinition); !ok {
 				respData["Type"] = resp.Type
 				respData["ContentType"] = resp.MediaType
+				methods = append(methods, fmt.Sprintf("%s(r %s) error",
+					codegen.Goify(resp.Name, true), codegen.GoTypeRef(resp.Type, nil, 0, false)))
 				return w.ExecuteTemplate("response", ctxTRespT, nil, respData)
 			}
 		} else {
@@ -279,14 +309,51 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 					base := fmt.Sprintf("%s%s", resp.Name, strings.Title(view))
 					respData["RespName"] = codegen.Goify(base, true)
 				}
+				methods = append(methods, fmt.Sprintf("%s(r %s) error",
+					respData["RespName"], codegen.GoTypeRef(projected, projected.AllRequired(), 0, false)))
 				if err := w.ExecuteTemplate("response", ctxMTRespT, fn, respData); err != nil {
 					return err
 				}
 			}
 			return nil
 		}
+		var arg string
+		if resp.MediaType != "" {
+			arg = "resp []byte"
+		}
+		methods = append(methods, fmt.Sprintf("%s(%s) error", codegen.Goify(resp.Name, true), arg))
 		return w.ExecuteTemplate("response", ctxNoMTRespT, nil, respData)
 	})
+	if err != nil || !w.Interfaces {
+		return err
+	}
+	ifaceData := map[string]interface{}{
+		"Context": data,
+		"Methods": methods,
+	}
+	return w.ExecuteTemplate("interface", ctxInterfaceT, nil, ifaceData)
+}
+
+// contextGetters returns the name, type and field of the getter methods of the params and
+// payload of the given context.
+func contextGetters(data *ContextTemplateData) []map[string]string {
+	var getters []map[string]string
+	if data.Params != nil {
+		data.Params.Type.ToObject().IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+			field := codegen.GoifyAtt(att, n, true)
+			typ := codegen.GoTypeRef(att.Type, nil, 0, false)
+			if att.Type.IsPrimitive() && data.Params.IsPrimitivePointer(n) {
+				typ = "*" + typ
+			}
+			getters = append(getters, map[string]string{"Name": "Get" + field, "Type": typ, "Field": field})
+			return nil
+		})
+	}
+	if data.Payload != nil {
+		typ := codegen.GoTypeRef(data.Payload, nil, 0, false)
+		getters = append(getters, map[string]string{"Name": "GetPayload", "Type": typ, "Field": "Payload"})
+	}
+	return getters
 }
 
 // NewControllersWriter returns a handlers code writer.
@@ -325,7 +392,8 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 		if err := w.ExecuteTemplate("controller", ctrlT, nil, d); err != nil {
 			return err
 		}
-		if err := w.ExecuteTemplate("mount", mountT, nil, d); err != nil {
+		mountFn := template.FuncMap{"routePath": codegen.GoRoutePath}
+		if err := w.ExecuteTemplate("mount", mountT, mountFn, d); err != nil {
 			return err
 		}
 		if len(d.Origins) > 0 {
@@ -388,7 +456,10 @@ func NewMediaTypesWriter(filename string) (*MediaTypesWriter, error) {
 func (w *MediaTypesWriter) Execute(mt *design.MediaTypeDefinition) error {
 	var (
 		mLinks *design.UserTypeDefinition
-		fn     = template.FuncMap{"validationCode": w.Validator.Code}
+		fn     = template.FuncMap{
+			"validationCode": w.Validator.Code,
+			"enumSets":       w.Validator.EnumSets,
+		}
 	)
 	err := mt.IterateViews(func(view *design.ViewDefinition) error {
 		p, links, err := mt.Project(view.Name)
@@ -433,6 +504,7 @@ func (w *UserTypesWriter) Execute(t *design.UserTypeDefinition) error {
 	fn := template.FuncMap{
 		"finalizeCode":   w.Finalizer.Code,
 		"validationCode": w.Validator.Code,
+		"enumSets":       w.Validator.EnumSets,
 	}
 	return w.ExecuteTemplate("types", userTypeT, fn, t)
 }
@@ -449,6 +521,19 @@ func newCoerceData(name string, att *design.AttributeDefinition, pointer bool, p
 	}
 }
 
+// paramDelimiter returns the delimiter used to split the values of the given array param if it
+// defines the SplitParamKey metadata, the empty string otherwise.
+func paramDelimiter(a *design.AttributeDefinition) string {
+	vals, ok := a.Metadata[SplitParamKey]
+	if !ok {
+		return ""
+	}
+	if len(vals) > 0 && vals[0] != "" {
+		return vals[0]
+	}
+	return ","
+}
+
 // arrayAttribute returns the array element attribute definition.
 func arrayAttribute(a *design.AttributeDefinition) *design.AttributeDefinition {
 	return a.Type.(*design.Array).ElemType
@@ -528,6 +613,16 @@ type {{ .Name }} struct {
 {{ tabs .Depth }}} else {
 {{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "uuid"))
 {{ tabs .Depth }}}
+{{ end }}{{ if eq .Attribute.Type.Kind languageKind }}{{/*
+
+*/}}{{/* LanguageType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := language.Parse(raw{{ goify .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "language"))
+{{ tabs .Depth }}}
 {{ end }}{{ if eq .Attribute.Type.Kind 7 }}{{/*
 
 */}}{{/* AnyType */}}{{/*
@@ -573,7 +668,9 @@ func New{{ .Name }}(ctx context.Context, service *goa.Service) (*{{ .Name }}, er
 		err = goa.MergeErrors(err, goa.MissingParamError("{{ $name }}"))
 	} else {
 {{ else }}	if len(param{{ goify $name true }}) > 0 {
-{{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}{{ if eq (arrayAttribute $att).Type.Kind 4 }}		params := param{{ goify $name true }}
+{{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}{{ $delim := paramDelimiter $att }}{{ if $delim }}{{/*
+*/}}		param{{ goify $name true }} = strings.Split(strings.Join(param{{ goify $name true }}, {{ printf "%q" $delim }}), {{ printf "%q" $delim }})
+{{ end }}{{ if eq (arrayAttribute $att).Type.Kind 4 }}		params := param{{ goify $name true }}
 {{ else }}		params := make({{ gotypedef $att 2 true false }}, len(param{{ goify $name true }}))
 		for i, raw{{ goify $name true}} := range param{{ goify $name true}} {
 {{ template "Coerce" (newCoerceData $name (arrayAttribute $att) ($.Params.IsPrimitivePointer $name) "params[i]" 3) }}{{/*
@@ -620,6 +717,47 @@ func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}({{ if .Response.
 	return err{{ else }}
 	return nil{{ end }}
 }
+`
+
+	// ctxKeyT generates the context key type and the functions that store and retrieve a
+	// context data structure in a context.Context.
+	// template input: *ContextTemplateData
+	ctxKeyT = `{{ $key := print (goify .Name false) "Key" }}
+// {{ $key }} is the type of the key used to store {{ .Name }} values in a context.Context.
+type {{ $key }} struct{}
+
+// With{{ .Name }} returns a copy of ctx that holds the given {{ .Name }}.
+func With{{ .Name }}(ctx context.Context, c *{{ .Name }}) context.Context {
+	return context.WithValue(ctx, {{ $key }}{}, c)
+}
+
+// {{ .Name }}From returns the {{ .Name }} stored in ctx by With{{ .Name }} if any.
+func {{ .Name }}From(ctx context.Context) (*{{ .Name }}, bool) {
+	c, ok := ctx.Value({{ $key }}{}).(*{{ .Name }})
+	return c, ok
+}
+`
+
+	// ctxGettersT generates the getter methods of the context params and payload.
+	// template input: map[string]interface{}
+	ctxGettersT = `{{ $ctx := .Context }}{{ range .Getters }}
+// {{ .Name }} returns the {{ .Field }} field of the context.
+func (ctx *{{ $ctx.Name }}) {{ .Name }}() {{ .Type }} {
+	return ctx.{{ .Field }}
+}
+{{ end }}`
+
+	// ctxInterfaceT generates the interface implemented by a context.
+	// template input: map[string]interface{}
+	ctxInterfaceT = `
+// {{ .Context.Name }}er is the interface implemented by {{ .Context.Name }}.
+type {{ .Context.Name }}er interface {
+	context.Context
+{{ range .Methods }}	{{ . }}
+{{ end }}}
+
+// {{ .Context.Name }} must implement {{ .Context.Name }}er.
+var _ {{ .Context.Name }}er = (*{{ .Context.Name }})(nil)
 `
 
 	// payloadT generates the payload type definition GoGenerator
@@ -685,9 +823,11 @@ func initService(service *goa.Service) {
 {{ end }}{{ end }}}
 `
 
-	// mountT generates the code for a resource "Mount" function.
+	// mountT generates the route path constants of the resource actions and the code for a
+	// resource "Mount" function.
 	// template input: *ControllerTemplateData
-	mountT = `
+	mountT = `{{ range .Actions }}{{ with .RouteConstants }}
+{{ . }}{{ end }}{{ end }}
 // Mount{{ .Resource }}Controller "mounts" a {{ .Resource }} resource controller on the given service.
 func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Controller) {
 	initService(service)
@@ -715,7 +855,7 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 	}
 {{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
 {{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
-{{ end }}{{ range .Routes }}	service.Mux.Handle("{{ .Verb }}", {{ printf "%q" .FullPath }}, ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
+{{ end }}{{ range .Routes }}	service.Mux.Handle("{{ .Verb }}", {{ routePath . }}, ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
 	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "%s %s" .Verb .FullPath) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
 {{ end }}{{ end }}{{ range .FileServers }}
 	h = ctrl.FileHandler({{ printf "%q" .RequestPath }}, {{ printf "%q" .FilePath }})
@@ -799,7 +939,7 @@ func {{ .Name }}Href({{ if .CanonicalParams }}{{ join .CanonicalParams ", " }} i
 // Identifier: {{ .Identifier }}{{ $typeName := gotypename . .AllRequired 0 false }}
 type {{ $typeName }} {{ gotypedef . 0 true false }}
 
-{{ $validation := validationCode .AttributeDefinition false false false "mt" "response" 1 false }}{{ if $validation }}// Validate validates the {{$typeName}} media type instance.
+{{ enumSets . }}{{ $validation := validationCode .AttributeDefinition false false false "mt" "response" 1 false }}{{ if $validation }}// Validate validates the {{$typeName}} media type instance.
 func (mt {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
@@ -826,7 +966,7 @@ type {{ $privateTypeName }} {{ gotypedef . 0 true true }}
 func (ut {{ gotyperef . .AllRequired 0 true }}) Finalize() {
 {{ $assignment }}
 }{{ end }}
-{{ $validation := validationCode .AttributeDefinition false false false "ut" "response" 1 true }}{{ if $validation }}// Validate validates the {{$privateTypeName}} type instance.
+{{ enumSets . }}{{ $validation := validationCode .AttributeDefinition false false false "ut" "response" 1 true }}{{ if $validation }}// Validate validates the {{$privateTypeName}} type instance.
 func (ut {{ gotyperef . .AllRequired 0 true }}) Validate() (err error) {
 {{ $validation }}
 	return
@@ -87,6 +87,66 @@ var _ = Describe("ContextsWriter", func() {
 					Ω(written).Should(ContainSubstring(emptyContext))
 					Ω(written).Should(ContainSubstring(emptyContextFactory))
 				})
+
+				It("writes the context key type and accessors", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(contextKey))
+					Ω(written).Should(ContainSubstring(contextAccessors))
+				})
+			})
+
+			Context("with interfaces enabled", func() {
+				BeforeEach(func() {
+					responses = map[string]*design.ResponseDefinition{
+						"NotFound": {Name: "NotFound", Status: 404},
+						"OK":       {Name: "OK", Status: 200, MediaType: "text/plain"},
+					}
+				})
+
+				It("writes the interface implemented by the context", func() {
+					writer.Interfaces = true
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(contextInterface))
+					Ω(written).Should(ContainSubstring("func (ctx *ListBottleContext) OK(resp []byte) error {"))
+					Ω(written).Should(ContainSubstring("func (ctx *ListBottleContext) NotFound() error {"))
+				})
+
+				Context("and params and a payload", func() {
+					BeforeEach(func() {
+						params = &design.AttributeDefinition{
+							Type: design.Object{
+								"id":    &design.AttributeDefinition{Type: design.Integer},
+								"since": &design.AttributeDefinition{Type: design.DateTime},
+							},
+							Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
+						}
+						payload = &design.UserTypeDefinition{
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{"name": &design.AttributeDefinition{Type: design.String}},
+							},
+							TypeName: "ListBottlePayload",
+						}
+					})
+
+					It("writes the getters and lists them in the interface", func() {
+						writer.Interfaces = true
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring(contextGetters))
+						Ω(written).Should(ContainSubstring(contextGettersInterface))
+					})
+				})
 			})
 
 			Context("with a media type setting a ContentType", func() {
@@ -221,6 +281,28 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with a language param", func() {
+				BeforeEach(func() {
+					langParam := &design.AttributeDefinition{Type: design.Language}
+					dataType := design.Object{
+						"param": langParam,
+					}
+					params = &design.AttributeDefinition{
+						Type: dataType,
+					}
+				})
+
+				It("writes the contexts code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(langContextFactory))
+				})
+			})
+
 			Context("with a number param", func() {
 				BeforeEach(func() {
 					numParam := &design.AttributeDefinition{Type: design.Number}
@@ -293,6 +375,33 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with a comma separated integer array param", func() {
+				BeforeEach(func() {
+					i := &design.AttributeDefinition{Type: design.Integer}
+					intArrayParam := &design.AttributeDefinition{
+						Type:     &design.Array{ElemType: i},
+						Metadata: dslengine.MetadataDefinition{genapp.SplitParamKey: nil},
+					}
+					dataType := design.Object{
+						"param": intArrayParam,
+					}
+					params = &design.AttributeDefinition{
+						Type: dataType,
+					}
+				})
+
+				It("splits the param value and reports invalid elements", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(intArrayContext))
+					Ω(written).Should(ContainSubstring(splitIntArrayContextFactory))
+				})
+			})
+
 			Context("with an integer array param", func() {
 				BeforeEach(func() {
 					i := &design.AttributeDefinition{Type: design.Integer}
@@ -1036,7 +1145,201 @@ var _ = Describe("HrefWriter", func() {
 	})
 })
 
+var _ = Describe("MediaTypesWriter", func() {
+	var writer *genapp.MediaTypesWriter
+	var workspace *codegen.Workspace
+	var filename string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("mediatypes")
+		Ω(err).ShouldNot(HaveOccurred())
+		src := pkg.CreateSourceFile("test.go")
+		filename = src.Abs()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		writer, err = genapp.NewMediaTypesWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+		codegen.TempCount = 0
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a media type with two links", func() {
+		var mt *design.MediaTypeDefinition
+
+		BeforeEach(func() {
+			design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+			ownerAtt := &design.AttributeDefinition{
+				Type: design.Object{"href": {Type: design.String}},
+			}
+			owner := &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					AttributeDefinition: ownerAtt,
+					TypeName:            "Owner",
+				},
+				Identifier: "application/vnd.goa.owner",
+			}
+			owner.Views = map[string]*design.ViewDefinition{
+				"default": {AttributeDefinition: ownerAtt, Name: "default", Parent: owner},
+				"link":    {AttributeDefinition: ownerAtt, Name: "link", Parent: owner},
+			}
+			mt = &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"name":   {Type: design.String},
+							"owner":  {Type: owner},
+							"winery": {Type: owner},
+						},
+					},
+					TypeName: "Bottle",
+				},
+				Identifier: "application/vnd.goa.bottle",
+			}
+			mt.Links = map[string]*design.LinkDefinition{
+				"owner":  {Name: "owner", Parent: mt},
+				"winery": {Name: "winery", Parent: mt},
+			}
+			mt.Views = map[string]*design.ViewDefinition{
+				"default": {
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"name":  {Type: design.String},
+							"links": {Type: design.String},
+						},
+					},
+					Name:   "default",
+					Parent: mt,
+				},
+				"tiny": {
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{"name": {Type: design.String}},
+					},
+					Name:   "tiny",
+					Parent: mt,
+				},
+			}
+		})
+
+		It("writes the links struct and the views that include it", func() {
+			err := writer.Execute(mt)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring(linkedMediaType))
+			Ω(written).Should(ContainSubstring(linkedMediaTypeTiny))
+			Ω(written).Should(ContainSubstring(mediaTypeLinks))
+		})
+	})
+
+	Context("with a media type with a string enum attribute", func() {
+		var mt *design.MediaTypeDefinition
+
+		BeforeEach(func() {
+			design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+			att := &design.AttributeDefinition{
+				Type: design.Object{
+					"status": {
+						Type:       design.String,
+						Validation: &dslengine.ValidationDefinition{Values: []interface{}{"open", "closed"}},
+					},
+				},
+			}
+			mt = &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					AttributeDefinition: att,
+					TypeName:            "Ticket",
+				},
+				Identifier: "application/vnd.goa.ticket",
+			}
+			mt.Views = map[string]*design.ViewDefinition{
+				"default": {AttributeDefinition: att, Name: "default", Parent: mt},
+			}
+		})
+
+		It("validates the enum with a map lookup", func() {
+			err := writer.Execute(mt)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring(enumSetMediaType))
+			Ω(written).Should(ContainSubstring("if _, ok := ticketStatusValid[*mt.Status]; !ok {"))
+		})
+	})
+})
+
 const (
+	enumSetMediaType = `// ticketStatusValid holds the valid values of the Ticket status attribute.
+var ticketStatusValid = map[string]struct{}{
+	"open": {},
+	"closed": {},
+}`
+
+	linkedMediaType = `type Bottle struct {
+	// Links to related resources
+	Links *BottleLinks ` + "`" + `form:"links,omitempty" json:"links,omitempty" xml:"links,omitempty"` + "`" + `
+	Name *string ` + "`" + `form:"name,omitempty" json:"name,omitempty" xml:"name,omitempty"` + "`" + `
+}`
+
+	linkedMediaTypeTiny = `type BottleTiny struct {
+	Name *string ` + "`" + `form:"name,omitempty" json:"name,omitempty" xml:"name,omitempty"` + "`" + `
+}`
+
+	mediaTypeLinks = `// BottleLinks contains links to related resources of Bottle.
+type BottleLinks struct {
+	Owner *OwnerLink ` + "`" + `form:"owner,omitempty" json:"owner,omitempty" xml:"owner,omitempty"` + "`" + `
+	Winery *OwnerLink ` + "`" + `form:"winery,omitempty" json:"winery,omitempty" xml:"winery,omitempty"` + "`" + `
+}`
+
+	contextInterface = `
+// ListBottleContexter is the interface implemented by ListBottleContext.
+type ListBottleContexter interface {
+	context.Context
+	OK(resp []byte) error
+	NotFound() error
+}
+
+// ListBottleContext must implement ListBottleContexter.
+var _ ListBottleContexter = (*ListBottleContext)(nil)
+`
+
+	contextGetters = `
+// GetID returns the ID field of the context.
+func (ctx *ListBottleContext) GetID() int {
+	return ctx.ID
+}
+
+// GetSince returns the Since field of the context.
+func (ctx *ListBottleContext) GetSince() *time.Time {
+	return ctx.Since
+}
+
+// GetPayload returns the Payload field of the context.
+func (ctx *ListBottleContext) GetPayload() *ListBottlePayload {
+	return ctx.Payload
+}
+`
+
+	contextGettersInterface = `
+type ListBottleContexter interface {
+	context.Context
+	GetID() int
+	GetSince() *time.Time
+	GetPayload() *ListBottlePayload
+	OK(resp []byte) error
+	NotFound() error
+}
+`
+
 	emptyContext = `
 type ListBottleContext struct {
 	context.Context
@@ -1054,6 +1357,24 @@ func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottl
 	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
 	return &rctx, err
 }
+`
+
+	contextKey = `
+// listBottleContextKey is the type of the key used to store ListBottleContext values in a context.Context.
+type listBottleContextKey struct{}
+`
+
+	contextAccessors = `
+// WithListBottleContext returns a copy of ctx that holds the given ListBottleContext.
+func WithListBottleContext(ctx context.Context, c *ListBottleContext) context.Context {
+	return context.WithValue(ctx, listBottleContextKey{}, c)
+}
+
+// ListBottleContextFrom returns the ListBottleContext stored in ctx by WithListBottleContext if any.
+func ListBottleContextFrom(ctx context.Context) (*ListBottleContext, bool) {
+	c, ok := ctx.Value(listBottleContextKey{}).(*ListBottleContext)
+	return c, ok
+}
 `
 
 	intContext = `
@@ -1110,6 +1431,27 @@ func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottl
 	}
 	return &rctx, err
 }
+`
+
+	langContextFactory = `
+func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	resp := goa.ContextResponse(ctx)
+	resp.Service = service
+	req := goa.ContextRequest(ctx)
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := paramParam[0]
+		if param, err2 := language.Parse(rawParam); err2 == nil {
+			tmp1 := &param
+			rctx.Param = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "language"))
+		}
+	}
+	return &rctx, err
+}
 `
 
 	strHeaderContext = `
@@ -1276,6 +1618,22 @@ func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottl
 }
 `
 
+	splitIntArrayContextFactory = `
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		paramParam = strings.Split(strings.Join(paramParam, ","), ",")
+		params := make([]int, len(paramParam))
+		for i, rawParam := range paramParam {
+			if param, err2 := strconv.Atoi(rawParam); err2 == nil {
+				params[i] = param
+			} else {
+				err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "integer"))
+			}
+		}
+		rctx.Param = params
+	}
+`
+
 	resContext = `
 type ListBottleContext struct {
 	context.Context
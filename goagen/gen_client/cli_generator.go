@@ -127,6 +127,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 		codegen.SimpleImport(clientPkg),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
+		codegen.SimpleImport("golang.org/x/text/language"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	if len(g.API.Resources) > 0 {
@@ -263,7 +264,7 @@ func defaultRouteParams(a *design.ActionDefinition) *design.AttributeDefinition
 
 // produces a fmt template to render the first route of action.
 func defaultRouteTemplate(a *design.ActionDefinition) string {
-	return design.WildcardRegex.ReplaceAllLiteralString(a.Routes[0].FullPath(), "/%v")
+	return routeFormat(a.Routes[0].FullPath())
 }
 
 // return a ',' joined list of Params as a reference to cmd.XFieldName
@@ -352,7 +353,7 @@ func flagTypeVal(a *design.AttributeDefinition, key string, field string) string
 		return `intFlagVal("` + key + `", ` + field + ")"
 	case design.String:
 		return `stringFlagVal("` + key + `", ` + field + ")"
-	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
+	case design.Number, design.Boolean, design.UUID, design.Language, design.DateTime, design.Any:
 		return "%s"
 	default:
 		return "&" + field
@@ -365,7 +366,7 @@ func flagTypeVal(a *design.AttributeDefinition, key string, field string) string
 // %s maps to specialTypeResult.Temps
 func flagRequiredTypeVal(a *design.AttributeDefinition, field string) string {
 	switch a.Type {
-	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
+	case design.Number, design.Boolean, design.UUID, design.Language, design.DateTime, design.Any:
 		return "*%s"
 	default:
 		return field
@@ -377,7 +378,7 @@ func flagRequiredTypeVal(a *design.AttributeDefinition, field string) string {
 // %s maps to specialTypeResult.Temps
 func flagTypeArrayVal(a *design.AttributeDefinition, field string) string {
 	switch a.Type.ToArray().ElemType.Type {
-	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
+	case design.Number, design.Boolean, design.UUID, design.Language, design.DateTime, design.Any:
 		return "%s"
 	}
 	return field
@@ -439,6 +440,8 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 					typeHandler = "boolVal"
 				case design.UUID:
 					typeHandler = "uuidVal"
+				case design.Language:
+					typeHandler = "languageVal"
 				case design.DateTime:
 					typeHandler = "timeVal"
 				case design.Any:
@@ -454,6 +457,8 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 					typeHandler = "boolArray"
 				case design.UUID:
 					typeHandler = "uuidArray"
+				case design.Language:
+					typeHandler = "languageArray"
 				case design.DateTime:
 					typeHandler = "timeArray"
 				case design.Any:
@@ -563,6 +568,8 @@ func flagType(att *design.AttributeDefinition) string {
 		return "String"
 	case design.UUIDKind:
 		return "String"
+	case design.LanguageKind:
+		return "String"
 	case design.AnyKind:
 		return "String"
 	case design.ArrayKind:
@@ -975,6 +982,29 @@ func uuidArray(ins []string) ([]uuid.UUID, error) {
 	return vals, nil
 }
 
+func languageVal(val string) (*language.Tag, error) {
+	t, err := language.Parse(val)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func languageArray(ins []string) ([]language.Tag, error) {
+	if ins == nil {
+		return nil, nil
+	}
+	var vals []language.Tag
+	for _, id := range ins {
+		val, err := languageVal(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, *val)
+	}
+	return vals, nil
+}
+
 func float64Val(val string) (*float64, error) {
 	t, err := strconv.ParseFloat(val, 64)
 	if err != nil {
@@ -78,12 +78,14 @@ var _ = Describe("Generate", func() {
 										"param":       &design.AttributeDefinition{Type: design.Integer},
 										"time":        &design.AttributeDefinition{Type: design.DateTime},
 										"uuid":        &design.AttributeDefinition{Type: design.UUID},
+										"language":    &design.AttributeDefinition{Type: design.Language},
 										"any":         &design.AttributeDefinition{Type: design.Any},
 										"bool":        &design.AttributeDefinition{Type: design.Boolean},
 										"number":      &design.AttributeDefinition{Type: design.Number},
 										"boolReq":     &design.AttributeDefinition{Type: design.Boolean},
 										"timeArray":   &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.DateTime}}},
 										"uuidArray":   &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.UUID}}},
+										"langArray":   &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Language}}},
 										"anyArray":    &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Any}}},
 										"boolArray":   &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Boolean}}},
 										"numberArray": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Number}}},
@@ -179,6 +181,16 @@ var _ = Describe("Generate", func() {
 			Ω(content).Should(ContainSubstring(", tmp"))
 			Ω(content).Should(ContainSubstring("cc.Flags().StringSliceVar(&cmd.UUIDArray, "))
 		})
+		It("generate the correct handling for special type Language", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "cli", "commands.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(", err = languageVal(cmd.Language)"))
+			Ω(content).Should(ContainSubstring(", err = languageArray(cmd.LangArray)"))
+			Ω(content).Should(ContainSubstring("cc.Flags().StringVar(&cmd.Language, "))
+			Ω(content).Should(ContainSubstring("cc.Flags().StringSliceVar(&cmd.LangArray, "))
+			Ω(content).Should(ContainSubstring("func languageVal(val string) (*language.Tag, error) {"))
+		})
 		It("generate the correct handling for special type Any", func() {
 			Ω(genErr).Should(BeNil())
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "cli", "commands.go"))
@@ -51,6 +51,7 @@ func Generate() (files []string, err error) {
 	set.String("design", "", "")
 	set.Bool("force", false, "")
 	set.Bool("notest", false, "")
+	set.Bool("interfaces", false, "")
 	set.Parse(os.Args[1:])
 
 	// First check compatibility
@@ -307,6 +308,7 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
+		codegen.SimpleImport("golang.org/x/text/language"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	if err := file.WriteHeader("", g.Target, imports); err != nil {
@@ -556,6 +558,7 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) er
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
+		codegen.SimpleImport("golang.org/x/text/language"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	mtWr.WriteHeader(title, g.Target, imports)
@@ -595,12 +598,23 @@ func (g *Generator) generateUserTypes(pkgDir string) error {
 	title := fmt.Sprintf("%s: Application User Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("database/sql"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
+		codegen.SimpleImport("golang.org/x/text/language"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	utWr.WriteHeader(title, g.Target, imports)
+	// The user types, media types and payloads of the client package render the identifier
+	// attributes with the identifier types so they are all declared here, as in the app package.
+	ids, err := codegen.GoIDTypes(g.dataStructures())
+	if err != nil {
+		return err
+	}
+	if ids != "" {
+		utWr.Write([]byte(ids + "\n"))
+	}
 	err = g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
 		return utWr.Execute(t)
 	})
@@ -611,6 +625,28 @@ func (g *Generator) generateUserTypes(pkgDir string) error {
 	return utWr.FormatCode()
 }
 
+// dataStructures returns the user types, media types and action payloads of the API.
+func (g *Generator) dataStructures() []design.DataStructure {
+	var types []design.DataStructure
+	g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
+		types = append(types, t)
+		return nil
+	})
+	g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		types = append(types, mt)
+		return nil
+	})
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Payload != nil {
+				types = append(types, a.Payload)
+			}
+			return nil
+		})
+	})
+	return types
+}
+
 // join is a code generation helper function that generates a function signature built from
 // concatenating the properties (name type) of the given attribute type (assuming it's an object).
 // join accepts an optional slice of strings which indicates the order in which the parameters
@@ -703,9 +739,9 @@ func cmdFieldTypeString(t design.DataType, point bool) string {
 	if point && !t.IsArray() {
 		pointer = "*"
 	}
-	if t.Kind() == design.UUIDKind || t.Kind() == design.DateTimeKind || t.Kind() == design.AnyKind || t.Kind() == design.NumberKind || t.Kind() == design.BooleanKind {
+	if t.Kind() == design.UUIDKind || t.Kind() == design.LanguageKind || t.Kind() == design.DateTimeKind || t.Kind() == design.AnyKind || t.Kind() == design.NumberKind || t.Kind() == design.BooleanKind {
 		suffix = "string"
-	} else if isArrayOfType(t, design.UUIDKind, design.DateTimeKind, design.AnyKind, design.NumberKind, design.BooleanKind) {
+	} else if isArrayOfType(t, design.UUIDKind, design.LanguageKind, design.DateTimeKind, design.AnyKind, design.NumberKind, design.BooleanKind) {
 		suffix = "[]string"
 	} else {
 		suffix = codegen.GoNativeType(t)
@@ -743,7 +779,7 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 			return fmt.Sprintf("%s := strconv.FormatFloat(%s, 'f', -1, 64)", target, name)
 		case design.StringKind:
 			return fmt.Sprintf("%s := %s", target, name)
-		case design.DateTimeKind, design.UUIDKind:
+		case design.DateTimeKind, design.UUIDKind, design.LanguageKind:
 			return fmt.Sprintf("%s := %s.String()", target, strings.Replace(name, "*", "", -1)) // remove pointer if present
 		case design.AnyKind:
 			return fmt.Sprintf("%s := fmt.Sprintf(\"%%v\", %s)", target, name)
@@ -791,7 +827,14 @@ func signerType(scheme *design.SecuritySchemeDefinition) string {
 
 // pathTemplate returns a fmt format suitable to build a request path to the reoute.
 func pathTemplate(r *design.RouteDefinition) string {
-	return design.WildcardRegex.ReplaceAllLiteralString(r.FullPath(), "/%v")
+	return routeFormat(r.FullPath())
+}
+
+// routeFormat returns a fmt format that replaces the parameters of the given route path with
+// operands, the literal "%" characters of the path are escaped.
+func routeFormat(path string) string {
+	path = strings.Replace(path, "%", "%%", -1)
+	return design.WildcardRegex.ReplaceAllLiteralString(path, "/%v")
 }
 
 // pathParams return the function signature of the path factory function for the given route.
@@ -3,10 +3,13 @@ package genclient_test
 import (
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/goagen/gen_client"
 	"github.com/goadesign/goa/version"
@@ -146,6 +149,22 @@ var _ = Describe("Generate", func() {
 			Ω(strings.Count(string(content), "func ShowFooPath2(")).Should(Equal(1))
 		})
 
+		Context("with a literal percent character in a route", func() {
+			BeforeEach(func() {
+				design.Design.Resources["foo"].Actions["show"].Routes[1].Path = "/100%/:id"
+				design.Design.Resources["foo"].Actions["show"].Params = &design.AttributeDefinition{
+					Type: design.Object{"id": &design.AttributeDefinition{Type: design.String}},
+				}
+			})
+
+			It("escapes the percent character in the path format", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(content).Should(ContainSubstring(`fmt.Sprintf("/100%%/%v", id)`))
+			})
+		})
+
 		Context("with a file server", func() {
 			BeforeEach(func() {
 				res := design.Design.Resources["foo"]
@@ -234,6 +253,70 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with user and media types using identifier types", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+			id := func(name string) dslengine.MetadataDefinition {
+				return dslengine.MetadataDefinition{"struct:field:id": {name}}
+			}
+			author := &design.UserTypeDefinition{
+				TypeName: "Author",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"id":   &design.AttributeDefinition{Type: design.String, Metadata: id("UserID")},
+						"name": &design.AttributeDefinition{Type: design.String},
+					},
+				},
+			}
+			post := &design.UserTypeDefinition{
+				TypeName: "Post",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"id":       &design.AttributeDefinition{Type: design.Integer, Metadata: id("PostID")},
+						"authorID": &design.AttributeDefinition{Type: design.String, Metadata: id("UserID")},
+					},
+				},
+			}
+			mt := &design.MediaTypeDefinition{
+				UserTypeDefinition: post,
+				Identifier:         "application/vnd.post",
+				Views: map[string]*design.ViewDefinition{
+					"default": {AttributeDefinition: post.AttributeDefinition, Name: "default"},
+				},
+			}
+			design.Design = &design.APIDefinition{
+				Name:       "testapi",
+				Types:      map[string]*design.UserTypeDefinition{"Author": author},
+				MediaTypes: map[string]*design.MediaTypeDefinition{"application/vnd.post": mt},
+			}
+		})
+
+		It("declares the identifier types once in the client package and generates code that compiles", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "user_types.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(strings.Count(string(content), "type UserID string")).Should(Equal(1))
+			Ω(string(content)).Should(ContainSubstring("type PostID int"))
+			mts, err := ioutil.ReadFile(filepath.Join(outDir, "client", "media_types.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(mts)).Should(ContainSubstring("UserID"))
+			Ω(string(mts)).ShouldNot(ContainSubstring("type UserID"))
+
+			// The other files of the client package depend on the client runtime, compile
+			// the types on their own against a stub client.
+			typesDir := filepath.Join(outDir, "types")
+			Ω(os.MkdirAll(typesDir, 0777)).Should(Succeed())
+			Ω(ioutil.WriteFile(filepath.Join(typesDir, "client.go"), []byte(stubClient), 0644)).Should(Succeed())
+			Ω(ioutil.WriteFile(filepath.Join(typesDir, "user_types.go"), content, 0644)).Should(Succeed())
+			Ω(ioutil.WriteFile(filepath.Join(typesDir, "media_types.go"), mts, 0644)).Should(Succeed())
+			cmd := exec.Command(filepath.Join(runtime.GOROOT(), "bin", "go"), "build", ".")
+			cmd.Dir = typesDir
+			out, err := cmd.CombinedOutput()
+			Ω(err).ShouldNot(HaveOccurred(), string(out))
+		})
+	})
+
 	Context("with an action with a user type payload", func() {
 		BeforeEach(func() {
 			codegen.TempCount = 0
@@ -287,3 +370,14 @@ var _ = Describe("Generate", func() {
 		})
 	})
 })
+
+const stubClient = `package client
+
+import "io"
+
+type Client struct {
+	Decoder interface {
+		Decode(v interface{}, body io.Reader, contentType string) error
+	}
+}
+`
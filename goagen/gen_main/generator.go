@@ -39,6 +39,7 @@ func Generate() (files []string, err error) {
 	set.StringVar(&ver, "version", "", "")
 	set.BoolVar(&force, "force", false, "")
 	set.Bool("notest", false, "")
+	set.Bool("interfaces", false, "")
 	set.Parse(os.Args[1:])
 
 	if err := codegen.CheckVersion(ver); err != nil {
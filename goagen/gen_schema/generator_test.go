@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/design/apidsl"
 	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
@@ -60,4 +61,31 @@ var _ = Describe("Generate", func() {
 			Ω(err).ShouldNot(HaveOccurred())
 		})
 	})
+	Context("with a payload with a language attribute", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("API with languages")
+			})
+			apidsl.Resource("locale", func() {
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.POST("/"))
+					apidsl.Payload(func() {
+						apidsl.Attribute("tag", design.Language, func() {
+							apidsl.Example("fr-CA")
+						})
+					})
+					apidsl.Response(design.OK)
+				})
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the language format", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "schema", "schema.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`"tag":{"type":"string","example":"fr-CA","format":"language"}`))
+		})
+	})
 })
@@ -43,6 +43,7 @@ type (
 		Maximum              *float64      `json:"maximum,omitempty"`
 		MinLength            *int          `json:"minLength,omitempty"`
 		MaxLength            *int          `json:"maxLength,omitempty"`
+		UniqueItems          bool          `json:"uniqueItems,omitempty"`
 		Required             []string      `json:"required,omitempty"`
 		AdditionalProperties bool          `json:"additionalProperties,omitempty"`
 
@@ -173,7 +174,7 @@ func GenerateResourceDefinition(api *design.APIDefinition, r *design.ResourceDef
 	Definitions[r.Name] = s
 	if mt, ok := api.MediaTypes[r.MediaType]; ok {
 		for _, v := range mt.Views {
-			buildMediaTypeSchema(api, mt, v.Name, s)
+			buildMediaTypeSchema(api, Definitions, mt, v.Name, s)
 		}
 	}
 	r.IterateActions(func(a *design.ActionDefinition) error {
@@ -236,8 +237,51 @@ func GenerateResourceDefinition(api *design.APIDefinition, r *design.ResourceDef
 
 // MediaTypeRef produces the JSON reference to the media type definition with the given view.
 func MediaTypeRef(api *design.APIDefinition, mt *design.MediaTypeDefinition, view string) string {
-	if _, ok := Definitions[mt.TypeName]; !ok {
-		GenerateMediaTypeDefinition(api, mt, view)
+	return mediaTypeRef(api, Definitions, mt, view)
+}
+
+// TypeRef produces the JSON reference to the type definition.
+func TypeRef(api *design.APIDefinition, ut *design.UserTypeDefinition) string {
+	return typeRef(api, Definitions, ut)
+}
+
+// GenerateMediaTypeDefinition produces the JSON schema corresponding to the given media type and
+// given view.
+func GenerateMediaTypeDefinition(api *design.APIDefinition, mt *design.MediaTypeDefinition, view string) {
+	generateMediaTypeDefinition(api, Definitions, mt, view)
+}
+
+// GenerateTypeDefinition produces the JSON schema corresponding to the given type.
+func GenerateTypeDefinition(api *design.APIDefinition, ut *design.UserTypeDefinition) {
+	generateTypeDefinition(api, Definitions, ut)
+}
+
+// TypeSchema produces the JSON schema corresponding to the given data type.
+func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
+	return typeSchema(api, Definitions, t)
+}
+
+// DefinitionSchema produces the JSON schema describing the given data type and records the
+// definitions of the types it refers to in defs rather than in Definitions. User and media types
+// are described by their definition rather than by a reference, media types with the given view.
+func DefinitionSchema(api *design.APIDefinition, t design.DataType, view string, defs map[string]*JSONSchema) *JSONSchema {
+	switch actual := t.(type) {
+	case *design.MediaTypeDefinition:
+		generateMediaTypeDefinition(api, defs, actual, view)
+		return defs[actual.TypeName]
+	case *design.UserTypeDefinition:
+		generateTypeDefinition(api, defs, actual)
+		return defs[actual.TypeName]
+	default:
+		return typeSchema(api, defs, t)
+	}
+}
+
+// mediaTypeRef produces the JSON reference to the media type definition with the given view
+// recorded in defs.
+func mediaTypeRef(api *design.APIDefinition, defs map[string]*JSONSchema, mt *design.MediaTypeDefinition, view string) string {
+	if _, ok := defs[mt.TypeName]; !ok {
+		generateMediaTypeDefinition(api, defs, mt, view)
 	}
 	ref := fmt.Sprintf("#/definitions/%s", mt.TypeName)
 	if view != "default" {
@@ -246,39 +290,40 @@ func MediaTypeRef(api *design.APIDefinition, mt *design.MediaTypeDefinition, vie
 	return ref
 }
 
-// TypeRef produces the JSON reference to the type definition.
-func TypeRef(api *design.APIDefinition, ut *design.UserTypeDefinition) string {
-	if _, ok := Definitions[ut.TypeName]; !ok {
-		GenerateTypeDefinition(api, ut)
+// typeRef produces the JSON reference to the type definition recorded in defs.
+func typeRef(api *design.APIDefinition, defs map[string]*JSONSchema, ut *design.UserTypeDefinition) string {
+	if _, ok := defs[ut.TypeName]; !ok {
+		generateTypeDefinition(api, defs, ut)
 	}
 	return fmt.Sprintf("#/definitions/%s", ut.TypeName)
 }
 
-// GenerateMediaTypeDefinition produces the JSON schema corresponding to the given media type and
-// given view.
-func GenerateMediaTypeDefinition(api *design.APIDefinition, mt *design.MediaTypeDefinition, view string) {
-	if _, ok := Definitions[mt.TypeName]; ok {
+// generateMediaTypeDefinition records the JSON schema corresponding to the given media type and
+// given view in defs.
+func generateMediaTypeDefinition(api *design.APIDefinition, defs map[string]*JSONSchema, mt *design.MediaTypeDefinition, view string) {
+	if _, ok := defs[mt.TypeName]; ok {
 		return
 	}
 	s := NewJSONSchema()
 	s.Title = fmt.Sprintf("Mediatype identifier: %s", mt.Identifier)
-	Definitions[mt.TypeName] = s
-	buildMediaTypeSchema(api, mt, view, s)
+	defs[mt.TypeName] = s
+	buildMediaTypeSchema(api, defs, mt, view, s)
 }
 
-// GenerateTypeDefinition produces the JSON schema corresponding to the given type.
-func GenerateTypeDefinition(api *design.APIDefinition, ut *design.UserTypeDefinition) {
-	if _, ok := Definitions[ut.TypeName]; ok {
+// generateTypeDefinition records the JSON schema corresponding to the given type in defs.
+func generateTypeDefinition(api *design.APIDefinition, defs map[string]*JSONSchema, ut *design.UserTypeDefinition) {
+	if _, ok := defs[ut.TypeName]; ok {
 		return
 	}
 	s := NewJSONSchema()
 	s.Title = ut.TypeName
-	Definitions[ut.TypeName] = s
-	buildAttributeSchema(api, s, ut.AttributeDefinition)
+	defs[ut.TypeName] = s
+	buildAttributeSchema(api, defs, s, ut.AttributeDefinition)
 }
 
-// TypeSchema produces the JSON schema corresponding to the given data type.
-func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
+// typeSchema produces the JSON schema corresponding to the given data type, the definitions of
+// the types it refers to are recorded in defs.
+func typeSchema(api *design.APIDefinition, defs map[string]*JSONSchema, t design.DataType) *JSONSchema {
 	s := NewJSONSchema()
 	switch actual := t.(type) {
 	case design.Primitive:
@@ -286,6 +331,9 @@ func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 		switch actual.Kind() {
 		case design.UUIDKind:
 			s.Format = "uuid"
+		case design.LanguageKind:
+			// BCP 47 language tag
+			s.Format = "language"
 		case design.DateTimeKind:
 			s.Format = "date-time"
 		case design.NumberKind:
@@ -296,22 +344,22 @@ func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 	case *design.Array:
 		s.Type = JSONArray
 		s.Items = NewJSONSchema()
-		buildAttributeSchema(api, s.Items, actual.ElemType)
+		buildAttributeSchema(api, defs, s.Items, actual.ElemType)
 	case design.Object:
 		s.Type = JSONObject
 		for n, at := range actual {
 			prop := NewJSONSchema()
-			buildAttributeSchema(api, prop, at)
+			buildAttributeSchema(api, defs, prop, at)
 			s.Properties[n] = prop
 		}
 	case *design.Hash:
 		s.Type = JSONObject
 		s.AdditionalProperties = true
 	case *design.UserTypeDefinition:
-		s.Ref = TypeRef(api, actual)
+		s.Ref = typeRef(api, defs, actual)
 	case *design.MediaTypeDefinition:
 		// Use "default" view by default
-		s.Ref = MediaTypeRef(api, actual, design.DefaultView)
+		s.Ref = mediaTypeRef(api, defs, actual, design.DefaultView)
 	}
 	return s
 }
@@ -368,6 +416,8 @@ func (s *JSONSchema) Merge(other *JSONSchema) {
 		}
 	}
 
+	s.UniqueItems = s.UniqueItems || other.UniqueItems
+
 	for n, p := range other.Properties {
 		if _, ok := s.Properties[n]; !ok {
 			if s.Properties == nil {
@@ -413,6 +463,7 @@ func (s *JSONSchema) Dup() *JSONSchema {
 		Maximum:              s.Maximum,
 		MinLength:            s.MinLength,
 		MaxLength:            s.MaxLength,
+		UniqueItems:          s.UniqueItems,
 		Required:             s.Required,
 		AdditionalProperties: s.AdditionalProperties,
 	}
@@ -429,14 +480,15 @@ func (s *JSONSchema) Dup() *JSONSchema {
 }
 
 // buildAttributeSchema initializes the given JSON schema that corresponds to the given attribute.
-func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.AttributeDefinition) *JSONSchema {
+// The definitions of the types the attribute refers to are recorded in defs.
+func buildAttributeSchema(api *design.APIDefinition, defs map[string]*JSONSchema, s *JSONSchema, at *design.AttributeDefinition) *JSONSchema {
 	if at.View != "" {
 		inner := NewJSONSchema()
-		inner.Ref = MediaTypeRef(api, at.Type.(*design.MediaTypeDefinition), at.View)
+		inner.Ref = mediaTypeRef(api, defs, at.Type.(*design.MediaTypeDefinition), at.View)
 		s.Merge(inner)
 		return s
 	}
-	s.Merge(TypeSchema(api, at.Type))
+	s.Merge(typeSchema(api, defs, at.Type))
 	if s.Ref != "" {
 		// Ref is exclusive with other fields
 		return s
@@ -463,6 +515,7 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	if val.MaxLength != nil {
 		s.MaxLength = val.MaxLength
 	}
+	s.UniqueItems = val.UniqueItems
 	s.Required = val.Required
 	return s
 }
@@ -531,7 +584,8 @@ func propertiesFromDefs(definitions map[string]*JSONSchema, path string) map[str
 }
 
 // buildMediaTypeSchema initializes s as the JSON schema representing mt for the given view.
-func buildMediaTypeSchema(api *design.APIDefinition, mt *design.MediaTypeDefinition, view string, s *JSONSchema) {
+// The definitions of the types mt refers to are recorded in defs.
+func buildMediaTypeSchema(api *design.APIDefinition, defs map[string]*JSONSchema, mt *design.MediaTypeDefinition, view string, s *JSONSchema) {
 	s.Media = &JSONMedia{Type: mt.Identifier}
 	projected, linksUT, err := mt.Project(view)
 	if err != nil {
@@ -557,7 +611,7 @@ func buildMediaTypeSchema(api *design.APIDefinition, mt *design.MediaTypeDefinit
 				href = toSchemaHref(api, r.CanonicalAction().Routes[0])
 			}
 			sm := NewJSONSchema()
-			sm.Ref = MediaTypeRef(api, lmt, "default")
+			sm.Ref = mediaTypeRef(api, defs, lmt, "default")
 			s.Links = append(s.Links, &JSONLink{
 				Title:        ln,
 				Rel:          ln,
@@ -569,5 +623,5 @@ func buildMediaTypeSchema(api *design.APIDefinition, mt *design.MediaTypeDefinit
 			})
 		}
 	}
-	buildAttributeSchema(api, s, projected.AttributeDefinition)
+	buildAttributeSchema(api, defs, s, projected.AttributeDefinition)
 }
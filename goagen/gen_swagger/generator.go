@@ -30,6 +30,7 @@ func Generate() (files []string, err error) {
 	set.String("design", "", "")
 	set.Bool("force", false, "")
 	set.Bool("notest", false, "")
+	set.Bool("interfaces", false, "")
 	set.Parse(os.Args[1:])
 
 	if err := codegen.CheckVersion(ver); err != nil {
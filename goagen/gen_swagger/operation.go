@@ -0,0 +1,61 @@
+package genswagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// ToOpenAPIOperation returns the Swagger 2.0 operation object describing the given action. The
+// operation ID is built from the action and resource names (e.g. "ShowBottle"), the parameters
+// and responses are built the same way as in the Swagger specification produced by New: the
+// payload is described by a "body" parameter.
+func ToOpenAPIOperation(a *design.ActionDefinition) (map[string]interface{}, error) {
+	api := design.Design
+	var path string
+	if len(a.Routes) > 0 {
+		path = a.Routes[0].FullPath()
+	}
+	params, err := paramsFromDefinition(a.AllParams(), path)
+	if err != nil {
+		return nil, err
+	}
+	params = append(params, paramsFromHeaders(a)...)
+
+	responses := make(map[string]*Response, len(a.Responses))
+	for _, r := range a.Responses {
+		resp, err := responseSpecFromDefinition(nil, api, r)
+		if err != nil {
+			return nil, err
+		}
+		responses[strconv.Itoa(r.Status)] = resp
+	}
+
+	operation := map[string]interface{}{
+		"operationId": codegen.Goify(a.Name, true) + codegen.Goify(a.Parent.Name, true),
+		"responses":   responses,
+	}
+	if a.Description != "" {
+		operation["description"] = a.Description
+	}
+	if a.Payload != nil {
+		params = append(params, payloadParam(api, a))
+	}
+	if len(params) > 0 {
+		operation["parameters"] = params
+	}
+
+	// Round trip through JSON so that the result only consists of generic values.
+	b, err := json.Marshal(operation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize operation of action %s: %s", a.Name, err)
+	}
+	var res map[string]interface{}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,115 @@
+package genswagger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_schema"
+)
+
+// ToOpenAPISchema returns the OpenAPI schema object describing the given data type. User and media
+// types are described by their definition (using the default view for media types) rather than by
+// a reference. The definitions of the user and media types the data type refers to are returned
+// under the "definitions" key of the schema so that the "#/definitions/" references of the schema
+// resolve within it. The examples generated for the schema properties are omitted as they depend
+// on the order in which the design schemas are built. If withExample is true the schema instead
+// includes an example of the whole type (of the default view for media types) under the "example"
+// key. The example is produced by the design example generator seeded with the API name so that it
+// is the same across runs.
+// The schema is built with a local definitions map: the definitions held by genschema.Definitions
+// are neither reused nor modified.
+func ToOpenAPISchema(t design.DataType, withExample bool) (map[string]interface{}, error) {
+	api := design.Design
+	defs := make(map[string]*genschema.JSONSchema)
+	att := &design.AttributeDefinition{Type: t}
+	if _, ok := t.(*design.MediaTypeDefinition); ok {
+		att.View = design.DefaultView
+	}
+	s := genschema.DefinitionSchema(api, t, att.View, defs)
+	var res map[string]interface{}
+	if err := roundTrip(s, &res); err != nil {
+		return nil, fmt.Errorf("failed to serialize schema of type %s: %s", t.Name(), err)
+	}
+	stripExamples(res)
+	if refs := referencedDefinitions(res, defs); len(refs) > 0 {
+		var definitions map[string]interface{}
+		if err := roundTrip(refs, &definitions); err != nil {
+			return nil, fmt.Errorf("failed to serialize definitions of type %s: %s", t.Name(), err)
+		}
+		for _, d := range definitions {
+			if ds, ok := d.(map[string]interface{}); ok {
+				stripExamples(ds)
+			}
+		}
+		res["definitions"] = definitions
+	}
+	if withExample {
+		var example interface{}
+		if err := roundTrip(att.GenerateExample(design.NewRandomGenerator(api.Name), nil), &example); err != nil {
+			return nil, fmt.Errorf("failed to serialize example of type %s: %s", t.Name(), err)
+		}
+		res["example"] = example
+	}
+	return res, nil
+}
+
+// referencedDefinitions returns the definitions of defs referenced by the given schema directly or
+// through other definitions.
+func referencedDefinitions(schema map[string]interface{}, defs map[string]*genschema.JSONSchema) map[string]*genschema.JSONSchema {
+	refs := make(map[string]*genschema.JSONSchema)
+	var collect func(v interface{})
+	collect = func(v interface{}) {
+		switch actual := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := actual["$ref"].(string); ok && strings.HasPrefix(ref, "#/definitions/") {
+				name := strings.TrimPrefix(ref, "#/definitions/")
+				if d, ok := defs[name]; ok {
+					if _, seen := refs[name]; !seen {
+						refs[name] = d
+						var def interface{}
+						if err := roundTrip(d, &def); err == nil {
+							collect(def)
+						}
+					}
+				}
+			}
+			for _, e := range actual {
+				collect(e)
+			}
+		case []interface{}:
+			for _, e := range actual {
+				collect(e)
+			}
+		}
+	}
+	collect(schema)
+	return refs
+}
+
+// roundTrip serializes v to JSON and deserializes the result into target so that target only
+// consists of generic values.
+func roundTrip(v interface{}, target interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, target)
+}
+
+// stripExamples removes the examples of the given schema and of its properties and items
+// recursively.
+func stripExamples(s map[string]interface{}) {
+	delete(s, "example")
+	if props, ok := s["properties"].(map[string]interface{}); ok {
+		for _, p := range props {
+			if ps, ok := p.(map[string]interface{}); ok {
+				stripExamples(ps)
+			}
+		}
+	}
+	if items, ok := s["items"].(map[string]interface{}); ok {
+		stripExamples(items)
+	}
+}
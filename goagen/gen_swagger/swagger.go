@@ -884,6 +884,17 @@ func buildPathFromFileServer(s *Swagger, api *design.APIDefinition, fs *design.F
 	return nil
 }
 
+// payloadParam returns the "body" parameter describing the payload of the given action.
+func payloadParam(api *design.APIDefinition, action *design.ActionDefinition) *Parameter {
+	return &Parameter{
+		Name:        "payload",
+		In:          "body",
+		Description: action.Payload.Description,
+		Required:    !action.PayloadOptional,
+		Schema:      genschema.TypeSchema(api, action.Payload),
+	}
+}
+
 func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *design.RouteDefinition, basePath string) error {
 	action := route.Parent
 
@@ -909,15 +920,7 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 	}
 
 	if action.Payload != nil {
-		payloadSchema := genschema.TypeSchema(api, action.Payload)
-		pp := &Parameter{
-			Name:        "payload",
-			In:          "body",
-			Description: action.Payload.Description,
-			Required:    !action.PayloadOptional,
-			Schema:      payloadSchema,
-		}
-		params = append(params, pp)
+		params = append(params, payloadParam(api, action))
 	}
 
 	operationID := fmt.Sprintf("%s#%s", action.Parent.Name, action.Name)
@@ -1125,6 +1128,17 @@ func initMaxLengthValidation(def interface{}, isArray bool, max *int) {
 	}
 }
 
+func initUniqueItemsValidation(def interface{}) {
+	switch actual := def.(type) {
+	case *Parameter:
+		actual.UniqueItems = true
+	case *Header:
+		actual.UniqueItems = true
+	case *Items:
+		actual.UniqueItems = true
+	}
+}
+
 func initValidations(attr *design.AttributeDefinition, def interface{}) {
 	val := attr.Validation
 	if val == nil {
@@ -1145,4 +1159,7 @@ func initValidations(attr *design.AttributeDefinition, def interface{}) {
 	if val.MaxLength != nil {
 		initMaxLengthValidation(def, attr.Type.IsArray(), val.MaxLength)
 	}
+	if val.UniqueItems {
+		initUniqueItemsValidation(def)
+	}
 }
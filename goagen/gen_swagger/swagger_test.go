@@ -654,3 +654,210 @@ var _ = Describe("New", func() {
 		})
 	})
 })
+
+var _ = Describe("ToOpenAPIOperation", func() {
+	var operation map[string]interface{}
+	var opErr error
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+		p := Type("UpdatePayload", func() {
+			Attribute("name", String)
+		})
+		API("test", func() {})
+		Resource("bottle", func() {
+			Action("update", func() {
+				Description("Update a bottle")
+				Routing(PUT("/bottles/:id"))
+				Params(func() {
+					Param("id", Integer)
+				})
+				Payload(p)
+				Response(NoContent)
+				Response(NotFound)
+			})
+		})
+	})
+
+	JustBeforeEach(func() {
+		err := dslengine.Run()
+		Ω(err).ShouldNot(HaveOccurred())
+		operation, opErr = genswagger.ToOpenAPIOperation(Design.Resources["bottle"].Actions["update"])
+	})
+
+	It("produces the operation object", func() {
+		Ω(opErr).ShouldNot(HaveOccurred())
+		var expected map[string]interface{}
+		err := json.Unmarshal([]byte(updateBottleOperation), &expected)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(operation).Should(Equal(expected))
+	})
+})
+
+const updateBottleOperation = `{
+	"operationId": "UpdateBottle",
+	"description": "Update a bottle",
+	"parameters": [
+		{"in": "path", "name": "id", "required": true, "type": "integer"},
+		{"in": "body", "name": "payload", "required": true, "schema": {"$ref": "#/definitions/UpdatePayload"}}
+	],
+	"responses": {
+		"204": {"description": "No Content"},
+		"404": {"description": "Not Found"}
+	}
+}`
+
+var _ = Describe("ToOpenAPISchema", func() {
+	var withExample bool
+	var schema map[string]interface{}
+	var schemaErr error
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+		API("test", func() {})
+		Type("Person", func() {
+			Attribute("name", String)
+			Attribute("age", Integer, func() {
+				Minimum(0)
+			})
+			Attribute("nicknames", ArrayOf(String))
+			Required("name")
+		})
+		withExample = true
+	})
+
+	JustBeforeEach(func() {
+		err := dslengine.Run()
+		Ω(err).ShouldNot(HaveOccurred())
+		schema, schemaErr = genswagger.ToOpenAPISchema(Design.Types["Person"], withExample)
+	})
+
+	It("includes an example of the whole type", func() {
+		Ω(schemaErr).ShouldNot(HaveOccurred())
+		example, ok := schema["example"].(map[string]interface{})
+		Ω(ok).Should(BeTrue())
+		Ω(example).Should(HaveKey("name"))
+		Ω(example["name"]).Should(BeAssignableToTypeOf(""))
+		delete(schema, "example")
+		var expected map[string]interface{}
+		err := json.Unmarshal([]byte(personSchema), &expected)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(schema).Should(Equal(expected))
+	})
+
+	It("produces the same example across calls", func() {
+		Ω(schemaErr).ShouldNot(HaveOccurred())
+		again, err := genswagger.ToOpenAPISchema(Design.Types["Person"], true)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(again["example"]).Should(Equal(schema["example"]))
+	})
+
+	It("does not use nor modify the global definitions", func() {
+		Ω(schemaErr).ShouldNot(HaveOccurred())
+		stale := &genschema.JSONSchema{Title: "stale"}
+		genschema.Definitions = map[string]*genschema.JSONSchema{"Person": stale}
+		again, err := genswagger.ToOpenAPISchema(Design.Types["Person"], false)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(again["title"]).Should(Equal("Person"))
+		Ω(genschema.Definitions).Should(Equal(map[string]*genschema.JSONSchema{"Person": stale}))
+	})
+
+	Context("with a media type", func() {
+		var mtSchema map[string]interface{}
+		var mtErr error
+
+		BeforeEach(func() {
+			MediaType("application/vnd.person", func() {
+				Attributes(func() {
+					Attribute("name", String)
+					Attribute("age", Integer)
+					Attribute("nicknames", ArrayOf(String))
+					Required("name")
+				})
+				View("default", func() {
+					Attribute("name")
+					Attribute("age")
+				})
+				View("full", func() {
+					Attribute("name")
+					Attribute("age")
+					Attribute("nicknames")
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			mtSchema, mtErr = genswagger.ToOpenAPISchema(Design.MediaTypes["application/vnd.person"], true)
+		})
+
+		It("describes the default view in the schema and in the example", func() {
+			Ω(mtErr).ShouldNot(HaveOccurred())
+			props, ok := mtSchema["properties"].(map[string]interface{})
+			Ω(ok).Should(BeTrue())
+			Ω(props).Should(HaveLen(2))
+			Ω(props).Should(HaveKey("name"))
+			Ω(props).Should(HaveKey("age"))
+			example, ok := mtSchema["example"].(map[string]interface{})
+			Ω(ok).Should(BeTrue())
+			Ω(example).ShouldNot(HaveKey("nicknames"))
+			for n := range example {
+				Ω(props).Should(HaveKey(n))
+			}
+		})
+	})
+
+	Context("with a nested user type", func() {
+		var teamSchema map[string]interface{}
+		var teamErr error
+
+		BeforeEach(func() {
+			Type("Team", func() {
+				Attribute("name", String)
+				Attribute("lead", "Person")
+				Attribute("members", ArrayOf("Person"))
+			})
+		})
+
+		JustBeforeEach(func() {
+			teamSchema, teamErr = genswagger.ToOpenAPISchema(Design.Types["Team"], false)
+		})
+
+		It("returns the definitions of the referenced types", func() {
+			Ω(teamErr).ShouldNot(HaveOccurred())
+			props := teamSchema["properties"].(map[string]interface{})
+			Ω(props["lead"]).Should(Equal(map[string]interface{}{"$ref": "#/definitions/Person"}))
+			defs, ok := teamSchema["definitions"].(map[string]interface{})
+			Ω(ok).Should(BeTrue())
+			Ω(defs).Should(HaveLen(1))
+			var expected map[string]interface{}
+			err := json.Unmarshal([]byte(personSchema), &expected)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(defs["Person"]).Should(Equal(expected))
+			Ω(genschema.Definitions).Should(BeEmpty())
+		})
+	})
+
+	Context("with examples disabled", func() {
+		BeforeEach(func() {
+			withExample = false
+		})
+
+		It("does not include an example", func() {
+			Ω(schemaErr).ShouldNot(HaveOccurred())
+			Ω(schema).ShouldNot(HaveKey("example"))
+		})
+	})
+})
+
+const personSchema = `{
+	"title": "Person",
+	"type": "object",
+	"properties": {
+		"age": {"type": "integer", "minimum": 0},
+		"name": {"type": "string"},
+		"nicknames": {"type": "array", "items": {"type": "string"}}
+	},
+	"required": ["name"]
+}`
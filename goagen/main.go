@@ -61,8 +61,9 @@ package and tool and the Swagger specification for the API.
 
 	// appCmd implements the "app" command.
 	var (
-		pkg    string
-		notest bool
+		pkg        string
+		notest     bool
+		interfaces bool
 	)
 	appCmd := &cobra.Command{
 		Use:   "app",
@@ -71,6 +72,7 @@ package and tool and the Swagger specification for the API.
 	}
 	appCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
 	appCmd.Flags().BoolVar(&notest, "notest", false, "Prevent generation of test helpers")
+	appCmd.Flags().BoolVar(&interfaces, "interfaces", false, "Generate interfaces describing the action contexts")
 	rootCmd.AddCommand(appCmd)
 
 	// mainCmd implements the "main" command.
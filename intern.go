@@ -0,0 +1,82 @@
+package goa
+
+import (
+	"container/list"
+	"sync"
+)
+
+const (
+	// DefaultInternCapacity is the default maximum number of strings held by the intern table.
+	DefaultInternCapacity = 4096
+
+	// MaxInternLength is the length in bytes above which Intern returns the strings unchanged.
+	MaxInternLength = 256
+)
+
+// internTable holds the strings interned by Intern. The table is bounded: once it holds capacity
+// strings interning a new string evicts the least recently used one so that decoding untrusted
+// input cannot grow the table indefinitely.
+var internTable = newInternLRU(DefaultInternCapacity)
+
+type internLRU struct {
+	sync.Mutex
+	capacity int
+	order    *list.List
+	strings  map[string]*list.Element
+}
+
+func newInternLRU(capacity int) *internLRU {
+	return &internLRU{
+		capacity: capacity,
+		order:    list.New(),
+		strings:  make(map[string]*list.Element),
+	}
+}
+
+// Intern returns a string equal to s that shares its backing memory with the other strings equal
+// to s returned by Intern while s is held by the intern table. Interning reduces the memory used by
+// many repeated values such as tenant IDs or enum-like strings. The table holds at most
+// DefaultInternCapacity strings (see SetInternCapacity) and evicts the least recently used
+// strings first, strings longer than MaxInternLength are not interned.
+// goagen generates UnmarshalJSON methods that intern the fields of the attributes that define the
+// "struct:field:intern" metadata.
+func Intern(s string) string {
+	if len(s) > MaxInternLength {
+		return s
+	}
+	internTable.Lock()
+	defer internTable.Unlock()
+	if e, ok := internTable.strings[s]; ok {
+		internTable.order.MoveToFront(e)
+		return e.Value.(string)
+	}
+	if internTable.capacity <= 0 {
+		return s
+	}
+	if internTable.order.Len() >= internTable.capacity {
+		oldest := internTable.order.Back()
+		internTable.order.Remove(oldest)
+		delete(internTable.strings, oldest.Value.(string))
+	}
+	internTable.strings[s] = internTable.order.PushFront(s)
+	return s
+}
+
+// SetInternCapacity releases all the strings interned by Intern and sets the maximum number of
+// strings held by the intern table. A capacity of 0 or less disables interning.
+func SetInternCapacity(capacity int) {
+	internTable.Lock()
+	defer internTable.Unlock()
+	internTable.capacity = capacity
+	internTable.order.Init()
+	internTable.strings = make(map[string]*list.Element)
+}
+
+// ResetInternTable releases all the strings interned by Intern. Strings interned after the reset do
+// not share memory with strings interned before.
+func ResetInternTable() {
+	internTable.Lock()
+	defer internTable.Unlock()
+	internTable.order.Init()
+	internTable.strings = make(map[string]*list.Element)
+}
@@ -0,0 +1,87 @@
+package goa_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// stringData returns the address of the backing memory of s.
+func stringData(s string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+}
+
+var _ = Describe("Intern", func() {
+	var first, second string
+
+	BeforeEach(func() {
+		goa.ResetInternTable()
+		var a, b struct{ Tenant string }
+		Ω(json.Unmarshal([]byte(`{"Tenant":"acme"}`), &a)).ShouldNot(HaveOccurred())
+		Ω(json.Unmarshal([]byte(`{"Tenant":"acme"}`), &b)).ShouldNot(HaveOccurred())
+		first, second = a.Tenant, b.Tenant
+	})
+
+	It("returns equal strings that share their backing memory", func() {
+		i1, i2 := goa.Intern(first), goa.Intern(second)
+		Ω(i1).Should(Equal("acme"))
+		Ω(i2).Should(Equal("acme"))
+		Ω(stringData(i1)).Should(Equal(stringData(i2)))
+	})
+
+	It("does not share memory across resets", func() {
+		i1 := goa.Intern(first)
+		goa.ResetInternTable()
+		i2 := goa.Intern(string([]byte(second)))
+		Ω(i2).Should(Equal(i1))
+		Ω(stringData(i1)).ShouldNot(Equal(stringData(i2)))
+	})
+
+	Context("with a bounded table", func() {
+		BeforeEach(func() {
+			goa.SetInternCapacity(2)
+		})
+
+		AfterEach(func() {
+			goa.SetInternCapacity(goa.DefaultInternCapacity)
+		})
+
+		It("evicts the least recently used strings", func() {
+			i1 := goa.Intern(first)
+			goa.Intern("other")
+			goa.Intern(string([]byte(second)))
+			goa.Intern("another")
+			Ω(stringData(goa.Intern(string([]byte(first))))).Should(Equal(stringData(i1)))
+			goa.Intern("more")
+			goa.Intern("and more")
+			Ω(stringData(goa.Intern(string([]byte(second))))).ShouldNot(Equal(stringData(i1)))
+		})
+	})
+
+	It("does not intern long strings", func() {
+		long := strings.Repeat("a", goa.MaxInternLength+1)
+		i1 := goa.Intern(long)
+		i2 := goa.Intern(string([]byte(long)))
+		Ω(i2).Should(Equal(i1))
+		Ω(stringData(i1)).ShouldNot(Equal(stringData(i2)))
+	})
+})
+
+func BenchmarkIntern(b *testing.B) {
+	goa.ResetInternTable()
+	values := make([]string, 100)
+	for i := range values {
+		values[i] = fmt.Sprintf("tenant-%d", i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		goa.Intern(values[i%len(values)])
+	}
+}
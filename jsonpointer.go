@@ -0,0 +1,194 @@
+package goa
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SetJSONPointer sets the value addressed by the RFC 6901 JSON pointer ptr in the data structure
+// pointed to by target. Struct fields are resolved using their JSON names, including the fields
+// promoted from embedded structs such as the base struct embedded by the generated derived types,
+// array and slice elements using their index. Nil pointers found along the way are allocated.
+// value must be assignable to the addressed value, numbers are converted as long as the
+// conversion preserves the value.
+// goagen generates JSONPointerSet methods that call SetJSONPointer on the generated structs.
+func SetJSONPointer(target interface{}, ptr string, value interface{}) error {
+	tokens, err := parseJSONPointer(ptr)
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("invalid JSON pointer target %T, must be a non-nil pointer", target)
+	}
+	cur := v.Elem()
+	for i, tok := range tokens {
+		for cur.Kind() == reflect.Ptr {
+			if cur.IsNil() {
+				cur.Set(reflect.New(cur.Type().Elem()))
+			}
+			cur = cur.Elem()
+		}
+		switch cur.Kind() {
+		case reflect.Struct:
+			f, ok := jsonField(cur, tok)
+			if !ok {
+				return fmt.Errorf("JSON pointer %q: no field %q in %s", ptr, tok, cur.Type())
+			}
+			cur = f
+		case reflect.Slice, reflect.Array:
+			idx, err := strconv.Atoi(tok)
+			if err != nil || idx < 0 || idx >= cur.Len() {
+				return fmt.Errorf("JSON pointer %q: index %s out of range", ptr, tok)
+			}
+			cur = cur.Index(idx)
+		case reflect.Map:
+			if cur.Type().Key().Kind() != reflect.String {
+				return fmt.Errorf("JSON pointer %q: map keys of %s are not strings", ptr, cur.Type())
+			}
+			if cur.IsNil() {
+				cur.Set(reflect.MakeMap(cur.Type()))
+			}
+			key := reflect.ValueOf(tok).Convert(cur.Type().Key())
+			elem := reflect.New(cur.Type().Elem()).Elem()
+			if existing := cur.MapIndex(key); existing.IsValid() {
+				elem.Set(existing)
+			}
+			if err := SetJSONPointer(elem.Addr().Interface(), jsonPointer(tokens[i+1:]), value); err != nil {
+				return fmt.Errorf("JSON pointer %q: %s", ptr, err)
+			}
+			cur.SetMapIndex(key, elem)
+			return nil
+		default:
+			return fmt.Errorf("JSON pointer %q: cannot resolve %q in %s", ptr, tok, cur.Type())
+		}
+	}
+	if err := assignJSONValue(cur, value); err != nil {
+		return fmt.Errorf("JSON pointer %q: %s", ptr, err)
+	}
+	return nil
+}
+
+// parseJSONPointer returns the unescaped reference tokens of the given JSON pointer.
+func parseJSONPointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q, must start with /", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, tok := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(tok, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// jsonPointer builds the JSON pointer made of the given reference tokens.
+func jsonPointer(tokens []string) string {
+	var ptr string
+	for _, tok := range tokens {
+		ptr += "/" + strings.Replace(strings.Replace(tok, "~", "~0", -1), "/", "~1", -1)
+	}
+	return ptr
+}
+
+// jsonField returns the field of the struct value v whose JSON name is name. The fields of the
+// structs embedded without a JSON name are searched after the fields of v, like encoding/json
+// does. A nil embedded struct pointer is allocated if it holds the field.
+func jsonField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	var embedded []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fname := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.Anonymous && fname == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, i)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		if fname == "-" {
+			continue
+		}
+		if fname == "" {
+			fname = f.Name
+		}
+		if fname == name {
+			return v.Field(i), true
+		}
+	}
+	for _, i := range embedded {
+		ev := v.Field(i)
+		if ev.Kind() != reflect.Ptr {
+			if f, ok := jsonField(ev, name); ok {
+				return f, true
+			}
+			continue
+		}
+		if ev.IsNil() {
+			if _, ok := jsonField(reflect.New(ev.Type().Elem()).Elem(), name); !ok || !ev.CanSet() {
+				continue
+			}
+			ev.Set(reflect.New(ev.Type().Elem()))
+		}
+		if f, ok := jsonField(ev.Elem(), name); ok {
+			return f, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// assignJSONValue sets dst to value after checking that value is compatible with dst.
+func assignJSONValue(dst reflect.Value, value interface{}) error {
+	if value == nil {
+		switch dst.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+		return fmt.Errorf("cannot assign null to %s", dst.Type())
+	}
+	src := reflect.ValueOf(value)
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+	if dst.Kind() == reflect.Ptr {
+		p := reflect.New(dst.Type().Elem())
+		if err := assignJSONValue(p.Elem(), value); err != nil {
+			return err
+		}
+		dst.Set(p)
+		return nil
+	}
+	if isNumberKind(src.Kind()) && isNumberKind(dst.Kind()) {
+		conv := src.Convert(dst.Type())
+		if conv.Convert(src.Type()).Interface() != src.Interface() {
+			return fmt.Errorf("cannot assign %v to %s without loss", value, dst.Type())
+		}
+		dst.Set(conv)
+		return nil
+	}
+	return fmt.Errorf("cannot assign value of type %s to %s", src.Type(), dst.Type())
+}
+
+// isNumberKind returns true if k is the kind of an integer or floating point number.
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
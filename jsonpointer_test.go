@@ -0,0 +1,165 @@
+package goa_test
+
+import (
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SetJSONPointer", func() {
+	type address struct {
+		City *string `json:"city,omitempty"`
+	}
+	type item struct {
+		Qty int `json:"qty"`
+	}
+	type order struct {
+		Address *address          `json:"address,omitempty"`
+		Items   []*item           `json:"items,omitempty"`
+		Labels  map[string]string `json:"labels,omitempty"`
+		Note    string            `json:"a/b~c"`
+	}
+
+	var target *order
+	var ptr string
+	var value interface{}
+	var err error
+
+	BeforeEach(func() {
+		target = &order{Items: []*item{{Qty: 1}, {Qty: 2}}}
+	})
+
+	JustBeforeEach(func() {
+		err = goa.SetJSONPointer(target, ptr, value)
+	})
+
+	Context("with a pointer to a nested field", func() {
+		BeforeEach(func() {
+			ptr = "/address/city"
+			value = "Paris"
+		})
+
+		It("allocates the parent and sets the field", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(target.Address).ShouldNot(BeNil())
+			Ω(*target.Address.City).Should(Equal("Paris"))
+		})
+	})
+
+	Context("with a pointer to an array element field", func() {
+		BeforeEach(func() {
+			ptr = "/items/1/qty"
+			value = float64(5)
+		})
+
+		It("converts the number and sets the field", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(target.Items[1].Qty).Should(Equal(5))
+		})
+	})
+
+	Context("with an out of range array index", func() {
+		BeforeEach(func() {
+			ptr = "/items/2/qty"
+			value = 5
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("out of range"))
+		})
+	})
+
+	Context("with a pointer to a map entry", func() {
+		BeforeEach(func() {
+			ptr = "/labels/env"
+			value = "prod"
+		})
+
+		It("sets the entry", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(target.Labels).Should(Equal(map[string]string{"env": "prod"}))
+		})
+	})
+
+	Context("with escaped reference tokens", func() {
+		BeforeEach(func() {
+			ptr = "/a~1b~0c"
+			value = "note"
+		})
+
+		It("unescapes the tokens", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(target.Note).Should(Equal("note"))
+		})
+	})
+
+	Context("with a value of the wrong type", func() {
+		BeforeEach(func() {
+			ptr = "/items/0/qty"
+			value = "five"
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a lossy number conversion", func() {
+		BeforeEach(func() {
+			ptr = "/items/0/qty"
+			value = 1.5
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a field of an embedded struct", func() {
+		type base struct {
+			Kind string `json:"kind"`
+		}
+		type Audit struct {
+			Author *string `json:"author,omitempty"`
+		}
+		type derived struct {
+			base
+			*Audit
+			Name string `json:"name"`
+		}
+		var d *derived
+
+		BeforeEach(func() {
+			d = &derived{}
+		})
+
+		It("sets the promoted field", func() {
+			Ω(goa.SetJSONPointer(d, "/kind", "dog")).ShouldNot(HaveOccurred())
+			Ω(d.Kind).Should(Equal("dog"))
+			Ω(d.Audit).Should(BeNil())
+		})
+
+		It("allocates the embedded struct pointer", func() {
+			Ω(goa.SetJSONPointer(d, "/author", "ann")).ShouldNot(HaveOccurred())
+			Ω(d.Audit).ShouldNot(BeNil())
+			Ω(*d.Author).Should(Equal("ann"))
+		})
+
+		It("does not allocate the embedded struct pointer for unknown fields", func() {
+			Ω(goa.SetJSONPointer(d, "/unknown", "x")).Should(HaveOccurred())
+			Ω(d.Audit).Should(BeNil())
+		})
+	})
+
+	Context("with an unknown field", func() {
+		BeforeEach(func() {
+			ptr = "/unknown"
+			value = 1
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -3,6 +3,8 @@ package goa
 import (
 	"net/http"
 	"net/url"
+	"regexp"
+	"strings"
 
 	"github.com/dimfeld/httptreemux"
 )
@@ -39,6 +41,30 @@ type (
 	}
 )
 
+// routeParamRegex captures the named (":id") and catch-all ("*path") parameters of route paths.
+var routeParamRegex = regexp.MustCompile(`/(?::|\*)([a-zA-Z0-9_]+)`)
+
+// RoutePath returns the request path obtained by replacing the parameters of the given route path,
+// e.g. "/users/:id" or "/files/*filepath", with the given values in order. The values are
+// URL-escaped: the "/" character is escaped for named parameters but not for catch-all
+// parameters. Parameters that have no corresponding value are left unchanged.
+// goagen generates path constructors that call RoutePath with the route path constants of the
+// actions.
+func RoutePath(route string, values ...string) string {
+	i := 0
+	return routeParamRegex.ReplaceAllStringFunc(route, func(param string) string {
+		if i >= len(values) {
+			return param
+		}
+		v := values[i]
+		i++
+		if param[1] == '*' {
+			return "/" + (&url.URL{Path: v}).EscapedPath()
+		}
+		return "/" + strings.Replace(url.QueryEscape(v), "+", "%20", -1)
+	})
+}
+
 // NewMux returns a Mux.
 func NewMux() ServeMux {
 	r := httptreemux.New()
@@ -67,3 +67,21 @@ var _ = Describe("Mux", func() {
 	})
 
 })
+
+var _ = Describe("RoutePath", func() {
+	It("escapes the values of named parameters", func() {
+		Ω(goa.RoutePath("/users/:id/posts/:postID", "a b/c", "100%+?")).Should(Equal("/users/a%20b%2Fc/posts/100%25%2B%3F"))
+	})
+
+	It("keeps the slashes of catch-all parameter values", func() {
+		Ω(goa.RoutePath("/files/*filepath", "docs/a b/100%.txt")).Should(Equal("/files/docs/a%20b/100%25.txt"))
+	})
+
+	It("keeps the literal percent characters of the route", func() {
+		Ω(goa.RoutePath("/rates/100%/:id", "a%b")).Should(Equal("/rates/100%/a%25b"))
+	})
+
+	It("leaves the parameters with no value unchanged", func() {
+		Ω(goa.RoutePath("/users/:id/posts/:postID", "1")).Should(Equal("/users/1/posts/:postID"))
+	})
+})
@@ -0,0 +1,110 @@
+package goa
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// The Null types wrap the database/sql Null types so that they can be scanned directly from
+// database rows while being encoded as their value in JSON or as null when not valid.
+// goagen uses these types for optional attributes that define the "struct:field:sqlnull" metadata.
+type (
+	// NullString is a sql.NullString encoded as a JSON string or null.
+	NullString struct{ sql.NullString }
+
+	// NullInt64 is a sql.NullInt64 encoded as a JSON number or null.
+	NullInt64 struct{ sql.NullInt64 }
+
+	// NullFloat64 is a sql.NullFloat64 encoded as a JSON number or null.
+	NullFloat64 struct{ sql.NullFloat64 }
+
+	// NullBool is a sql.NullBool encoded as a JSON boolean or null.
+	NullBool struct{ sql.NullBool }
+)
+
+// jsonNull is the JSON encoding of invalid Null values.
+var jsonNull = []byte("null")
+
+// MarshalJSON encodes the string value or null if the value is not valid.
+func (n NullString) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(n.String)
+}
+
+// UnmarshalJSON decodes a JSON string or null.
+func (n *NullString) UnmarshalJSON(data []byte) error {
+	var v *string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = NullString{}
+	if v != nil {
+		n.String, n.Valid = *v, true
+	}
+	return nil
+}
+
+// MarshalJSON encodes the integer value or null if the value is not valid.
+func (n NullInt64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(n.Int64)
+}
+
+// UnmarshalJSON decodes a JSON number or null.
+func (n *NullInt64) UnmarshalJSON(data []byte) error {
+	var v *int64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = NullInt64{}
+	if v != nil {
+		n.Int64, n.Valid = *v, true
+	}
+	return nil
+}
+
+// MarshalJSON encodes the number value or null if the value is not valid.
+func (n NullFloat64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(n.Float64)
+}
+
+// UnmarshalJSON decodes a JSON number or null.
+func (n *NullFloat64) UnmarshalJSON(data []byte) error {
+	var v *float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = NullFloat64{}
+	if v != nil {
+		n.Float64, n.Valid = *v, true
+	}
+	return nil
+}
+
+// MarshalJSON encodes the boolean value or null if the value is not valid.
+func (n NullBool) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(n.Bool)
+}
+
+// UnmarshalJSON decodes a JSON boolean or null.
+func (n *NullBool) UnmarshalJSON(data []byte) error {
+	var v *bool
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = NullBool{}
+	if v != nil {
+		n.Bool, n.Valid = *v, true
+	}
+	return nil
+}
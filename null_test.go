@@ -0,0 +1,72 @@
+package goa_test
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Null types", func() {
+	type record struct {
+		S goa.NullString  `json:"s"`
+		I goa.NullInt64   `json:"i"`
+		F goa.NullFloat64 `json:"f"`
+		B goa.NullBool    `json:"b"`
+	}
+
+	Context("with valid values", func() {
+		rec := record{
+			S: goa.NullString{NullString: sql.NullString{String: "foo", Valid: true}},
+			I: goa.NullInt64{NullInt64: sql.NullInt64{Int64: 42, Valid: true}},
+			F: goa.NullFloat64{NullFloat64: sql.NullFloat64{Float64: 4.2, Valid: true}},
+			B: goa.NullBool{NullBool: sql.NullBool{Bool: true, Valid: true}},
+		}
+
+		It("encodes the values", func() {
+			b, err := json.Marshal(rec)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal(`{"s":"foo","i":42,"f":4.2,"b":true}`))
+		})
+
+		It("round trips", func() {
+			b, err := json.Marshal(rec)
+			Ω(err).ShouldNot(HaveOccurred())
+			var decoded record
+			Ω(json.Unmarshal(b, &decoded)).ShouldNot(HaveOccurred())
+			Ω(decoded).Should(Equal(rec))
+		})
+	})
+
+	Context("with invalid values", func() {
+		var rec record
+
+		It("encodes null", func() {
+			b, err := json.Marshal(rec)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal(`{"s":null,"i":null,"f":null,"b":null}`))
+		})
+
+		It("round trips", func() {
+			b, err := json.Marshal(rec)
+			Ω(err).ShouldNot(HaveOccurred())
+			decoded := record{
+				S: goa.NullString{NullString: sql.NullString{String: "foo", Valid: true}},
+				I: goa.NullInt64{NullInt64: sql.NullInt64{Int64: 1, Valid: true}},
+				F: goa.NullFloat64{NullFloat64: sql.NullFloat64{Float64: 1.5, Valid: true}},
+				B: goa.NullBool{NullBool: sql.NullBool{Bool: true, Valid: true}},
+			}
+			Ω(json.Unmarshal(b, &decoded)).ShouldNot(HaveOccurred())
+			Ω(decoded).Should(Equal(rec))
+		})
+	})
+
+	Context("with a value of the wrong type", func() {
+		It("returns an error", func() {
+			var n goa.NullInt64
+			Ω(json.Unmarshal([]byte(`"foo"`), &n)).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,63 @@
+//go:build go1.18
+// +build go1.18
+
+package goa
+
+import "encoding/json"
+
+// Optional holds a value of type T that may be absent. goagen renders the optional primitive
+// fields of the generated structs with Optional when targeting Go 1.24 or later: the generated
+// fields use the "omitzero" JSON tag option so that absent values are omitted from the encoding,
+// earlier versions ignore the option and encode them as null.
+type Optional[T any] struct {
+	value   T
+	present bool
+}
+
+// Some returns an Optional holding v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, present: true}
+}
+
+// Get returns the value held by o, the zero value of T if it is absent.
+func (o Optional[T]) Get() T {
+	return o.value
+}
+
+// Set sets the value held by o.
+func (o *Optional[T]) Set(v T) {
+	o.value = v
+	o.present = true
+}
+
+// Present returns true if o holds a value.
+func (o Optional[T]) Present() bool {
+	return o.present
+}
+
+// IsZero returns true if o is absent. It is used by the "omitzero" JSON tag option.
+func (o Optional[T]) IsZero() bool {
+	return !o.present
+}
+
+// MarshalJSON encodes the value held by o, null if it is absent.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.present {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// UnmarshalJSON decodes the value held by o, o is absent if data is null.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*o = Optional[T]{}
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	o.Set(v)
+	return nil
+}
@@ -0,0 +1,42 @@
+//go:build go1.24
+// +build go1.24
+
+package goa_test
+
+import (
+	"encoding/json"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Optional", func() {
+	type counter struct {
+		Count goa.Optional[int] `json:"count,omitzero"`
+		Name  string            `json:"name"`
+	}
+
+	It("encodes present values", func() {
+		b, err := json.Marshal(counter{Count: goa.Some(0), Name: "c"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`{"count":0,"name":"c"}`))
+	})
+
+	It("omits absent values", func() {
+		b, err := json.Marshal(counter{Name: "c"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`{"name":"c"}`))
+	})
+
+	It("decodes present and absent values", func() {
+		var present, null, absent counter
+		Ω(json.Unmarshal([]byte(`{"count":0}`), &present)).ShouldNot(HaveOccurred())
+		Ω(json.Unmarshal([]byte(`{"count":null}`), &null)).ShouldNot(HaveOccurred())
+		Ω(json.Unmarshal([]byte(`{}`), &absent)).ShouldNot(HaveOccurred())
+		Ω(present.Count.Present()).Should(BeTrue())
+		Ω(present.Count.Get()).Should(Equal(0))
+		Ω(null.Count.Present()).Should(BeFalse())
+		Ω(absent.Count.Present()).Should(BeFalse())
+	})
+})
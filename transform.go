@@ -0,0 +1,33 @@
+package goa
+
+import (
+	"fmt"
+	"sync"
+)
+
+// transforms records the custom transform functions indexed by name.
+var transforms = make(map[string]func(string) string)
+
+// transformsLock is the mutex used to access transforms.
+var transformsLock = &sync.RWMutex{}
+
+// RegisterTransform registers a custom transform function under the given name. The code generated
+// for the attributes that use the transform name in their "struct:field:transform" metadata applies
+// the function to the decoded values. Registering a function under an existing name replaces it.
+func RegisterTransform(name string, fn func(string) string) {
+	transformsLock.Lock()
+	transforms[name] = fn
+	transformsLock.Unlock()
+}
+
+// ApplyTransform applies the custom transform function registered under the given name to val.
+// It returns an error if no function is registered under the name.
+func ApplyTransform(name string, val string) (string, error) {
+	transformsLock.RLock()
+	fn, ok := transforms[name]
+	transformsLock.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("goa: no transform registered under name %#v", name)
+	}
+	return fn(val), nil
+}
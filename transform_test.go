@@ -0,0 +1,32 @@
+package goa_test
+
+import (
+	"strings"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ApplyTransform", func() {
+	Context("with a registered transform", func() {
+		BeforeEach(func() {
+			goa.RegisterTransform("slug", func(s string) string {
+				return strings.Replace(strings.ToLower(s), " ", "-", -1)
+			})
+		})
+
+		It("applies the transform", func() {
+			v, err := goa.ApplyTransform("slug", "Hello World")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(v).Should(Equal("hello-world"))
+		})
+	})
+
+	Context("with an unknown transform", func() {
+		It("returns an error", func() {
+			_, err := goa.ApplyTransform("unknown", "foo")
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
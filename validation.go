@@ -48,6 +48,9 @@ const (
 
 	// FormatRegexp Regexp defines regular expression syntax accepted by RE2.
 	FormatRegexp = "regexp"
+
+	// FormatColor defines hexadecimal RGB or RGBA color values such as "#1f2e3d".
+	FormatColor = "color"
 )
 
 var (
@@ -56,6 +59,9 @@ var (
 
 	// Simple regular expression for IPv4 values, more rigorous checking is done via net.ParseIP
 	ipv4Regex = regexp.MustCompile(`^(?:[0-9]{1,3}\.){3}[0-9]{1,3}$`)
+
+	// Regular expression used to validate hexadecimal #RGB, #RGBA, #RRGGBB and #RRGGBBAA colors
+	colorRegex = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
 )
 
 // ValidateFormat validates a string against a standard format.
@@ -72,6 +78,7 @@ var (
 //     - "mac": IEEE 802 MAC-48, EUI-48 or EUI-64 MAC address value
 //     - "cidr": RFC4632 and RFC4291 CIDR notation IP address value
 //     - "regexp": Regular expression syntax accepted by RE2
+//     - "color": Hexadecimal #RGB, #RGBA, #RRGGBB or #RRGGBBAA color value
 func ValidateFormat(f Format, val string) error {
 	var err error
 	switch f {
@@ -109,6 +116,11 @@ func ValidateFormat(f Format, val string) error {
 		_, _, err = net.ParseCIDR(val)
 	case FormatRegexp:
 		_, err = regexp.Compile(val)
+	case FormatColor:
+		if !colorRegex.MatchString(val) {
+			err = fmt.Errorf("color value '%s' does not match %s",
+				val, colorRegex.String())
+		}
 	default:
 		return fmt.Errorf("unknown format %#v", f)
 	}
@@ -316,6 +316,53 @@ var _ = Describe("ValidateFormat", func() {
 
 	})
 
+	Context("Color", func() {
+		BeforeEach(func() {
+			f = goa.FormatColor
+		})
+
+		Context("with an invalid value", func() {
+			BeforeEach(func() {
+				val = "#12345g"
+			})
+
+			It("does not validate", func() {
+				Ω(valErr).Should(HaveOccurred())
+			})
+		})
+
+		Context("with a value missing the leading #", func() {
+			BeforeEach(func() {
+				val = "1f2e3d"
+			})
+
+			It("does not validate", func() {
+				Ω(valErr).Should(HaveOccurred())
+			})
+		})
+
+		Context("with a valid value", func() {
+			BeforeEach(func() {
+				val = "#1F2e3d"
+			})
+
+			It("validates", func() {
+				Ω(valErr).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with a valid short value with alpha", func() {
+			BeforeEach(func() {
+				val = "#fa08"
+			})
+
+			It("validates", func() {
+				Ω(valErr).ShouldNot(HaveOccurred())
+			})
+		})
+
+	})
+
 	Context("Regexp", func() {
 		BeforeEach(func() {
 			f = goa.FormatRegexp